@@ -0,0 +1,70 @@
+// Command audit checks the hash chain of the audit log.
+//
+//	go run ./cmd/audit -verify                       # walk the whole chain
+//	go run ./cmd/audit -verify -anchor 3f9c…e1       # and require an earlier head to be in it
+//
+// It prints the number of entries and the head hash. Keep the head hash somewhere the
+// database's operators cannot change (the council minutes, say): deleting the newest
+// entries leaves a valid chain, but a later -anchor check with that hash will fail.
+// Exits 1 when the chain is broken. Reads DATABASE_URL like the server does.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"village_project/internal/audit"
+	"village_project/internal/config"
+	"village_project/internal/database"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+)
+
+func main() {
+	verify := flag.Bool("verify", false, "Verify the audit log's hash chain")
+	anchor := flag.String("anchor", "", "A head hash recorded earlier; it must be in the chain")
+	flag.Parse()
+	if !*verify {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("FATAL: Could not load configuration: %v", err)
+	}
+	dbPool, err := database.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Could not connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	var verifier audit.Verifier
+	var chainErr error
+	anchorFound := *anchor == ""
+	_, err = repository.NewAuditRepository(dbPool).Walk(context.Background(), func(e models.AuditEntry) error {
+		if chainErr = verifier.Check(e); chainErr != nil {
+			return chainErr
+		}
+		anchorFound = anchorFound || e.Hash == *anchor
+		return nil
+	})
+	if err != nil && !errors.Is(err, chainErr) {
+		log.Fatalf("FATAL: Could not read the audit log: %v", err)
+	}
+
+	fmt.Printf("%d entries verified, head %s\n", verifier.Checked, verifier.Head)
+	switch {
+	case chainErr != nil:
+		fmt.Printf("BROKEN: %v\n", chainErr)
+		os.Exit(1)
+	case !anchorFound:
+		fmt.Printf("BROKEN: anchor %s is not in the chain (entries were removed or rewritten)\n", *anchor)
+		os.Exit(1)
+	}
+	fmt.Println("OK")
+}
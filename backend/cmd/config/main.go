@@ -0,0 +1,50 @@
+// Command config shows the configuration the server would run with.
+//
+//	go run ./cmd/config print                     # effective values, secrets masked
+//	go run ./cmd/config print -profile prod       # as with APP_PROFILE=prod
+//	go run ./cmd/config print -dir /etc/village   # read config.yaml and .env from there
+//
+// Each line is KEY=value followed by where the value came from: a default, config.yaml,
+// config.<profile>.yaml, .env, the environment, or a secret file named by <KEY>_FILE.
+// Secrets show as xxxxx and connection strings without their password. Exits 1, after
+// listing every problem, when the configuration is invalid.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"village_project/internal/config"
+)
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: config print [-profile dev|staging|prod] [-dir path]")
+		os.Exit(2)
+	}
+	flags := flag.NewFlagSet("print", flag.ExitOnError)
+	profile := flags.String("profile", "", "Profile to show (default: APP_PROFILE, else dev)")
+	dir := flags.String("dir", ".", "Directory holding config.yaml/.toml and .env")
+	flags.Parse(os.Args[2:])
+
+	_, settings, err := config.Load(config.Options{Dir: *dir, Profile: *profile})
+	var invalid *config.ValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		log.Fatalf("FATAL: Could not load configuration: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, s := range settings {
+		fmt.Fprintf(w, "%s=%s\t# %s\n", s.Key, s.Value, s.Source)
+	}
+	w.Flush()
+
+	if invalid != nil {
+		fmt.Fprintf(os.Stderr, "\n%v\n", invalid)
+		os.Exit(1)
+	}
+}
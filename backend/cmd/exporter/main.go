@@ -0,0 +1,97 @@
+// Command exporter writes jobs or news to a file for archives and annual reports, like
+// GET /api/v1/export/{jobs,news}. Rows are streamed from the database, so the size of
+// the export does not matter.
+//
+//	go run ./cmd/exporter -kind news -from 2024-04-01 -to 2025-03-31 -status published -out news.csv
+//	go run ./cmd/exporter -kind jobs -format ndjson > jobs.ndjson
+//
+// Dates are IST and -to includes that day. It reads DATABASE_URL (and DATABASE_READ_URL,
+// if set) like the server does.
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"village_project/internal/config"
+	"village_project/internal/database"
+	"village_project/internal/export"
+	"village_project/internal/repository"
+)
+
+func main() {
+	kind := flag.String("kind", "", "What to export: jobs or news")
+	format := flag.String("format", export.FormatCSV, "csv, json or ndjson")
+	from := flag.String("from", "", "Start date (YYYY-MM-DD, IST): created_at for jobs, published_at for news")
+	to := flag.String("to", "", "End date, inclusive (YYYY-MM-DD, IST)")
+	status := flag.String("status", "", "Only rows with this status")
+	out := flag.String("out", "", "Output file (default stdout)")
+	flag.Parse()
+
+	if *kind != "jobs" && *kind != "news" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var filter repository.ExportFilter
+	var err error
+	if filter.From, err = export.ParseDate(*from, false); err == nil {
+		filter.To, err = export.ParseDate(*to, true)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: %v", err)
+	}
+	filter.Status = *status
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("FATAL: Could not load configuration: %v", err)
+	}
+	dbPool, err := database.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Could not connect to database: %v", err)
+	}
+	defer dbPool.Close()
+	pools, err := database.NewPools(cfg, dbPool)
+	if err != nil {
+		log.Fatalf("FATAL: Could not set up database pools: %v", err)
+	}
+	defer pools.Close()
+
+	var dest io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatalf("FATAL: Could not create output file: %v", err)
+		}
+		defer f.Close()
+		dest = f
+	}
+
+	ctx := context.Background()
+	var count int
+	switch *kind {
+	case "jobs":
+		w, werr := export.NewJobWriter(dest, *format)
+		if werr != nil {
+			log.Fatalf("FATAL: %v", werr)
+		}
+		if count, err = repository.NewJobRepository(pools, nil).ExportJobs(ctx, filter, w.Write); err == nil {
+			err = w.Close()
+		}
+	case "news":
+		w, werr := export.NewNewsWriter(dest, *format)
+		if werr != nil {
+			log.Fatalf("FATAL: %v", werr)
+		}
+		if count, err = repository.NewNewsRepository(pools, nil).ExportNews(ctx, filter, w.Write); err == nil {
+			err = w.Close()
+		}
+	}
+	if err != nil {
+		log.Fatalf("FATAL: Export stopped after %d rows: %v", count, err)
+	}
+	log.Printf("Exported %d %s", count, *kind)
+}
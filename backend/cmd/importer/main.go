@@ -0,0 +1,121 @@
+// Command importer bulk-loads jobs or news from a CSV (UTF-8) or XLSX spreadsheet, like
+// POST /api/v1/import/{jobs,news} but without the upload.
+//
+//	go run ./cmd/importer -kind jobs -file jobs.xlsx -dry-run        # report problems, save nothing
+//	go run ./cmd/importer -kind news -file news.csv                  # all rows or none
+//	go run ./cmd/importer -kind jobs -file jobs.csv -mode partial -result result.csv
+//
+// It reads DATABASE_URL like the server does and always writes to the primary. Imported
+// jobs have no poster. Exits 1 when rows were invalid or failed, or nothing was saved.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"village_project/internal/config"
+	"village_project/internal/database"
+	"village_project/internal/i18n"
+	"village_project/internal/importer"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+)
+
+func main() {
+	kind := flag.String("kind", "", "What the file contains: jobs or news")
+	file := flag.String("file", "", "CSV or XLSX file to import")
+	dryRun := flag.Bool("dry-run", false, "Validate every row and report problems without saving")
+	mode := flag.String("mode", models.ImportModeAll, "all (any bad row saves nothing) or partial (save the good rows)")
+	result := flag.String("result", "", "Write the per-row result (line, status, id, errors) to this CSV file")
+	flag.Parse()
+
+	if (*kind != "jobs" && *kind != "news") || *file == "" ||
+		(*mode != models.ImportModeAll && *mode != models.ImportModePartial) {
+		flag.Usage()
+		fmt.Fprintf(os.Stderr, "\njobs columns: %s\nnews columns: %s\n",
+			strings.Join(importer.Columns("jobs"), ", "), strings.Join(importer.Columns("news"), ", "))
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("FATAL: Could not open file: %v", err)
+	}
+	table, err := importer.ReadTable(*file, f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("FATAL: Could not read %s: %v", *file, err)
+	}
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("FATAL: Could not load configuration: %v", err)
+	}
+	cfg.DatabaseReadURL = "" // Imports only write
+	dbPool, err := database.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Could not connect to database: %v", err)
+	}
+	defer dbPool.Close()
+	pools, err := database.NewPools(cfg, dbPool)
+	if err != nil {
+		log.Fatalf("FATAL: Could not set up database pools: %v", err)
+	}
+	locales := i18n.NewNegotiator(cfg.SupportedLocales, cfg.FallbackLocale)
+
+	// Servers drop their cached lists on the database's change notification
+	ctx := context.Background()
+	var report models.ImportReport
+	switch *kind {
+	case "jobs":
+		rows, parseErr := importer.ParseJobs(table, locales)
+		if parseErr != nil {
+			log.Fatalf("FATAL: %s: %v", *file, parseErr)
+		}
+		report, err = repository.NewJobRepository(pools, nil).ImportJobs(ctx, rows, *mode, *dryRun, nil)
+	case "news":
+		rows, parseErr := importer.ParseNews(table, locales)
+		if parseErr != nil {
+			log.Fatalf("FATAL: %s: %v", *file, parseErr)
+		}
+		report, err = repository.NewNewsRepository(pools, nil).ImportNews(ctx, rows, *mode, *dryRun)
+	}
+	if err != nil {
+		log.Fatalf("FATAL: Import failed, nothing was saved: %v", err)
+	}
+
+	for _, row := range report.Rows {
+		if len(row.Errors) > 0 {
+			fmt.Printf("line %d: %s: %s\n", row.Line, row.Status, strings.Join(row.Errors, "; "))
+		}
+	}
+	if *result != "" {
+		out, err := os.Create(*result)
+		if err != nil {
+			log.Fatalf("FATAL: Could not create result file: %v", err)
+		}
+		if err := importer.WriteResultCSV(out, report); err != nil {
+			log.Fatalf("FATAL: Could not write result file: %v", err)
+		}
+		if err := out.Close(); err != nil {
+			log.Fatalf("FATAL: Could not write result file: %v", err)
+		}
+	}
+
+	outcome := "saved"
+	switch {
+	case report.DryRun:
+		outcome = "dry run, nothing saved"
+	case !report.Committed:
+		outcome = "aborted, nothing saved"
+	}
+	fmt.Printf("%s: %d rows, %d created, %d invalid, %d failed, %d skipped (%s)\n",
+		report.Kind, report.Total, report.Created, report.Invalid, report.Failed, report.Skipped, outcome)
+	if report.Invalid+report.Failed > 0 || (!report.DryRun && !report.Committed) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,69 @@
+// Command searchindex maintains and inspects the Telugu-aware search index. The golden
+// corpus runs with go test ./internal/search.
+//
+//	go run ./cmd/searchindex -explain "వరి కోత pani"  # show tokens, keys and synonyms
+//	go run ./cmd/searchindex -reindex                # recompute search_terms for all jobs and news
+//
+// -reindex reads DATABASE_URL like the server does. Run it after applying the migration
+// that adds search_terms and after changing the synonym dictionary.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"village_project/internal/config"
+	"village_project/internal/database"
+	"village_project/internal/repository"
+	"village_project/internal/search"
+)
+
+func main() {
+	explain := flag.String("explain", "", "Print how a text is tokenized, keyed and expanded")
+	reindex := flag.Bool("reindex", false, "Recompute search_terms for every job and news item")
+	flag.Parse()
+
+	switch {
+	case *explain != "":
+		runExplain(*explain)
+	case *reindex:
+		runReindex()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func runExplain(text string) {
+	fmt.Printf("normalized: %s\n", search.Normalize(text))
+	for _, tok := range search.Tokenize(text) {
+		var matches []string
+		for _, group := range search.QueryTerms(tok) {
+			matches = append(matches, group...)
+		}
+		fmt.Printf("%-20s latin=%-20s key=%-16s matches=%s\n", tok, search.ToLatin(tok), search.Key(tok), strings.Join(matches, ","))
+	}
+	fmt.Printf("terms: %s\n", strings.Join(search.Terms(text), " "))
+}
+
+func runReindex() {
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		log.Fatalf("FATAL: Could not load configuration: %v", err)
+	}
+	dbPool, err := database.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("FATAL: Could not connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	jobs, news, err := repository.ReindexSearchTerms(context.Background(), dbPool)
+	if err != nil {
+		log.Fatalf("FATAL: Reindex stopped after %d jobs and %d news items: %v", jobs, news, err)
+	}
+	log.Printf("Reindexed %d jobs and %d news items", jobs, news)
+}
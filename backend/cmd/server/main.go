@@ -15,10 +15,20 @@ import (
 
 	// Ensure pgxpool is imported if needed directly (like in health check)
 	// Adjust import paths based on your go.mod module name
+	"village_project/internal/audit"
+	"village_project/internal/cache"
 	"village_project/internal/config"
 	"village_project/internal/database"
-	"village_project/internal/handlers"   // Import handlers
+	"village_project/internal/handlers" // Import handlers
+	"village_project/internal/i18n"
+	"village_project/internal/logging"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+	"village_project/internal/notify"
+	"village_project/internal/outbox"
 	"village_project/internal/repository" // Import repository
+	"village_project/internal/stream"
+	"village_project/internal/webhooks"
 )
 
 func main() {
@@ -33,6 +43,13 @@ func main() {
 	log.Printf("CORS Origins: %s", cfg.CorsAllowedOrigins)
 	// Add other config logs if needed
 
+	// Live settings (CORS origins, rate limits, feature flags, log level) are re-read on
+	// SIGHUP or when a config file changes; everything else needs a restart
+	reloader := config.NewReloader(config.Options{Dir: "."}, cfg)
+	log.SetOutput(logging.Writer(os.Stderr))
+	logging.SetLevel(cfg.LogLevel)
+	reloader.OnReload(func(live config.Config) { logging.SetLevel(live.LogLevel) })
+
 	// --- Connect to Database ---
 	dbPool, err := database.ConnectDB(cfg)
 	if err != nil {
@@ -43,6 +60,12 @@ func main() {
 		dbPool.Close()
 	}()
 	log.Println("Database pool initialized.")
+	// Optional read replica for list endpoints (DATABASE_READ_URL)
+	dbPools, err := database.NewPools(cfg, dbPool)
+	if err != nil {
+		log.Fatalf("FATAL: Could not set up read replica: %v", err)
+	}
+	defer dbPools.Close()
 
 	// --- Setup Gin Router ---
 	// Consider setting ReleaseMode based on GIN_MODE env var
@@ -53,27 +76,138 @@ func main() {
 		log.Println("Running in debug mode")
 	}
 	router := gin.Default() // Includes logger and recovery middleware
+	// Only X-Forwarded-For set by our own load balancers is believed; otherwise any client
+	// could pick its IP for the rate limit and the audit log
+	if err := router.SetTrustedProxies(cfg.TrustedProxyList()); err != nil {
+		log.Fatalf("FATAL: Invalid TRUSTED_PROXIES: %v", err)
+	}
 
 	// --- CORS Middleware ---
 	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOrigins = strings.Split(cfg.CorsAllowedOrigins, ",")
+	// Asked per request so reloaded origins apply at once
+	corsConfig.AllowOriginFunc = func(origin string) bool { return reloader.Current().AllowsOrigin(origin) }
 	corsConfig.AllowCredentials = true
 	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "Authorization", "Content-Type") // Ensure Content-Type is allowed for POST
+	// Let fetch() send conditional GETs and read the validators
+	corsConfig.AllowHeaders = append(corsConfig.AllowHeaders, "If-None-Match", "If-Modified-Since")
+	corsConfig.ExposeHeaders = []string{"ETag", "Last-Modified", "Content-Language", "X-Request-ID"}
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
 	router.Use(cors.New(corsConfig))
 	log.Println("CORS middleware configured.")
 
+	// Request IDs tie log lines and audit entries to a request
+	router.Use(middleware.AssignRequestID())
+	// Per-IP rate limit and switched-off features, both from the live settings
+	router.Use(middleware.RateLimit(func() middleware.RateLimits {
+		live := reloader.Current()
+		return middleware.RateLimits{PerMinute: live.RateLimitPerMinute, Burst: live.RateLimitBurst}
+	}))
+	router.Use(middleware.FeatureGate(map[string]string{
+		"/api/v1/polls":      "polls",
+		"/api/v1/grievances": "grievances",
+		"/api/v1/push":       "push",
+		"/api/v1/stream":     "stream",
+		"/api/v1/import":     "import",
+		"/api/v1/export":     "export",
+	}, func(feature string) bool { return reloader.Current().FeatureEnabled(feature) }))
+
+	// --- Auth Middleware ---
+	// Identifies Supabase users when a bearer token is present; anonymous requests pass through.
+	router.Use(middleware.Authenticate(cfg.SupabaseJWTSecret))
+	// Writers read from the primary for a short window so they see their own changes
+	router.Use(middleware.ReadYourWrites(time.Duration(cfg.DatabaseReadYourWritesSeconds) * time.Second))
+	// Every successful change is written to the hash-chained audit log
+	auditRepo := repository.NewAuditRepository(dbPool)
+	router.Use(audit.Record(auditRepo))
+
 	// --- Instantiate Repositories and Handlers ---
+	// Read cache for news and jobs; writes clear it here and NOTIFY clears it elsewhere
+	cacheRegistry := cache.NewRegistry(cfg.CacheEnabled, time.Duration(cfg.CacheTTLSeconds)*time.Second, cfg.CacheMaxEntries)
+	newsCache, jobsCache := cacheRegistry.Cache("news"), cacheRegistry.Cache("jobs")
+	cacheHandler := handlers.NewCacheHandler(cacheRegistry)
+	cacheRegistry.OnInvalidate = dbPools.NoteWrite // Don't refill that collection from a lagging replica
+	// Translations and language negotiation shared by news and jobs
+	locales := i18n.NewNegotiator(cfg.SupportedLocales, cfg.FallbackLocale)
+	translationRepo := repository.NewTranslationRepository(dbPools, newsCache, jobsCache)
+	translationHandler := handlers.NewTranslationHandler(translationRepo, locales)
+	localizer := handlers.NewLocalizer(translationRepo, locales)
+
 	// Pass the dbPool to the repository constructor
-	newsRepo := repository.NewNewsRepository(dbPool)
+	newsRepo := repository.NewNewsRepository(dbPools, newsCache)
 	// Pass the repository to the handler constructor
-	newsHandler := handlers.NewNewsHandler(newsRepo)
+	newsHandler := handlers.NewNewsHandler(newsRepo, localizer,
+		handlers.CachePolicy{List: cfg.CacheControlNewsList, Item: cfg.CacheControlNewsItem})
+
+	// --- Outbound webhooks ---
+	webhookRepo := repository.NewWebhookRepository(dbPool)
+	webhookDispatcher := webhooks.NewDispatcher(cfg, webhookRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo, webhookDispatcher)
 
 	// ** Instantiate Job Repository and Handler **
-	jobRepo := repository.NewJobRepository(dbPool)
-	jobHandler := handlers.NewJobHandler(jobRepo)
+	jobRepo := repository.NewJobRepository(dbPools, jobsCache)
+	jobHandler := handlers.NewJobHandler(jobRepo, localizer,
+		handlers.CachePolicy{List: cfg.CacheControlJobsList, Item: cfg.CacheControlJobsItem})
+	jobApplicationRepo := repository.NewJobApplicationRepository(dbPool)
+	jobApplicationHandler := handlers.NewJobApplicationHandler(jobApplicationRepo, jobRepo)
+	jobAlertRepo := repository.NewJobAlertRepository(dbPool)
+	jobAlertHandler := handlers.NewJobAlertHandler(jobAlertRepo)
 	// ** ------------------------------------ **
 
+	// --- Notifications ---
+	userContactRepo := repository.NewUserContactRepository(dbPool)
+	smsRepo := repository.NewSMSRepository(dbPool)
+	pushRepo := repository.NewPushRepository(dbPool)
+	newsNotificationRepo := repository.NewNewsNotificationRepository(dbPool)
+	notifier, err := notify.NewService(cfg, userContactRepo, jobAlertRepo, jobRepo, newsNotificationRepo, smsRepo, pushRepo)
+	if err != nil {
+		log.Fatalf("FATAL: Could not set up notifications: %v", err)
+	}
+	// Background workers read from the primary: they act on rows that were just written
+	workerCtx, stopWorkers := context.WithCancel(database.WithPrimary(context.Background()))
+	defer stopWorkers()
+	go notifier.Run(workerCtx)          // Drains the job alert and news notification queues
+	go webhookDispatcher.Run(workerCtx) // Delivers and retries queued webhooks
+
+	// --- Domain events (transactional outbox) ---
+	outboxRepo := repository.NewOutboxRepository(dbPool)
+	outboxDispatcher := outbox.NewDispatcher(outboxRepo, cfg.OutboxMaxAttempts)
+	for _, eventType := range webhooks.EventTypes {
+		outboxDispatcher.Register(eventType, "webhooks", webhookDispatcher.HandleOutboxEvent)
+	}
+	outboxDispatcher.Register(models.EventNewsPublished, "notify", notifier.HandleNewsPublishedEvent)
+	go outboxDispatcher.Run(workerCtx)
+	outboxHandler := handlers.NewOutboxHandler(outboxRepo)
+
+	// --- Live updates (SSE fed by LISTEN/NOTIFY on the outbox) and cache invalidation ---
+	if strings.Contains(cfg.DatabaseListenURL, "pooler.supabase.com:6543") {
+		log.Println("Warning: DATABASE_LISTEN_URL points at the transaction pooler, which does not support LISTEN; set it to the direct or session connection")
+	}
+	streamBroker := stream.NewBroker(cfg.StreamReplaySize, cfg.StreamClientBuffer)
+	streamListener := &stream.Listener{URL: cfg.DatabaseListenURL, Broker: streamBroker, Outbox: outboxRepo}
+	go streamListener.Run(workerCtx)
+	go cacheRegistry.ListenForInvalidations(workerCtx, cfg.DatabaseListenURL)
+	go dbPools.MonitorReplica(workerCtx)
+	streamHandler := handlers.NewStreamHandler(streamBroker, time.Duration(cfg.StreamHeartbeatSeconds)*time.Second)
+
+	notificationHandler := handlers.NewNotificationHandler(notifier, smsRepo, cfg.SMSCallbackToken)
+	pushHandler := handlers.NewPushHandler(pushRepo, notifier.Push)
+	feedHandler := handlers.NewFeedHandler(newsRepo, jobRepo, cfg.PublicAppURL, cfg.CacheControlFeeds)
+	syncHandler := handlers.NewSyncHandler(repository.NewSyncRepository(dbPool), localizer)
+	importHandler := handlers.NewImportHandler(newsRepo, jobRepo, locales)
+	exportHandler := handlers.NewExportHandler(newsRepo, jobRepo)
+	trashRepo := repository.NewTrashRepository(dbPool, cacheRegistry, time.Duration(cfg.TrashRetentionDays)*24*time.Hour)
+	trashHandler := handlers.NewTrashHandler(trashRepo)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
+	go trashRepo.RunPurge(workerCtx, time.Duration(cfg.TrashPurgeIntervalMinutes)*time.Minute)
+	configHandler := handlers.NewConfigHandler(reloader)
+	go reloader.Watch(workerCtx, time.Duration(cfg.ConfigWatchSeconds)*time.Second)
+
+	grievanceRepo := repository.NewGrievanceRepository(dbPool)
+	grievanceHandler := handlers.NewGrievanceHandler(grievanceRepo, notifier)
+
+	pollRepo := repository.NewPollRepository(dbPool)
+	pollHandler := handlers.NewPollHandler(pollRepo)
+
 	// Instantiate other repos/handlers here later...
 
 	// --- Routes ---
@@ -85,28 +219,143 @@ func main() {
 		dbStatus := "OK"
 		if err != nil {
 			dbStatus = "Error"
-			log.Printf("Health check DB ping error: %v", err)
+			log.Printf("Warning: Health check DB ping error: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"status": "Error", "database": dbStatus})
 			return
 		}
+		// An unhealthy replica degrades but does not fail the check: reads fall back to the primary
+		if replica := dbPools.ReplicaStatus(); replica.Configured {
+			status := "OK"
+			if !replica.Healthy {
+				status = "Degraded"
+			}
+			c.JSON(http.StatusOK, gin.H{"status": status, "database": dbStatus, "read_replica": replica})
+			return
+		}
 		c.JSON(http.StatusOK, gin.H{"status": "OK", "database": dbStatus})
 	})
 
 	// --- API v1 Routes ---
 	apiV1 := router.Group("/api/v1") // Group API routes under /api/v1
 	{
+		// --- Live Updates ---
+		apiV1.GET("/stream", streamHandler.Stream)
+		apiV1.GET("/sync", syncHandler.Sync) // Delta sync for offline clients
+
 		// --- News Routes ---
 		apiV1.GET("/news", newsHandler.ListNews)
+		apiV1.GET("/news/feed.rss", feedHandler.NewsRSS)
+		apiV1.GET("/news/feed.atom", feedHandler.NewsAtom)
+		apiV1.GET("/news/archive", newsHandler.GetNewsArchive)
+		apiV1.GET("/news/archive/:year/:month", newsHandler.ListNewsForMonth)
 		apiV1.GET("/news/:id", newsHandler.GetNewsByID)
-		// Add POST, PUT, DELETE for news later...
+		apiV1.POST("/news", middleware.RequireRole(middleware.RoleOfficial), newsHandler.CreateNews)
+		apiV1.PUT("/news/:id/publish", middleware.RequireRole(middleware.RoleOfficial), newsHandler.PublishNews)
+		apiV1.DELETE("/news/:id", middleware.RequireRole(middleware.RoleOfficial), newsHandler.DeleteNews)
+		apiV1.GET("/news/:id/translations", translationHandler.ListNewsTranslations)
+		apiV1.PUT("/news/:id/translations/:lang", middleware.RequireRole(middleware.RoleOfficial), translationHandler.UpsertNewsTranslation)
+		apiV1.DELETE("/news/:id/translations/:lang", middleware.RequireRole(middleware.RoleOfficial), translationHandler.DeleteNewsTranslation)
 
 		// --- Job Routes ---
 		apiV1.GET("/jobs", jobHandler.ListOpenJobs)   // List open jobs
 		apiV1.GET("/jobs/:id", jobHandler.GetJobByID) // Get single job
 		apiV1.POST("/jobs", jobHandler.CreateJob)     // Create a new job
-		// Add PUT /jobs/:id, DELETE /jobs/:id later...
+		apiV1.GET("/jobs/feed.rss", feedHandler.JobsRSS)
+		apiV1.GET("/jobs/feed.atom", feedHandler.JobsAtom)
+		apiV1.PUT("/jobs/:id/status", middleware.RequireUser(), jobHandler.UpdateJobStatus)
+		apiV1.DELETE("/jobs/:id", middleware.RequireUser(), jobHandler.DeleteJob)
+		apiV1.POST("/jobs/:id/applications", jobApplicationHandler.CreateApplication)
+		apiV1.GET("/jobs/:id/applications", middleware.RequireUser(), jobApplicationHandler.ListApplications)
+		apiV1.PUT("/jobs/:id/applications/:applicationId", middleware.RequireUser(), jobApplicationHandler.UpdateApplication)
+		apiV1.GET("/jobs/:id/translations", translationHandler.ListJobTranslations)
+		apiV1.PUT("/jobs/:id/translations/:lang", middleware.RequireRole(middleware.RoleOfficial), translationHandler.UpsertJobTranslation)
+		apiV1.DELETE("/jobs/:id/translations/:lang", middleware.RequireRole(middleware.RoleOfficial), translationHandler.DeleteJobTranslation)
 		// --- End Job Routes ---
 
+		// --- Job Alert Routes (per signed-in resident) ---
+		alerts := apiV1.Group("/job-alerts", middleware.RequireUser())
+		{
+			alerts.GET("", jobAlertHandler.ListAlerts)
+			alerts.POST("", jobAlertHandler.CreateAlert)
+			alerts.GET("/notifications", jobAlertHandler.ListNotifications)
+			alerts.PUT("/:id", jobAlertHandler.UpdateAlert)
+			alerts.DELETE("/:id", jobAlertHandler.DeleteAlert)
+		}
+
+		// --- Grievance Routes ---
+		apiV1.GET("/grievances/categories", grievanceHandler.ListCategories)
+		apiV1.POST("/grievances", grievanceHandler.CreateGrievance)             // Login optional
+		apiV1.GET("/grievances/track/:ticket", grievanceHandler.TrackGrievance) // Public status lookup
+		staff := apiV1.Group("/grievances", middleware.RequireRole(middleware.RoleOfficial))
+		{
+			staff.GET("", grievanceHandler.ListGrievances)
+			staff.GET("/:id", grievanceHandler.GetGrievanceByID)
+			staff.PUT("/:id/status", grievanceHandler.UpdateGrievanceStatus)
+			staff.PUT("/:id/assign", grievanceHandler.AssignGrievance)
+			staff.POST("/:id/comments", grievanceHandler.AddGrievanceComment)
+		}
+
+		// --- Notification Routes ---
+		apiV1.POST("/notices/emergency", middleware.RequireRole(middleware.RoleOfficial), notificationHandler.SendEmergencyNotice)
+		apiV1.POST("/sms/callback/:provider", notificationHandler.SMSDeliveryCallback) // Called by the SMS gateway
+
+		// --- Web Push Routes (signed-in user or X-Device-Token) ---
+		apiV1.GET("/push/vapid-public-key", pushHandler.GetVAPIDPublicKey)
+		apiV1.POST("/push/subscriptions", pushHandler.RegisterSubscription)
+		apiV1.DELETE("/push/subscriptions", pushHandler.UnregisterSubscription)
+
+		// --- Poll Routes ---
+		apiV1.GET("/polls", pollHandler.ListPolls)
+		apiV1.GET("/polls/:id", pollHandler.GetPoll)
+		apiV1.POST("/polls/:id/votes", pollHandler.CastVote) // Auth or X-Device-Token, depending on poll mode
+		apiV1.GET("/polls/:id/results", pollHandler.GetResults)
+		pollAdmin := apiV1.Group("/polls", middleware.RequireRole(middleware.RoleOfficial))
+		{
+			pollAdmin.POST("", pollHandler.CreatePoll)
+			pollAdmin.POST("/:id/close", pollHandler.ClosePoll)
+			pollAdmin.GET("/:id/results.csv", pollHandler.ExportResultsCSV)
+		}
+
+		// --- Webhook Routes (admin only) ---
+		hooks := apiV1.Group("/webhooks", middleware.RequireRole(middleware.RoleAdmin))
+		{
+			hooks.GET("", webhookHandler.ListWebhooks)
+			hooks.POST("", webhookHandler.CreateWebhook)
+			hooks.GET("/event-types", webhookHandler.ListEventTypes)
+			hooks.POST("/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverDelivery)
+			hooks.GET("/:id", webhookHandler.GetWebhook)
+			hooks.PUT("/:id", webhookHandler.UpdateWebhook)
+			hooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			hooks.POST("/:id/ping", webhookHandler.PingWebhook)
+			hooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+		}
+
+		// --- Outbox Routes (admin only) ---
+		apiV1.GET("/outbox", middleware.RequireRole(middleware.RoleAdmin), outboxHandler.ListEvents)
+		apiV1.POST("/outbox/:id/requeue", middleware.RequireRole(middleware.RoleAdmin), outboxHandler.RequeueEvent)
+
+		// --- Read cache (admin only) ---
+		apiV1.GET("/cache/stats", middleware.RequireRole(middleware.RoleAdmin), cacheHandler.Stats)
+		apiV1.POST("/cache/flush", middleware.RequireRole(middleware.RoleAdmin), cacheHandler.Flush)
+
+		// --- Bulk import from spreadsheets (admin only) ---
+		apiV1.POST("/import/jobs", middleware.RequireRole(middleware.RoleAdmin), importHandler.ImportJobs)
+		apiV1.POST("/import/news", middleware.RequireRole(middleware.RoleAdmin), importHandler.ImportNews)
+
+		// --- Exports for archives and reports (admin only) ---
+		apiV1.GET("/export/jobs", middleware.RequireRole(middleware.RoleAdmin), exportHandler.ExportJobs)
+		apiV1.GET("/export/news", middleware.RequireRole(middleware.RoleAdmin), exportHandler.ExportNews)
+
+		// --- Trash: deleted content, restorable until purged (admin only) ---
+		apiV1.GET("/trash", middleware.RequireRole(middleware.RoleAdmin), trashHandler.ListTrash)
+		apiV1.POST("/trash/:collection/:id/restore", middleware.RequireRole(middleware.RoleAdmin), trashHandler.RestoreFromTrash)
+
+		// --- Audit log (admin only) ---
+		apiV1.GET("/audit", middleware.RequireRole(middleware.RoleAdmin), auditHandler.ListAuditLog)
+
+		// --- Configuration reloads (admin only) ---
+		apiV1.GET("/config/reloads", middleware.RequireRole(middleware.RoleAdmin), configHandler.ReloadStats)
+
 		// Register other resource routes here later (events, directory, etc.)
 	}
 	log.Println("API routes registered.")
@@ -134,6 +383,8 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopWorkers()        // Stop background workers before closing the pool
+	streamBroker.Close() // End open event streams so Shutdown does not wait on them
 
 	// Context with timeout for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) // 5 seconds to finish requests
@@ -142,6 +393,12 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	// Let notifications started by requests finish before the pool closes
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelDrain()
+	if err := notifier.Drain(drainCtx); err != nil {
+		log.Printf("Warning: Notifications still sending at shutdown were cancelled: %v", err)
+	}
 
 	log.Println("Server exiting")
 }
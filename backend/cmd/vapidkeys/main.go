@@ -0,0 +1,20 @@
+// Command vapidkeys generates a VAPID key pair for Web Push.
+// Add the output to .env (or the deployment's secrets) and restart the server.
+// Changing keys invalidates every existing push subscription.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"village_project/internal/notify"
+)
+
+func main() {
+	publicKey, privateKey, err := notify.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatalf("FATAL: Could not generate VAPID keys: %v", err)
+	}
+	fmt.Printf("VAPID_PUBLIC_KEY=%s\n", publicKey)
+	fmt.Printf("VAPID_PRIVATE_KEY=%s\n", privateKey)
+}
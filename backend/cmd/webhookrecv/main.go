@@ -0,0 +1,62 @@
+// Command webhookrecv is a local webhook receiver for development.
+// It verifies signatures, prints each delivery and can simulate failures:
+//
+//	go run ./cmd/webhookrecv -addr :9090 -secret <webhook secret>
+//	go run ./cmd/webhookrecv -fail-rate 0.5   # answer half the deliveries with 500 to exercise retries
+//
+// Register http://localhost:9090/ as a webhook URL and use the ping endpoint to test it.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"village_project/internal/webhooks"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "Listen address")
+	secret := flag.String("secret", "", "Webhook secret; signatures are not checked when empty")
+	failRate := flag.Float64("fail-rate", 0, "Fraction of deliveries to answer with 500")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, "cannot read body", http.StatusBadRequest)
+			return
+		}
+
+		event := r.Header.Get(webhooks.HeaderEvent)
+		delivery := r.Header.Get(webhooks.HeaderDelivery)
+		if *secret != "" {
+			err := webhooks.Verify(*secret, r.Header.Get(webhooks.HeaderTimestamp), r.Header.Get(webhooks.HeaderSignature), body, 5*time.Minute)
+			if err != nil {
+				log.Printf("REJECTED %s delivery=%s: %v", event, delivery, err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+		if rand.Float64() < *failRate {
+			log.Printf("FAILING  %s delivery=%s (simulated)", event, delivery)
+			http.Error(w, "simulated failure", http.StatusInternalServerError)
+			return
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("RECEIVED %s delivery=%s\n%s", event, delivery, pretty.String())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("Webhook receiver listening on %s (signature check: %v)", *addr, *secret != "")
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
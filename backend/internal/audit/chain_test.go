@@ -0,0 +1,182 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"village_project/internal/models"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"b": 1, "a": {"d": [1, 2.50], "c": null}}`, `{"a":{"c":null,"d":[1,2.50]},"b":1}`},
+		{`  "text"  `, `"text"`},
+		{`{"title":{"before":"పని","after":"కూలీ"}}`, `{"title":{"after":"కూలీ","before":"పని"}}`},
+	}
+	for _, tt := range tests {
+		got, err := Canonical(json.RawMessage(tt.in))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("Canonical(%s) = %s, %v; want %s", tt.in, got, err, tt.want)
+		}
+	}
+	for _, empty := range []string{"", "null"} {
+		if got, err := Canonical(json.RawMessage(empty)); got != nil || err != nil {
+			t.Errorf("Canonical(%q) = %s, %v; want nil", empty, got, err)
+		}
+	}
+	if _, err := Canonical(json.RawMessage(`{"a":`)); err == nil {
+		t.Error("Canonical accepted truncated JSON")
+	}
+}
+
+func testEntry() models.AuditEntry {
+	actor := "5b0a9a3e-7c1d-4f7e-9a57-0d6c1e3b2f10"
+	return models.AuditEntry{
+		ID:         7,
+		CreatedAt:  time.Date(2026, 5, 1, 10, 30, 0, 123456000, time.UTC),
+		ActorID:    &actor,
+		ActorRole:  "official",
+		Action:     "PublishNews",
+		TargetType: "news",
+		TargetID:   "42",
+		Diff:       json.RawMessage(`{"status":{"after":"published","before":"draft"}}`),
+		IP:         "203.0.113.9",
+		RequestID:  "req-1",
+	}
+}
+
+func TestHashCoversEveryField(t *testing.T) {
+	base, err := Hash(testEntry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := "00000000-0000-0000-0000-000000000000"
+	changes := map[string]func(*models.AuditEntry){
+		"prev_hash":   func(e *models.AuditEntry) { e.PrevHash = "abc" },
+		"created_at":  func(e *models.AuditEntry) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) },
+		"actor_id":    func(e *models.AuditEntry) { e.ActorID = &other },
+		"anonymous":   func(e *models.AuditEntry) { e.ActorID = nil },
+		"actor_role":  func(e *models.AuditEntry) { e.ActorRole = "admin" },
+		"action":      func(e *models.AuditEntry) { e.Action = "DeleteNews" },
+		"target_type": func(e *models.AuditEntry) { e.TargetType = "jobs" },
+		"target_id":   func(e *models.AuditEntry) { e.TargetID = "43" },
+		"diff":        func(e *models.AuditEntry) { e.Diff = nil },
+		"ip":          func(e *models.AuditEntry) { e.IP = "198.51.100.1" },
+		"remote_ip":   func(e *models.AuditEntry) { e.RemoteIP = "10.0.0.2" },
+		"request_id":  func(e *models.AuditEntry) { e.RequestID = "req-2" },
+		// Field boundaries: moving text between adjacent fields must change the hash
+		"boundary": func(e *models.AuditEntry) { e.TargetType, e.TargetID = "news4", "2" },
+	}
+	for name, change := range changes {
+		e := testEntry()
+		change(&e)
+		if h, err := Hash(e); err != nil || h == base {
+			t.Errorf("changing %s left the hash unchanged (err %v)", name, err)
+		}
+	}
+
+	// Not covered: the database ID and the stored hash itself
+	e := testEntry()
+	e.ID, e.Hash = 99, "stale"
+	if h, _ := Hash(e); h != base {
+		t.Error("hash depends on ID or Hash")
+	}
+	// A diff read back from jsonb (reordered, reformatted) hashes the same
+	e = testEntry()
+	e.Diff = json.RawMessage(`{ "status": { "before": "draft", "after": "published" } }`)
+	if h, _ := Hash(e); h != base {
+		t.Error("hash depends on the diff's formatting")
+	}
+}
+
+func TestHashWithoutRemoteIPMatchesOlderEntries(t *testing.T) {
+	e := testEntry()
+	e.PrevHash = "f00d"
+	diff, _ := Canonical(e.Diff)
+	payload, _ := json.Marshal([]any{
+		e.PrevHash, e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		*e.ActorID, e.ActorRole, e.Action, e.TargetType, e.TargetID,
+		diff, e.IP, e.RequestID,
+	})
+	sum := sha256.Sum256(payload)
+	if got, _ := Hash(e); got != hex.EncodeToString(sum[:]) {
+		t.Errorf("hash of an entry without remote_ip changed from the original layout")
+	}
+}
+
+// chain builds n linked entries as AuditRepository.Append would
+func chain(t *testing.T, n int) []models.AuditEntry {
+	t.Helper()
+	entries := make([]models.AuditEntry, n)
+	prev := ""
+	for i := range entries {
+		e := testEntry()
+		e.ID = int64(i + 1)
+		e.CreatedAt = e.CreatedAt.Add(time.Duration(i) * time.Minute)
+		e.TargetID = strings.Repeat("x", i+1)
+		e.PrevHash = prev
+		h, err := Hash(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Hash, prev = h, h
+		entries[i] = e
+	}
+	return entries
+}
+
+func verify(entries []models.AuditEntry) (Verifier, error) {
+	var v Verifier
+	for _, e := range entries {
+		if err := v.Check(e); err != nil {
+			return v, err
+		}
+	}
+	return v, nil
+}
+
+func TestVerifier(t *testing.T) {
+	entries := chain(t, 4)
+	v, err := verify(entries)
+	if err != nil {
+		t.Fatalf("intact chain: %v", err)
+	}
+	if v.Checked != 4 || v.Head != entries[3].Hash {
+		t.Errorf("checked %d, head %s; want 4 and the last entry's hash", v.Checked, v.Head)
+	}
+
+	tampered := chain(t, 4)
+	tampered[2].IP = "192.0.2.66"
+	if v, err := verify(tampered); err == nil || !strings.Contains(err.Error(), "entry 3: contents were changed") || v.Checked != 2 {
+		t.Errorf("edited entry: checked %d, err %v", v.Checked, err)
+	}
+
+	removed := append(chain(t, 4)[:1], chain(t, 4)[2:]...)
+	if _, err := verify(removed); err == nil || !strings.Contains(err.Error(), "entry 3: chain broken") {
+		t.Errorf("deleted entry: err %v", err)
+	}
+
+	// Rewriting an entry and its own hash still breaks the next link
+	rewritten := chain(t, 4)
+	rewritten[1].Action = "DeleteNews"
+	rewritten[1].Hash, _ = Hash(rewritten[1])
+	if _, err := verify(rewritten); err == nil || !strings.Contains(err.Error(), "entry 3: chain broken") {
+		t.Errorf("rewritten entry: err %v", err)
+	}
+
+	// A chain must start at the first entry unless the verifier is seeded
+	if _, err := verify(entries[1:]); err == nil {
+		t.Error("a chain missing its first entry verified")
+	}
+	seeded := Verifier{Head: entries[0].Hash}
+	for _, e := range entries[1:] {
+		if err := seeded.Check(e); err != nil {
+			t.Fatalf("seeded verifier: %v", err)
+		}
+	}
+}
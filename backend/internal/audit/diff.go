@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+)
+
+// ignoredFields change on every write and would only add noise to diffs
+var ignoredFields = []string{"updated_at", "available_languages"}
+
+// change is one field's entry in a diff; a side is omitted when the field did not
+// exist there (a null value is kept)
+type change struct {
+	Before *any `json:"before,omitempty"`
+	After  *any `json:"after,omitempty"`
+}
+
+// Diff compares the JSON forms of before and after (either may be nil, for creations
+// and deletions) and returns the changed top-level fields. Values that are not JSON
+// objects are recorded whole under "value".
+func Diff(before, after any) (json.RawMessage, error) {
+	b, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+	changes := map[string]change{}
+	for name, value := range b {
+		if slices.Contains(ignoredFields, name) {
+			continue
+		}
+		other, ok := a[name]
+		switch {
+		case !ok:
+			changes[name] = change{Before: &value}
+		case !reflect.DeepEqual(value, other):
+			changes[name] = change{Before: &value, After: &other}
+		}
+	}
+	for name, value := range a {
+		if _, ok := b[name]; !ok && !slices.Contains(ignoredFields, name) {
+			changes[name] = change{After: &value}
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(changes)
+}
+
+// fields decodes v's JSON form into its top-level fields
+func fields(v any) (map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		var whole any
+		if err := json.Unmarshal(raw, &whole); err != nil {
+			return nil, err
+		}
+		return map[string]any{"value": whole}, nil
+	}
+	return m, nil
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contextKey holds the *record for the current request
+const contextKey = "auditRecord"
+
+// Store appends entries to the chained log
+type Store interface {
+	Append(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// record collects what handlers report about their change
+type record struct {
+	skip                bool
+	targetType          string
+	targetID            string
+	before, after       any
+	hasBefore, hasAfter bool
+}
+
+func current(c *gin.Context) *record {
+	if v, ok := c.Get(contextKey); ok {
+		return v.(*record)
+	}
+	return &record{} // Record is not installed (e.g. in the CLIs); discard
+}
+
+// Before attaches the target's state before the change
+func Before(c *gin.Context, v any) {
+	rec := current(c)
+	rec.before, rec.hasBefore = v, true
+}
+
+// After attaches the target's state after the change
+func After(c *gin.Context, v any) {
+	rec := current(c)
+	rec.after, rec.hasAfter = v, true
+}
+
+// Target names what was changed when the route's :id does not, e.g. a newly created item
+func Target(c *gin.Context, targetType, id string) {
+	rec := current(c)
+	rec.targetType, rec.targetID = targetType, id
+}
+
+// Skip leaves the request out of the log: machine callbacks, device housekeeping and
+// secret ballots
+func Skip(c *gin.Context) {
+	current(c).skip = true
+}
+
+// Record writes an audit entry for every mutating request that succeeds (status below
+// 400). The entry is written after the handler, outside its transaction: a failure to
+// append is logged loudly but cannot undo the change.
+func Record(store Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		rec := &record{}
+		c.Set(contextKey, rec)
+		c.Next()
+		if rec.skip || c.Writer.Status() >= http.StatusBadRequest || c.FullPath() == "" {
+			return
+		}
+
+		entry := models.AuditEntry{
+			ActorRole:  "anonymous",
+			Action:     actionName(c),
+			TargetType: rec.targetType,
+			TargetID:   rec.targetID,
+			IP:         c.ClientIP(),
+			RemoteIP:   c.RemoteIP(), // Unlike ClientIP, not taken from headers the client sets
+			RequestID:  middleware.RequestID(c),
+		}
+		if userID := middleware.UserID(c); userID != "" {
+			entry.ActorID, entry.ActorRole = &userID, middleware.UserRole(c)
+		}
+		if entry.TargetType == "" {
+			entry.TargetType = resource(c.FullPath())
+		}
+		if entry.TargetID == "" {
+			entry.TargetID = c.Param("id")
+		}
+		if rec.hasBefore || rec.hasAfter {
+			diff, err := Diff(rec.before, rec.after)
+			if err != nil {
+				log.Printf("Error diffing audit state for %s: %v\n", entry.Action, err)
+			}
+			entry.Diff = diff
+		}
+
+		// The client may already have gone; the entry must still be written
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Second)
+		defer cancel()
+		if err := store.Append(ctx, &entry); err != nil {
+			log.Printf("ERROR: Audit entry not written for %s %s by %s (request %s): %v\n",
+				entry.Action, entry.TargetID, entry.ActorRole, entry.RequestID, err)
+		}
+	}
+}
+
+// actionName turns the handler's name ("…/handlers.(*NewsHandler).PublishNews-fm") into
+// the action ("PublishNews")
+func actionName(c *gin.Context) string {
+	name := c.HandlerName()
+	name = name[strings.LastIndex(name, ".")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// resource is the first path segment after the API prefix: /api/v1/news/:id -> news
+func resource(path string) string {
+	path = strings.TrimPrefix(path, "/api/v1/")
+	resource, _, _ := strings.Cut(path, "/")
+	return resource
+}
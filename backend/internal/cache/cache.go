@@ -0,0 +1,161 @@
+// Package cache is a small in-process read cache with a TTL, an entry bound (least
+// recently used entries are evicted first) and hit/miss statistics. A nil *Cache is a
+// valid, disabled cache: every lookup misses and nothing is stored.
+//
+// Entries are invalidated a whole cache at a time. Writers clear the cache locally, and
+// Registry.ListenForInvalidations clears every instance's cache when Postgres announces a write.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Stats counts cache activity since start-up
+type Stats struct {
+	Name          string  `json:"name"`
+	Enabled       bool    `json:"enabled"`
+	Entries       int     `json:"entries"`
+	MaxEntries    int     `json:"max_entries"`
+	TTLSeconds    float64 `json:"ttl_seconds"`
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Evictions     uint64  `json:"evictions"`     // Dropped to stay within MaxEntries
+	Expirations   uint64  `json:"expirations"`   // Found past their TTL
+	Invalidations uint64  `json:"invalidations"` // Times the whole cache was cleared
+}
+
+type entry struct {
+	key     string
+	value   any
+	expires time.Time
+}
+
+// Cache maps string keys to values for up to TTL
+type Cache struct {
+	name       string
+	ttl        time.Duration
+	maxEntries int
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	lru        *list.List // Front is most recently used
+	generation uint64     // Bumped by Clear so in-flight loads do not store stale values
+	stats      Stats
+}
+
+// New creates a cache. It returns nil (a disabled cache) when ttl or maxEntries is not positive.
+func New(name string, ttl time.Duration, maxEntries int) *Cache {
+	if ttl <= 0 || maxEntries <= 0 {
+		return nil
+	}
+	return &Cache{
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// get returns the value for key and the current generation
+func (c *Cache) get(key string) (any, bool, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*entry)
+		if time.Now().Before(e.expires) {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			return e.value, true, c.generation
+		}
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		c.stats.Expirations++
+	}
+	c.stats.Misses++
+	return nil, false, c.generation
+}
+
+// set stores value unless the cache was cleared since generation was read
+func (c *Cache) set(key string, value any, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value = &entry{key: key, value: value, expires: time.Now().Add(c.ttl)}
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, value: value, expires: time.Now().Add(c.ttl)})
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry).key)
+		c.stats.Evictions++
+	}
+}
+
+// Clear drops every entry
+func (c *Cache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.generation++
+	c.stats.Invalidations++
+}
+
+// Stats returns a snapshot of the counters
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Name = c.name
+	s.Enabled = true
+	s.Entries = c.lru.Len()
+	s.MaxEntries = c.maxEntries
+	s.TTLSeconds = c.ttl.Seconds()
+	if total := s.Hits + s.Misses; total > 0 {
+		s.HitRatio = float64(s.Hits) / float64(total)
+	}
+	return s
+}
+
+// Name returns the name the cache was created with
+func (c *Cache) Name() string {
+	if c == nil {
+		return ""
+	}
+	return c.name
+}
+
+// Load returns the cached value for key or calls load and caches its result. Errors are
+// not cached. Callers must not modify a returned value in place; clone slices first.
+func Load[V any](c *Cache, key string, load func() (V, error)) (V, error) {
+	if c == nil {
+		return load()
+	}
+	cached, ok, generation := c.get(key)
+	if ok {
+		if v, isV := cached.(V); isV {
+			return v, nil
+		}
+	}
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+	c.set(key, v, generation)
+	return v, nil
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// loadCounting loads key through c, counting calls to the loader
+func loadCounting(t *testing.T, c *Cache, key string, value int, calls *int) int {
+	t.Helper()
+	v, err := Load(c, key, func() (int, error) {
+		*calls++
+		return value, nil
+	})
+	if err != nil {
+		t.Fatalf("Load(%q): %v", key, err)
+	}
+	return v
+}
+
+func TestLoadCachesUntilCleared(t *testing.T) {
+	c := New("news", time.Minute, 10)
+	calls := 0
+	if v := loadCounting(t, c, "a", 1, &calls); v != 1 {
+		t.Fatalf("first load = %d, want 1", v)
+	}
+	if v := loadCounting(t, c, "a", 2, &calls); v != 1 || calls != 1 {
+		t.Fatalf("second load = %d after %d calls, want the cached 1 after 1 call", v, calls)
+	}
+
+	c.Clear()
+	if v := loadCounting(t, c, "a", 3, &calls); v != 3 || calls != 2 {
+		t.Fatalf("load after Clear = %d after %d calls, want 3 after 2", v, calls)
+	}
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 2 || s.Invalidations != 1 || s.Entries != 1 {
+		t.Errorf("stats = %+v, want 1 hit, 2 misses, 1 invalidation, 1 entry", s)
+	}
+}
+
+func TestLoadDoesNotCacheErrors(t *testing.T) {
+	c := New("news", time.Minute, 10)
+	failed := errors.New("database down")
+	if _, err := Load(c, "a", func() (int, error) { return 0, failed }); !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+	calls := 0
+	if v := loadCounting(t, c, "a", 7, &calls); v != 7 || calls != 1 {
+		t.Errorf("load after error = %d after %d calls, want 7 after 1", v, calls)
+	}
+}
+
+func TestClearDuringLoadDropsStaleValue(t *testing.T) {
+	c := New("jobs", time.Minute, 10)
+	v, err := Load(c, "a", func() (int, error) {
+		c.Clear() // A write lands while the old value is being read
+		return 1, nil
+	})
+	if err != nil || v != 1 {
+		t.Fatalf("Load = %d, %v; want 1, nil", v, err)
+	}
+	if s := c.Stats(); s.Entries != 0 {
+		t.Errorf("%d entries cached, want the stale load dropped", s.Entries)
+	}
+	calls := 0
+	if v := loadCounting(t, c, "a", 2, &calls); v != 2 || calls != 1 {
+		t.Errorf("next load = %d after %d calls, want a fresh 2", v, calls)
+	}
+}
+
+func TestLRUEviction(t *testing.T) {
+	c := New("news", time.Minute, 2)
+	calls := 0
+	loadCounting(t, c, "a", 1, &calls)
+	loadCounting(t, c, "b", 2, &calls)
+	loadCounting(t, c, "a", 1, &calls) // a is now the most recently used
+	loadCounting(t, c, "c", 3, &calls) // evicts b
+
+	calls = 0
+	loadCounting(t, c, "a", 1, &calls)
+	loadCounting(t, c, "c", 3, &calls)
+	if calls != 0 {
+		t.Errorf("a and c reloaded %d times, want both cached", calls)
+	}
+	loadCounting(t, c, "b", 2, &calls)
+	if calls != 1 {
+		t.Errorf("b was not evicted")
+	}
+	if s := c.Stats(); s.Evictions != 2 || s.Entries != 2 {
+		t.Errorf("stats = %+v, want 2 evictions and 2 entries", s)
+	}
+}
+
+func TestExpiry(t *testing.T) {
+	c := New("news", time.Millisecond, 10)
+	calls := 0
+	loadCounting(t, c, "a", 1, &calls)
+	time.Sleep(5 * time.Millisecond)
+	loadCounting(t, c, "a", 1, &calls)
+	if calls != 2 {
+		t.Errorf("loader called %d times, want the expired entry reloaded", calls)
+	}
+	if s := c.Stats(); s.Expirations != 1 {
+		t.Errorf("expirations = %d, want 1", s.Expirations)
+	}
+}
+
+func TestDisabledCache(t *testing.T) {
+	c := New("news", 0, 10)
+	if c != nil {
+		t.Fatal("New with a zero TTL should return a disabled (nil) cache")
+	}
+	calls := 0
+	loadCounting(t, c, "a", 1, &calls)
+	loadCounting(t, c, "a", 1, &calls)
+	c.Clear()
+	if calls != 2 {
+		t.Errorf("loader called %d times, want every lookup to miss", calls)
+	}
+	if s := c.Stats(); s.Enabled {
+		t.Error("disabled cache reports itself enabled")
+	}
+}
+
+func TestRegistryInvalidate(t *testing.T) {
+	r := NewRegistry(true, time.Minute, 10)
+	news, jobs := r.Cache("news"), r.Cache("jobs")
+	if r.Cache("news") != news {
+		t.Fatal("Cache returned a new cache for an existing name")
+	}
+	calls := 0
+	loadCounting(t, news, "a", 1, &calls)
+	loadCounting(t, jobs, "a", 1, &calls)
+
+	r.Invalidate("news")
+	r.Invalidate("polls") // Unknown names are ignored
+	calls = 0
+	loadCounting(t, news, "a", 1, &calls)
+	loadCounting(t, jobs, "a", 1, &calls)
+	if calls != 1 {
+		t.Errorf("%d reloads after invalidating news, want only news reloaded", calls)
+	}
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"village_project/internal/database"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// NotifyChannel carries collection names from the content version trigger
+// (migrations/014_cache_invalidation.sql) whenever a collection is written
+const NotifyChannel = "village_cache"
+
+// ListenForInvalidations clears the named cache for every notification, on this and
+// every other instance, until ctx is cancelled. After a reconnect all caches are cleared
+// since notifications may have been missed; the TTL bounds staleness while disconnected.
+func (r *Registry) ListenForInvalidations(ctx context.Context, url string) {
+	if !r.enabled {
+		return
+	}
+	database.Listen(ctx, url, NotifyChannel, func(ctx context.Context, reconnected bool) {
+		if reconnected {
+			r.InvalidateAll()
+		}
+	}, func(n *pgconn.Notification) {
+		r.Invalidate(n.Payload)
+		if r.OnInvalidate != nil {
+			r.OnInvalidate(n.Payload)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"time"
+)
+
+// Registry holds one cache per collection ("news", "jobs") so invalidations and
+// statistics can address them by name
+type Registry struct {
+	enabled    bool
+	ttl        time.Duration
+	maxEntries int
+	names      []string
+	caches     map[string]*Cache
+
+	// OnInvalidate, when set, is called with the collection name for every invalidation
+	// notification, e.g. to keep reads on the primary until a replica catches up
+	OnInvalidate func(name string)
+}
+
+// NewRegistry creates a registry whose caches share the TTL and entry bound. With
+// enabled false every cache it hands out is nil (disabled).
+func NewRegistry(enabled bool, ttl time.Duration, maxEntries int) *Registry {
+	return &Registry{enabled: enabled, ttl: ttl, maxEntries: maxEntries, caches: map[string]*Cache{}}
+}
+
+// Cache returns the named cache, creating it on first use
+func (r *Registry) Cache(name string) *Cache {
+	if c, ok := r.caches[name]; ok {
+		return c
+	}
+	var c *Cache
+	if r.enabled {
+		c = New(name, r.ttl, r.maxEntries)
+	}
+	r.names = append(r.names, name)
+	r.caches[name] = c
+	return c
+}
+
+// Invalidate clears the named cache; unknown names are ignored
+func (r *Registry) Invalidate(name string) {
+	r.caches[name].Clear()
+}
+
+// InvalidateAll clears every cache, e.g. after missing notifications
+func (r *Registry) InvalidateAll() {
+	for _, c := range r.caches {
+		c.Clear()
+	}
+}
+
+// Stats reports every cache in creation order
+func (r *Registry) Stats() []Stats {
+	stats := make([]Stats, 0, len(r.names))
+	for _, name := range r.names {
+		s := r.caches[name].Stats()
+		s.Name = name
+		stats = append(stats, s)
+	}
+	return stats
+}
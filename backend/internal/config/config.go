@@ -1,86 +1,306 @@
 package config
 
 import (
-	// Import "fmt" only if used elsewhere now, likely not needed here anymore
-	"fmt"
 	"log"
-	// Keep "strings" only if used elsewhere, likely not needed here anymore
-	// "strings"
-
-	"github.com/spf13/viper"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // Config holds all configuration for the application
 type Config struct {
+	Profile            string `mapstructure:"APP_PROFILE"` // dev, staging or prod; selects config.<profile>.yaml (see load.go)
 	Port               string `mapstructure:"PORT"`
 	SupabaseURL        string `mapstructure:"SUPABASE_URL"`         // Keep for potential API calls
 	SupabaseAnonKey    string `mapstructure:"SUPABASE_ANON_KEY"`    // Keep for potential API calls
 	SupabaseServiceKey string `mapstructure:"SUPABASE_SERVICE_KEY"` // Keep for API calls
 	DatabaseURL        string `mapstructure:"DATABASE_URL"`         // Primary connection string (will hold pooler URL)
-	CorsAllowedOrigins string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	DatabaseListenURL  string `mapstructure:"DATABASE_LISTEN_URL"`  // Session (direct) connection for LISTEN; defaults to DATABASE_URL
+	CorsAllowedOrigins string `mapstructure:"CORS_ALLOWED_ORIGINS"` // Comma-separated; applied live (see reload.go)
 	GinMode            string `mapstructure:"GIN_MODE"`
+	TrustedProxies     string `mapstructure:"TRUSTED_PROXIES"`     // Comma-separated IPs/CIDRs of load balancers whose X-Forwarded-For is believed; empty trusts none
+	SupabaseJWTSecret  string `mapstructure:"SUPABASE_JWT_SECRET"` // Used to verify user access tokens (HS256)
+
+	// --- Database pool (see database.go for DB_MODE and validation) ---
+	DBMode                   string `mapstructure:"DB_MODE"`                     // pooler (PgBouncer/Supabase pooler) or direct
+	DBExecMode               string `mapstructure:"DB_EXEC_MODE"`                // pgx exec mode; default simple_protocol (pooler) or cache_statement (direct)
+	DBStatementCacheCapacity int    `mapstructure:"DB_STATEMENT_CACHE_CAPACITY"` // Prepared statements kept per connection with cache_statement/cache_describe
+	DBMaxConns               int    `mapstructure:"DB_MAX_CONNS"`
+	DBMinConns               int    `mapstructure:"DB_MIN_CONNS"`                 // Kept open even when idle
+	DBMaxConnLifetimeMinutes int    `mapstructure:"DB_MAX_CONN_LIFETIME_MINUTES"` // Connections are recycled after this long
+	DBMaxConnIdleMinutes     int    `mapstructure:"DB_MAX_CONN_IDLE_MINUTES"`     // Idle connections above DB_MIN_CONNS are closed after this long
+	DBHealthCheckSeconds     int    `mapstructure:"DB_HEALTH_CHECK_SECONDS"`      // How often idle connections are checked
+	DBConnectTimeoutSeconds  int    `mapstructure:"DB_CONNECT_TIMEOUT_SECONDS"`   // Per connection attempt, and for the start-up ping
+
+	// --- Read replica (optional; list endpoints read from it, writes stay on the primary) ---
+	DatabaseReadURL               string `mapstructure:"DATABASE_READ_URL"`                 // Empty sends every query to DATABASE_URL
+	DatabaseReadYourWritesSeconds int    `mapstructure:"DATABASE_READ_YOUR_WRITES_SECONDS"` // Reads use the primary this long after a write
+	DatabaseReadMaxLagSeconds     int    `mapstructure:"DATABASE_READ_MAX_LAG_SECONDS"`     // Replica is treated as unhealthy beyond this lag
+	DatabaseReadCheckSeconds      int    `mapstructure:"DATABASE_READ_CHECK_SECONDS"`       // Replica health check interval
+
+	// --- Email notifications ---
+	EmailMode         string `mapstructure:"EMAIL_MODE"`       // smtp, file (dev: write .eml files) or disabled
+	EmailOutboxDir    string `mapstructure:"EMAIL_OUTBOX_DIR"` // Where EMAIL_MODE=file writes messages
+	SMTPHost          string `mapstructure:"SMTP_HOST"`        // Use localhost with SMTP_PORT=1025 for MailHog
+	SMTPPort          int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername      string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword      string `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom          string `mapstructure:"SMTP_FROM"`           // e.g. "Village Panchayat <noreply@example.org>"
+	SMTPImplicitTLS   bool   `mapstructure:"SMTP_IMPLICIT_TLS"`   // true for port 465; otherwise STARTTLS is used when offered
+	NotifyMaxAttempts int    `mapstructure:"NOTIFY_MAX_ATTEMPTS"` // Send attempts per message before giving up
+	PublicAppURL      string `mapstructure:"PUBLIC_APP_URL"`      // Used to build links in notifications
+
+	// --- SMS notifications ---
+	SMSProvider      string `mapstructure:"SMS_PROVIDER"`     // http, fake (dev/tests) or disabled
+	SMSProviderURL   string `mapstructure:"SMS_PROVIDER_URL"` // Gateway endpoint for SMS_PROVIDER=http
+	SMSAuthHeader    string `mapstructure:"SMS_AUTH_HEADER"`  // Header carrying SMS_API_KEY, e.g. "authkey" (MSG91)
+	SMSAPIKey        string `mapstructure:"SMS_API_KEY"`
+	SMSSenderID      string `mapstructure:"SMS_SENDER_ID"`      // 6-character DLT header, e.g. "VLGPNC"
+	SMSDLTEntityID   string `mapstructure:"SMS_DLT_ENTITY_ID"`  // Principal entity ID registered on the DLT portal
+	SMSDLTTemplates  string `mapstructure:"SMS_DLT_TEMPLATES"`  // "job_alert_match=1107...,emergency_notice=1107..."
+	SMSDailyCap      int    `mapstructure:"SMS_DAILY_CAP"`      // Messages per recipient per day (emergencies exempt); 0 = no cap
+	SMSCallbackToken string `mapstructure:"SMS_CALLBACK_TOKEN"` // Shared secret expected on delivery callbacks
+
+	// --- Web Push notifications ---
+	VAPIDPublicKey  string `mapstructure:"VAPID_PUBLIC_KEY"`  // base64url; generate a pair with `go run ./cmd/vapidkeys`
+	VAPIDPrivateKey string `mapstructure:"VAPID_PRIVATE_KEY"` // Push is disabled when both keys are empty
+	VAPIDSubject    string `mapstructure:"VAPID_SUBJECT"`     // Contact for push services, e.g. "mailto:admin@example.org"
+	PushTTLSeconds  int    `mapstructure:"PUSH_TTL_SECONDS"`  // How long push services hold undelivered messages
+
+	// --- Outbound webhooks ---
+	WebhookMaxAttempts    int `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`    // Attempts per delivery before it is marked failed
+	WebhookTimeoutSeconds int `mapstructure:"WEBHOOK_TIMEOUT_SECONDS"` // Per-request timeout for receivers
+	OutboxMaxAttempts     int `mapstructure:"OUTBOX_MAX_ATTEMPTS"`     // Handler attempts per domain event before it is dead-lettered
+
+	// --- Live updates stream ---
+	StreamHeartbeatSeconds int `mapstructure:"STREAM_HEARTBEAT_SECONDS"` // Comment lines that keep proxies from closing idle streams
+	StreamReplaySize       int `mapstructure:"STREAM_REPLAY_SIZE"`       // Events kept for Last-Event-ID resume
+	StreamClientBuffer     int `mapstructure:"STREAM_CLIENT_BUFFER"`     // Events queued per client before a slow client is dropped
+
+	// --- Content languages ---
+	SupportedLocales string `mapstructure:"SUPPORTED_LOCALES"` // Comma-separated, e.g. "te,en"
+	FallbackLocale   string `mapstructure:"FALLBACK_LOCALE"`   // Served when a request matches none of an item's languages
+
+	// --- HTTP caching (Cache-Control per route; conditional GETs work regardless) ---
+	CacheControlNewsList string `mapstructure:"CACHE_CONTROL_NEWS_LIST"` // GET /news
+	CacheControlNewsItem string `mapstructure:"CACHE_CONTROL_NEWS_ITEM"` // GET /news/:id
+	CacheControlJobsList string `mapstructure:"CACHE_CONTROL_JOBS_LIST"` // GET /jobs
+	CacheControlJobsItem string `mapstructure:"CACHE_CONTROL_JOBS_ITEM"` // GET /jobs/:id
+	CacheControlFeeds    string `mapstructure:"CACHE_CONTROL_FEEDS"`     // RSS and Atom feeds
+
+	// --- Read cache (in-process, cleared on writes and via NOTIFY from other instances) ---
+	CacheEnabled    bool `mapstructure:"CACHE_ENABLED"`     // false sends every read to the database
+	CacheTTLSeconds int  `mapstructure:"CACHE_TTL_SECONDS"` // Upper bound on staleness if a notification is missed
+	CacheMaxEntries int  `mapstructure:"CACHE_MAX_ENTRIES"` // Per collection; least recently used entries go first
+
+	// --- Trash (deleted news and jobs) ---
+	TrashRetentionDays        int `mapstructure:"TRASH_RETENTION_DAYS"`         // Deleted items can be restored for this long, then are purged
+	TrashPurgeIntervalMinutes int `mapstructure:"TRASH_PURGE_INTERVAL_MINUTES"` // How often the purge runs
+
+	// --- Live settings (applied on SIGHUP or when a config file changes, see reload.go; CORS_ALLOWED_ORIGINS too) ---
+	RateLimitPerMinute int    `mapstructure:"RATE_LIMIT_PER_MINUTE"` // Requests per client IP; 0 = no limit
+	RateLimitBurst     int    `mapstructure:"RATE_LIMIT_BURST"`      // Requests a client may make at once before the limit applies
+	FeatureFlags       string `mapstructure:"FEATURE_FLAGS"`         // Features to switch off, e.g. "polls=off,import=off" (see Features)
+	LogLevel           string `mapstructure:"LOG_LEVEL"`             // debug, info, warn or error
+	ConfigWatchSeconds int    `mapstructure:"CONFIG_WATCH_SECONDS"`  // How often config files are checked for changes; 0 = SIGHUP only
 	// DBPassword is no longer needed here if using the full DATABASE_URL from pooler
 	// DBPassword         string `mapstructure:"DB_PASSWORD"`
 }
 
-// LoadConfig reads configuration from file or environment variables.
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)   // Path to look for the config file in
-	viper.SetConfigName(".env") // Name of config file (without extension)
-	viper.SetConfigType("env")  // Look for specific type
-
-	viper.AutomaticEnv() // Read in environment variables that match
+// defaults apply when no file or environment variable sets a key
+var defaults = map[string]any{
+	"APP_PROFILE":                       ProfileDev,
+	"PORT":                              "8080",
+	"CORS_ALLOWED_ORIGINS":              "http://localhost:3000",
+	"GIN_MODE":                          "debug", // Default Gin mode
+	"TRUSTED_PROXIES":                   "",      // Client IPs come from the connection itself
+	"SUPABASE_JWT_SECRET":               "",
+	"EMAIL_MODE":                        "disabled",
+	"EMAIL_OUTBOX_DIR":                  "./tmp/mail",
+	"SMTP_HOST":                         "localhost",
+	"SMTP_PORT":                         1025,
+	"SMTP_USERNAME":                     "",
+	"SMTP_PASSWORD":                     "",
+	"SMTP_FROM":                         "Village Panchayat <noreply@localhost>",
+	"SMTP_IMPLICIT_TLS":                 false,
+	"NOTIFY_MAX_ATTEMPTS":               4,
+	"PUBLIC_APP_URL":                    "http://localhost:5000",
+	"SMS_PROVIDER":                      "disabled",
+	"SMS_PROVIDER_URL":                  "",
+	"SMS_AUTH_HEADER":                   "authkey",
+	"SMS_API_KEY":                       "",
+	"SMS_SENDER_ID":                     "",
+	"SMS_DLT_ENTITY_ID":                 "",
+	"SMS_DLT_TEMPLATES":                 "",
+	"SMS_DAILY_CAP":                     5,
+	"SMS_CALLBACK_TOKEN":                "",
+	"VAPID_PUBLIC_KEY":                  "",
+	"VAPID_PRIVATE_KEY":                 "",
+	"VAPID_SUBJECT":                     "mailto:admin@localhost",
+	"PUSH_TTL_SECONDS":                  86400,
+	"WEBHOOK_MAX_ATTEMPTS":              8,
+	"WEBHOOK_TIMEOUT_SECONDS":           10,
+	"OUTBOX_MAX_ATTEMPTS":               10,
+	"DATABASE_LISTEN_URL":               "",
+	"STREAM_HEARTBEAT_SECONDS":          20,
+	"STREAM_REPLAY_SIZE":                256,
+	"STREAM_CLIENT_BUFFER":              32,
+	"SUPPORTED_LOCALES":                 "te,en",
+	"FALLBACK_LOCALE":                   "en",
+	"CACHE_CONTROL_NEWS_LIST":           "public, no-cache", // Always revalidate; a 304 is cheap
+	"CACHE_CONTROL_NEWS_ITEM":           "public, max-age=60",
+	"CACHE_CONTROL_JOBS_LIST":           "public, no-cache",
+	"CACHE_CONTROL_JOBS_ITEM":           "public, max-age=60",
+	"CACHE_CONTROL_FEEDS":               "public, max-age=300",
+	"CACHE_ENABLED":                     true,
+	"CACHE_TTL_SECONDS":                 60,
+	"CACHE_MAX_ENTRIES":                 500,
+	"DB_MODE":                           DBModePooler,
+	"DB_EXEC_MODE":                      "",
+	"DB_STATEMENT_CACHE_CAPACITY":       512,
+	"DB_MAX_CONNS":                      10,
+	"DB_MIN_CONNS":                      2,
+	"DB_MAX_CONN_LIFETIME_MINUTES":      60,
+	"DB_MAX_CONN_IDLE_MINUTES":          30,
+	"DB_HEALTH_CHECK_SECONDS":           60,
+	"DB_CONNECT_TIMEOUT_SECONDS":        10,
+	"DATABASE_READ_URL":                 "",
+	"DATABASE_READ_YOUR_WRITES_SECONDS": 5,
+	"DATABASE_READ_MAX_LAG_SECONDS":     30,
+	"DATABASE_READ_CHECK_SECONDS":       10,
+	"TRASH_RETENTION_DAYS":              30,
+	"TRASH_PURGE_INTERVAL_MINUTES":      60,
+	"RATE_LIMIT_PER_MINUTE":             0,
+	"RATE_LIMIT_BURST":                  20,
+	"FEATURE_FLAGS":                     "",
+	"LOG_LEVEL":                         "debug",
+	"CONFIG_WATCH_SECONDS":              5,
+}
 
-	// Set default values
-	viper.SetDefault("PORT", "8080")
-	viper.SetDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
-	viper.SetDefault("GIN_MODE", "debug") // Default Gin mode
+// profileDefaults override defaults for a profile; files and the environment still win
+var profileDefaults = map[string]map[string]any{
+	ProfileStaging: {"GIN_MODE": "release", "LOG_LEVEL": "info"},
+	ProfileProd:    {"GIN_MODE": "release", "LOG_LEVEL": "info"},
+}
 
-	// Attempt to read .env file first (useful for local overrides)
-	err = viper.ReadInConfig()
-	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			// Only log error if it's NOT a file not found error
-			log.Printf("Warning: Error reading config file: %s\n", err)
-        } else {
-            log.Println("Config file (.env) not found, relying on environment variables or defaults.")
-        }
-        // Proceed even if .env is not found, environment variables take precedence
-        err = nil
-	}
-
-	// Unmarshal all values found (from file or environment variables)
-	err = viper.Unmarshal(&config)
+// LoadConfig loads the configuration in path for the profile named by APP_PROFILE (see
+// Load for the layers). The error lists every problem found.
+func LoadConfig(path string) (Config, error) {
+	config, _, err := Load(Options{Dir: path})
 	if err != nil {
-		log.Fatalf("Unable to decode config into struct: %v", err)
-		// return - Unreachable due to Fatalf
+		return config, err
 	}
+	log.Printf("Using Database URL: %s", RedactDatabaseURL(config.DatabaseURL)) // Never the password
+	log.Printf("Configuration loaded successfully (profile %s).", config.Profile)
+	return config, nil
+}
 
-	// --- SIMPLIFIED Database URL Logic ---
-	// Get DATABASE_URL directly after unmarshalling.
-	// viper.AutomaticEnv() ensures environment variable takes precedence over file.
-	config.DatabaseURL = viper.GetString("DATABASE_URL")
-	// -------------------------------------
-
+// validate normalizes config and records every problem in probs
+func validate(config *Config, probs *problems) {
+	if port, err := strconv.Atoi(config.Port); err != nil || port < 1 || port > 65535 {
+		probs.addf("PORT must be a port number, got %q", config.Port)
+	}
+	if !slices.Contains([]string{"debug", "release", "test"}, config.GinMode) {
+		probs.addf("GIN_MODE must be debug, release or test, got %q", config.GinMode)
+	}
+	for _, origin := range strings.Split(config.CorsAllowedOrigins, ",") {
+		origin = strings.TrimSpace(origin)
+		if u, err := url.Parse(origin); origin != "*" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			probs.addf("CORS_ALLOWED_ORIGINS: %q is not an origin such as https://example.org", origin)
+		}
+	}
+	for _, proxy := range config.TrustedProxyList() {
+		if _, err := netip.ParsePrefix(proxy); err != nil {
+			if _, err := netip.ParseAddr(proxy); err != nil {
+				probs.addf("TRUSTED_PROXIES: %q is not an IP address or CIDR such as 10.0.0.0/8", proxy)
+			}
+		}
+	}
 
-	// --- Validate essential configs ---
 	if config.DatabaseURL == "" {
-		// This is now a critical error if DATABASE_URL is not set via env var or file
-		err = fmt.Errorf("DATABASE_URL environment variable is required but not set or empty")
-		return
+		probs.addf("DATABASE_URL is required")
 	}
-	// Remove check for SupabaseURL unless needed for non-DB API calls
-	// if config.SupabaseURL == "" { ... }
-
-	// Service key is likely still needed for Supabase API interactions (e.g., auth)
+	validateDatabase(config, probs)
+	// Service key is still needed for Supabase API interactions (e.g., auth)
 	if config.SupabaseServiceKey == "" {
-		err = fmt.Errorf("SUPABASE_SERVICE_KEY environment variable is required")
-		return
+		probs.addf("SUPABASE_SERVICE_KEY is required")
+	}
+
+	switch config.EmailMode {
+	case "smtp":
+		if config.SMTPHost == "" || config.SMTPPort < 1 || config.SMTPPort > 65535 {
+			probs.addf("EMAIL_MODE=smtp requires SMTP_HOST and an SMTP_PORT between 1 and 65535")
+		}
+	case "file", "disabled", "":
+	default:
+		probs.addf("EMAIL_MODE must be smtp, file or disabled, got %q", config.EmailMode)
+	}
+	if _, err := mail.ParseAddress(config.SMTPFrom); err != nil {
+		probs.addf("SMTP_FROM %q is not an address: %v", config.SMTPFrom, err)
+	}
+	switch config.SMSProvider {
+	case "http":
+		if config.SMSProviderURL == "" || config.SMSAPIKey == "" {
+			probs.addf("SMS_PROVIDER=http requires SMS_PROVIDER_URL and SMS_API_KEY")
+		}
+	case "fake", "disabled", "":
+	default:
+		probs.addf("SMS_PROVIDER must be http, fake or disabled, got %q", config.SMSProvider)
+	}
+	if (config.VAPIDPublicKey == "") != (config.VAPIDPrivateKey == "") {
+		probs.addf("VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY must be set together")
+	}
+
+	probs.atLeast("NOTIFY_MAX_ATTEMPTS", config.NotifyMaxAttempts, 1)
+	probs.atLeast("SMS_DAILY_CAP", config.SMSDailyCap, 0)
+	probs.atLeast("PUSH_TTL_SECONDS", config.PushTTLSeconds, 0)
+	probs.atLeast("WEBHOOK_MAX_ATTEMPTS", config.WebhookMaxAttempts, 1)
+	probs.atLeast("WEBHOOK_TIMEOUT_SECONDS", config.WebhookTimeoutSeconds, 1)
+	probs.atLeast("OUTBOX_MAX_ATTEMPTS", config.OutboxMaxAttempts, 1)
+	probs.atLeast("STREAM_HEARTBEAT_SECONDS", config.StreamHeartbeatSeconds, 1)
+	probs.atLeast("STREAM_REPLAY_SIZE", config.StreamReplaySize, 0)
+	probs.atLeast("STREAM_CLIENT_BUFFER", config.StreamClientBuffer, 1)
+	probs.atLeast("CACHE_TTL_SECONDS", config.CacheTTLSeconds, 1)
+	probs.atLeast("CACHE_MAX_ENTRIES", config.CacheMaxEntries, 1)
+	probs.atLeast("TRASH_RETENTION_DAYS", config.TrashRetentionDays, 1)
+	probs.atLeast("TRASH_PURGE_INTERVAL_MINUTES", config.TrashPurgeIntervalMinutes, 1)
+	probs.atLeast("RATE_LIMIT_PER_MINUTE", config.RateLimitPerMinute, 0)
+	probs.atLeast("RATE_LIMIT_BURST", config.RateLimitBurst, 1)
+	probs.atLeast("CONFIG_WATCH_SECONDS", config.ConfigWatchSeconds, 0)
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, config.LogLevel) {
+		probs.addf("LOG_LEVEL must be debug, info, warn or error, got %q", config.LogLevel)
+	}
+	if _, err := parseFeatureFlags(config.FeatureFlags); err != nil {
+		probs.addf("FEATURE_FLAGS: %v", err)
+	}
+	if strings.TrimSpace(config.SupportedLocales) == "" || strings.TrimSpace(config.FallbackLocale) == "" {
+		probs.addf("SUPPORTED_LOCALES and FALLBACK_LOCALE must not be empty")
 	}
-	// Remove DBPassword validation
-	// if viper.GetString("DB_PASSWORD") == "" { ... }
 
-	log.Printf("Using Database URL: %s", config.DatabaseURL) // Log the URL being used
-	log.Println("Configuration loaded successfully.")
-	return
+	// Development conveniences that must not reach production
+	if config.Profile == ProfileProd {
+		if config.GinMode != "release" {
+			probs.addf("GIN_MODE must be release with APP_PROFILE=prod, got %q", config.GinMode)
+		}
+		if config.SupabaseJWTSecret == "" {
+			probs.addf("SUPABASE_JWT_SECRET is required with APP_PROFILE=prod; without it every request is anonymous")
+		}
+		if config.EmailMode == "file" || config.SMSProvider == "fake" {
+			probs.addf("EMAIL_MODE=file and SMS_PROVIDER=fake are for development, not APP_PROFILE=prod")
+		}
+	}
+}
+
+// TrustedProxyList splits TRUSTED_PROXIES for gin's SetTrustedProxies; nil trusts no proxy
+func (c *Config) TrustedProxyList() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(c.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
 }
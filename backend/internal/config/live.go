@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Features can be switched off with FEATURE_FLAGS, e.g. "polls=off,import=off". All are
+// on unless listed.
+var Features = []string{"polls", "grievances", "push", "stream", "import", "export"}
+
+// parseFeatureFlags reads FEATURE_FLAGS into feature -> enabled
+func parseFeatureFlags(flags string) (map[string]bool, error) {
+	parsed := make(map[string]bool)
+	for _, entry := range strings.Split(flags, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(Features, name) {
+			return nil, fmt.Errorf("unknown feature %q (known: %s)", name, strings.Join(Features, ", "))
+		}
+		enabled, err := parseSwitch(value)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("%q: use %s=on or %s=off", entry, name, name)
+		}
+		parsed[name] = enabled
+	}
+	return parsed, nil
+}
+
+func parseSwitch(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(value))
+}
+
+// FeatureEnabled reports whether FEATURE_FLAGS leaves feature on
+func (c *Config) FeatureEnabled(feature string) bool {
+	flags, err := parseFeatureFlags(c.FeatureFlags)
+	if err != nil {
+		return true // Rejected by validation; unreachable for a loaded Config
+	}
+	enabled, listed := flags[feature]
+	return !listed || enabled
+}
+
+// AllowsOrigin reports whether CORS_ALLOWED_ORIGINS lists origin, or "*"
+func (c *Config) AllowsOrigin(origin string) bool {
+	for _, allowed := range strings.Split(c.CorsAllowedOrigins, ",") {
+		if allowed = strings.TrimSpace(allowed); allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
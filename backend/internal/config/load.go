@@ -0,0 +1,358 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Profiles (APP_PROFILE) select config.<profile>.yaml and a few built-in defaults
+const (
+	ProfileDev     = "dev"
+	ProfileStaging = "staging"
+	ProfileProd    = "prod"
+)
+
+// Profiles lists the accepted APP_PROFILE values
+var Profiles = []string{ProfileDev, ProfileStaging, ProfileProd}
+
+// configFileTypes are the extensions tried for config.<ext> and config.<profile>.<ext>
+var configFileTypes = []string{"yaml", "yml", "toml"}
+
+// Options says where Load looks for configuration
+type Options struct {
+	Dir     string // Holds config.yaml (or .yml/.toml), the profile files and .env
+	Profile string // Overrides APP_PROFILE when set
+}
+
+// Setting is one effective value and where it came from, as shown by `config print`
+type Setting struct {
+	Key    string
+	Value  string // Secrets are masked (see Mask)
+	Source string // "default", a file name, "env", or the secret file read for <KEY>_FILE
+}
+
+// ValidationError lists every problem found, so they can all be fixed in one go
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d configuration problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// problems collects validation failures
+type problems []string
+
+func (p *problems) addf(format string, args ...any) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+// atLeast checks an integer setting's lower bound
+func (p *problems) atLeast(key string, value, min int) {
+	if value < min {
+		p.addf("%s must be at least %d, got %d", key, min, value)
+	}
+}
+
+// layer is one source of values; later layers override earlier ones
+type layer struct {
+	name   string
+	values map[string]any // Upper-case keys
+}
+
+// Load builds the configuration from these layers, each overriding the ones before:
+//
+//  1. defaults, then the profile's built-in defaults
+//  2. config.yaml, config.yml or config.toml in opts.Dir
+//  3. config.<profile>.yaml (or .yml/.toml)
+//  4. .env in opts.Dir
+//  5. environment variables
+//
+// A key can also be given as <KEY>_FILE naming a file that holds the value (Docker and
+// Kubernetes secrets); it counts as set in the layer where <KEY>_FILE is. Unknown keys in
+// YAML/TOML files are errors. The error is a *ValidationError listing every problem;
+// the settings are returned even then so they can be printed.
+func Load(opts Options) (Config, []Setting, error) {
+	var config Config
+	var probs problems
+	fields := configFields()
+
+	base, err := readConfigFile(opts.Dir, "config", fields)
+	if err != nil {
+		probs.addf("%v", err)
+	}
+	dotEnv, err := readDotEnv(opts.Dir, fields)
+	if err != nil {
+		probs.addf("%v", err)
+	}
+	env := readEnv(fields)
+
+	// The profile can be chosen anywhere except in a profile file
+	profile := strings.ToLower(strings.TrimSpace(opts.Profile))
+	if profile == "" {
+		if v, _, ok := lookup([]layer{{"default", defaults}, base, dotEnv, env}, "APP_PROFILE"); ok {
+			profile = strings.ToLower(strings.TrimSpace(fmt.Sprint(v)))
+		}
+	}
+	if !slices.Contains(Profiles, profile) {
+		probs.addf("APP_PROFILE must be one of %s, got %q", strings.Join(Profiles, ", "), profile)
+	}
+	profileFile, err := readConfigFile(opts.Dir, "config."+profile, fields)
+	if err != nil {
+		probs.addf("%v", err)
+	}
+
+	layers := []layer{
+		{"default", defaults},
+		{"default (" + profile + ")", profileDefaults[profile]},
+		base,
+		profileFile,
+		dotEnv,
+		env,
+	}
+
+	sources := make(map[string]string, len(fields))
+	target := reflect.ValueOf(&config).Elem()
+	for _, f := range fields {
+		raw, source, ok := resolve(layers, f.key, &probs)
+		if !ok {
+			sources[f.key] = "unset"
+			continue
+		}
+		sources[f.key] = source
+		if err := decode(target.Field(f.index), raw); err != nil {
+			probs.addf("%s (from %s): %v", f.key, source, err)
+			if d, ok := defaults[f.key]; ok {
+				decode(target.Field(f.index), d) // Keeps later checks from repeating the problem
+			}
+		}
+	}
+	config.Profile = profile
+	if opts.Profile != "" {
+		sources["APP_PROFILE"] = "option"
+	}
+
+	if config.DatabaseListenURL == "" && config.DatabaseURL != "" {
+		config.DatabaseListenURL = config.DatabaseURL
+		sources["DATABASE_LISTEN_URL"] = "DATABASE_URL"
+	}
+	validate(&config, &probs)
+
+	settings := make([]Setting, 0, len(fields))
+	for _, f := range fields {
+		value := fmt.Sprint(target.Field(f.index).Interface())
+		settings = append(settings, Setting{Key: f.key, Value: Mask(f.key, value), Source: sources[f.key]})
+	}
+	if len(probs) > 0 {
+		return config, settings, &ValidationError{Problems: probs}
+	}
+	return config, settings, nil
+}
+
+// field is a Config field and its key
+type field struct {
+	key   string
+	index int
+}
+
+func configFields() []field {
+	t := reflect.TypeOf(Config{})
+	fields := make([]field, 0, t.NumField())
+	for i := range t.NumField() {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" {
+			fields = append(fields, field{key: key, index: i})
+		}
+	}
+	return fields
+}
+
+// known reports whether key is a setting, or <setting>_FILE
+func known(fields []field, key string) bool {
+	key = strings.TrimSuffix(key, "_FILE")
+	return slices.ContainsFunc(fields, func(f field) bool { return f.key == key })
+}
+
+// lookup finds the topmost layer that sets key
+func lookup(layers []layer, key string) (value any, index int, ok bool) {
+	for i := len(layers) - 1; i >= 0; i-- {
+		if v, found := layers[i].values[key]; found {
+			return v, i, true
+		}
+	}
+	return nil, -1, false
+}
+
+// resolve picks the value for key, reading the secret file when <KEY>_FILE is set in a
+// higher layer than key itself
+func resolve(layers []layer, key string, probs *problems) (any, string, bool) {
+	value, valueLayer, ok := lookup(layers, key)
+	path, fileLayer, fileOK := lookup(layers, key+"_FILE")
+	if fileOK && fmt.Sprint(path) == "" {
+		fileOK = false
+	}
+	switch {
+	case fileOK && fileLayer == valueLayer:
+		probs.addf("%s and %s_FILE are both set in %s; use one", key, key, layers[valueLayer].name)
+		return nil, "", false
+	case fileOK && fileLayer > valueLayer:
+		name := fmt.Sprint(path)
+		data, err := os.ReadFile(name)
+		if err != nil {
+			probs.addf("%s_FILE (from %s): %v", key, layers[fileLayer].name, err)
+			return nil, "", false
+		}
+		// Secret files usually end with a newline the value must not include
+		return strings.TrimRight(string(data), "\r\n"), "file " + name, true
+	case ok:
+		return value, layers[valueLayer].name, true
+	}
+	return nil, "", false
+}
+
+// decode stores a value from any layer into a string, int or bool field
+func decode(dst reflect.Value, raw any) error {
+	switch dst.Kind() {
+	case reflect.String:
+		switch v := raw.(type) {
+		case string:
+			dst.SetString(v)
+		case []any: // A YAML/TOML list, e.g. of CORS origins
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			dst.SetString(strings.Join(items, ","))
+		case map[string]any:
+			return errors.New("expected a value, got a section")
+		default:
+			dst.SetString(fmt.Sprint(v))
+		}
+	case reflect.Int:
+		switch v := raw.(type) {
+		case int:
+			dst.SetInt(int64(v))
+		case int64:
+			dst.SetInt(v)
+		case float64:
+			if v != float64(int64(v)) {
+				return fmt.Errorf("%v is not a whole number", v)
+			}
+			dst.SetInt(int64(v))
+		case string:
+			n, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%q is not a whole number", v)
+			}
+			dst.SetInt(int64(n))
+		default:
+			return fmt.Errorf("%v is not a whole number", v)
+		}
+	case reflect.Bool:
+		switch v := raw.(type) {
+		case bool:
+			dst.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("%q is not true or false", v)
+			}
+			dst.SetBool(b)
+		default:
+			return fmt.Errorf("%v is not true or false", v)
+		}
+	default:
+		return fmt.Errorf("unsupported field type %s", dst.Kind())
+	}
+	return nil
+}
+
+// readConfigFile reads <name>.yaml, .yml or .toml from dir. It is not an error for none
+// to exist, but it is for more than one to.
+func readConfigFile(dir, name string, fields []field) (layer, error) {
+	var found []string
+	for _, ext := range configFileTypes {
+		path := filepath.Join(dir, name+"."+ext)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return layer{}, err
+		}
+	}
+	switch len(found) {
+	case 0:
+		return layer{}, nil
+	case 1:
+	default:
+		return layer{}, fmt.Errorf("found %s; keep only one", strings.Join(found, " and "))
+	}
+
+	v := viper.New()
+	v.SetConfigFile(found[0])
+	if err := v.ReadInConfig(); err != nil {
+		return layer{}, fmt.Errorf("reading %s: %w", found[0], err)
+	}
+	l := layer{name: filepath.Base(found[0]), values: make(map[string]any)}
+	var unknown []string
+	for _, key := range v.AllKeys() {
+		upper := strings.ToUpper(key)
+		if !known(fields, upper) {
+			unknown = append(unknown, key)
+			continue
+		}
+		l.values[upper] = v.Get(key)
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return l, fmt.Errorf("%s: unknown key(s) %s", l.name, strings.Join(unknown, ", "))
+	}
+	return l, nil
+}
+
+// readDotEnv reads .env from dir. Unknown keys only warn: the file is often shared with
+// other tools.
+func readDotEnv(dir string, fields []field) (layer, error) {
+	path := filepath.Join(dir, ".env")
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		log.Println("Config file (.env) not found, relying on environment variables or defaults.")
+		return layer{}, nil
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("env")
+	if err := v.ReadInConfig(); err != nil {
+		return layer{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	l := layer{name: ".env", values: make(map[string]any)}
+	for _, key := range v.AllKeys() {
+		upper := strings.ToUpper(key)
+		if !known(fields, upper) {
+			log.Printf("Warning: .env sets unknown key %s; ignoring it", upper)
+			continue
+		}
+		l.values[upper] = v.Get(key)
+	}
+	return l, nil
+}
+
+// readEnv picks up environment variables for known keys. Empty variables count as unset.
+func readEnv(fields []field) layer {
+	l := layer{name: "env", values: make(map[string]any)}
+	for _, f := range fields {
+		for _, key := range []string{f.key, f.key + "_FILE"} {
+			if v := os.Getenv(key); v != "" {
+				l.values[key] = v
+			}
+		}
+	}
+	return l
+}
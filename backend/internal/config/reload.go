@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// LiveKeys are the settings a reload applies to the running server. Changes to any other
+// key (the database URL, the port, ...) are rejected with a warning until a restart.
+var LiveKeys = []string{"CORS_ALLOWED_ORIGINS", "RATE_LIMIT_PER_MINUTE", "RATE_LIMIT_BURST", "FEATURE_FLAGS", "LOG_LEVEL"}
+
+// ReloadStats counts configuration reloads on this instance since start-up
+type ReloadStats struct {
+	Reloads      uint64     `json:"reloads"`       // Applied, including ones that changed nothing
+	Failures     uint64     `json:"failures"`      // Invalid configuration; the running values were kept
+	RejectedKeys uint64     `json:"rejected_keys"` // Changes to keys outside LiveKeys
+	LastReloadAt *time.Time `json:"last_reload_at"`
+	LastError    string     `json:"last_error,omitempty"`
+	LiveKeys     []string   `json:"live_keys"`
+}
+
+// Reloader holds the running configuration and swaps in the live settings from a fresh
+// Load when asked to reload. Readers call Current on each use and see either the old or
+// the new configuration, never a mix.
+type Reloader struct {
+	opts    Options
+	current atomic.Pointer[Config]
+
+	mu    sync.Mutex // One reload at a time; guards stats and hooks
+	hooks []func(Config)
+	stats ReloadStats
+}
+
+// NewReloader starts from the configuration loaded at start-up with the same options
+func NewReloader(opts Options, initial Config) *Reloader {
+	r := &Reloader{opts: opts}
+	r.current.Store(&initial)
+	return r
+}
+
+// Current returns the running configuration. Callers must not modify it.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// OnReload registers fn to run after each applied reload, e.g. to set the log level
+func (r *Reloader) OnReload(fn func(Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = append(r.hooks, fn)
+}
+
+// Stats returns the reload counters
+func (r *Reloader) Stats() ReloadStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats
+	stats.LiveKeys = LiveKeys
+	return stats
+}
+
+// Reload loads the configuration again and applies the changed live settings. Invalid
+// configuration changes nothing; trigger ("SIGHUP", "file change") is for the logs.
+func (r *Reloader) Reload(trigger string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, _, err := Load(r.opts)
+	if err != nil {
+		r.stats.Failures++
+		r.stats.LastError = err.Error()
+		log.Printf("Error reloading configuration (%s); keeping the running values: %v\n", trigger, err)
+		return err
+	}
+
+	running := r.current.Load()
+	applied := *running
+	old, fresh, target := reflect.ValueOf(running).Elem(), reflect.ValueOf(next), reflect.ValueOf(&applied).Elem()
+	var changed []string
+	for _, f := range configFields() {
+		if old.Field(f.index).Equal(fresh.Field(f.index)) {
+			continue
+		}
+		if !slices.Contains(LiveKeys, f.key) {
+			r.stats.RejectedKeys++
+			log.Printf("Warning: configuration reload (%s): %s changed but only takes effect after a restart; keeping the running value", trigger, f.key)
+			continue
+		}
+		target.Field(f.index).Set(fresh.Field(f.index))
+		changed = append(changed, f.key+"="+Mask(f.key, fmt.Sprint(fresh.Field(f.index).Interface())))
+	}
+	r.current.Store(&applied)
+
+	now := time.Now()
+	r.stats.Reloads++
+	r.stats.LastReloadAt = &now
+	r.stats.LastError = ""
+	if len(changed) == 0 {
+		log.Printf("Configuration reloaded (%s): no live settings changed", trigger)
+	} else {
+		log.Printf("Configuration reloaded (%s): %s", trigger, strings.Join(changed, ", "))
+	}
+	for _, fn := range r.hooks {
+		fn(applied)
+	}
+	return nil
+}
+
+// Watch reloads on SIGHUP, and when a config file or .env changes as seen by checking
+// them every interval (0 checks none). It returns when ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	seen := r.fingerprint()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.Reload("SIGHUP")
+		case <-tick:
+			if now := r.fingerprint(); !maps.Equal(now, seen) {
+				seen = now
+				r.Reload("file change")
+			}
+		}
+	}
+}
+
+// fingerprint records the size and modification time of each file Load may read; a
+// missing file is recorded too, so creating it counts as a change
+func (r *Reloader) fingerprint() map[string]string {
+	profile := r.Current().Profile
+	var names []string
+	for _, ext := range configFileTypes {
+		names = append(names, "config."+ext, "config."+profile+"."+ext)
+	}
+	names = append(names, ".env")
+
+	prints := make(map[string]string, len(names))
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(r.opts.Dir, name)); err == nil {
+			prints[name] = fmt.Sprintf("%d %d", info.Size(), info.ModTime().UnixNano())
+		} else {
+			prints[name] = "missing"
+		}
+	}
+	return prints
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// secretSuffixes mark keys whose values are masked by Mask: API keys, tokens, passwords
+var secretSuffixes = []string{"_KEY", "_SECRET", "_TOKEN", "_PASSWORD"}
+
+// publicKeys end in a secret suffix but are safe to show
+var publicKeys = []string{"VAPID_PUBLIC_KEY"}
+
+// databaseURLKeys are connection strings; only their password is masked
+var databaseURLKeys = []string{"DATABASE_URL", "DATABASE_LISTEN_URL", "DATABASE_READ_URL"}
+
+// Mask hides a secret value for display. An empty secret stays empty, so it is still
+// visible whether one is set.
+func Mask(key, value string) string {
+	switch {
+	case value == "":
+		return ""
+	case slices.Contains(databaseURLKeys, key):
+		return RedactDatabaseURL(value)
+	case slices.Contains(publicKeys, key):
+		return value
+	}
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return "xxxxx"
+		}
+	}
+	return value
+}
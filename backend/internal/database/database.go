@@ -6,14 +6,15 @@ import (
 	"log"
 	"time"
 
-	"github.com/jackc/pgx/v5" // Import pgx for QueryExecModeSimpleProtocol
+	"github.com/jackc/pgx/v5" // Import pgx for the QueryExecMode constants
 	"github.com/jackc/pgx/v5/pgxpool"
 	"village_project/internal/config" // Adjust import path if needed
 )
 
 // ConnectDB establishes a connection pool to the PostgreSQL database,
-// preferring the DATABASE_URL from config (expected to be Supabase Pooler URL)
-// and disabling prepared statement caching.
+// preferring the DATABASE_URL from config (expected to be Supabase Pooler URL).
+// Pool sizes, lifetimes and the query exec mode come from config (DB_MODE and DB_*):
+// prepared statements are only cached with DB_MODE=direct.
 func ConnectDB(cfg config.Config) (*pgxpool.Pool, error) {
 	log.Println("Attempting to connect to database...")
 
@@ -21,7 +22,7 @@ func ConnectDB(cfg config.Config) (*pgxpool.Pool, error) {
 	var dbURL string
 	if cfg.DatabaseURL != "" {
 		dbURL = cfg.DatabaseURL // Use directly if provided (expected for pooler)
-		log.Printf("Using Database URL from config: %s", dbURL)
+		log.Printf("Using Database URL from config: %s", config.RedactDatabaseURL(dbURL))
 	} else {
 		// Fallback or error if DATABASE_URL is missing
 		// If you still need the *direct* connection logic as a fallback,
@@ -32,43 +33,27 @@ func ConnectDB(cfg config.Config) (*pgxpool.Pool, error) {
 	// -------------------------------------------------------------
 
 
-	// --- Parse Config ---
-	dbConfig, err := pgxpool.ParseConfig(dbURL)
+	// --- Parse Config and Pool Settings ---
+	dbConfig, err := newPoolConfig(cfg, dbURL, "primary")
 	if err != nil {
-		log.Printf("Failed to parse database config using URL '%s': %v\n", dbURL, err)
 		return nil, err
 	}
 
-	// --- Pool Settings ---
-	dbConfig.MaxConns = 10
-	dbConfig.MinConns = 2
-	dbConfig.MaxConnLifetime = time.Hour
-	dbConfig.MaxConnIdleTime = time.Minute * 30
-	dbConfig.ConnConfig.ConnectTimeout = 10 * time.Second // Connection timeout
-
-	// --- DISABLE PREPARED STATEMENT CACHE ---
-	// Required when connecting through PgBouncer (Supabase Pooler)
-	// in transaction pooling mode to avoid "prepared statement already exists" errors.
-	dbConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
-	log.Println("Prepared statement cache disabled (Simple Protocol Mode enabled)")
-	// ---------------------------------------
-
-
 	// --- Connect to Pool ---
 	log.Println("Connecting to database pool...")
 	pool, err := pgxpool.NewWithConfig(context.Background(), dbConfig)
 	if err != nil {
-		log.Printf("Unable to create connection pool with URL '%s': %v\n", dbURL, err)
+		log.Printf("Error creating connection pool with URL '%s': %v\n", config.RedactDatabaseURL(dbURL), err)
 		return nil, err
 	}
 
 	// --- Ping ---
-	pingCtx, cancel := context.WithTimeout(context.Background(), 7*time.Second) // Ping timeout
+	pingCtx, cancel := context.WithTimeout(context.Background(), dbConfig.ConnConfig.ConnectTimeout) // Ping timeout
 	defer cancel()
 	log.Println("Pinging database...")
 	err = pool.Ping(pingCtx)
 	if err != nil {
-		log.Printf("Database ping failed using URL '%s': %v\n", dbURL, err)
+		log.Printf("Error pinging database using URL '%s': %v\n", config.RedactDatabaseURL(dbURL), err)
         pool.Close() // Close pool if ping fails
 		return nil, err
 	}
@@ -76,3 +61,45 @@ func ConnectDB(cfg config.Config) (*pgxpool.Pool, error) {
 	log.Println("Database connection established successfully!")
 	return pool, nil
 }
+
+// execModes maps DB_EXEC_MODE (already validated by config) to pgx's modes
+var execModes = map[string]pgx.QueryExecMode{
+	config.ExecModeCacheStatement: pgx.QueryExecModeCacheStatement,
+	config.ExecModeCacheDescribe:  pgx.QueryExecModeCacheDescribe,
+	config.ExecModeDescribeExec:   pgx.QueryExecModeDescribeExec,
+	config.ExecModeExec:           pgx.QueryExecModeExec,
+	config.ExecModeSimpleProtocol: pgx.QueryExecModeSimpleProtocol,
+}
+
+// newPoolConfig parses dbURL and applies the DB_* pool settings, logging the effective
+// configuration (with the password redacted) under label
+func newPoolConfig(cfg config.Config, dbURL, label string) (*pgxpool.Config, error) {
+	// --- Parse Config ---
+	dbConfig, err := pgxpool.ParseConfig(dbURL)
+	if err != nil {
+		// pgconn's parse errors already redact the password
+		log.Printf("Error parsing database config using URL '%s': %v\n", config.RedactDatabaseURL(dbURL), err)
+		return nil, err
+	}
+
+	// --- Pool Settings ---
+	dbConfig.MaxConns = int32(cfg.DBMaxConns)
+	dbConfig.MinConns = int32(cfg.DBMinConns)
+	dbConfig.MaxConnLifetime = time.Duration(cfg.DBMaxConnLifetimeMinutes) * time.Minute
+	dbConfig.MaxConnIdleTime = time.Duration(cfg.DBMaxConnIdleMinutes) * time.Minute
+	dbConfig.HealthCheckPeriod = time.Duration(cfg.DBHealthCheckSeconds) * time.Second
+	dbConfig.ConnConfig.ConnectTimeout = time.Duration(cfg.DBConnectTimeoutSeconds) * time.Second
+
+	// --- Query Exec Mode ---
+	// PgBouncer (Supabase Pooler) in transaction pooling mode shares server connections
+	// between clients, so cached prepared statements fail with "prepared statement already
+	// exists". DB_MODE=pooler therefore only allows simple_protocol or exec (unnamed statements).
+	dbConfig.ConnConfig.DefaultQueryExecMode = execModes[cfg.DBExecMode]
+	dbConfig.ConnConfig.StatementCacheCapacity = cfg.DBStatementCacheCapacity
+	dbConfig.ConnConfig.DescriptionCacheCapacity = cfg.DBStatementCacheCapacity
+	log.Printf("Database pool (%s): mode=%s exec_mode=%s statement_cache=%d max_conns=%d min_conns=%d max_conn_lifetime=%s max_conn_idle=%s health_check=%s connect_timeout=%s url=%s",
+		label, cfg.DBMode, cfg.DBExecMode, cfg.DBStatementCacheCapacity, dbConfig.MaxConns, dbConfig.MinConns,
+		dbConfig.MaxConnLifetime, dbConfig.MaxConnIdleTime, dbConfig.HealthCheckPeriod,
+		dbConfig.ConnConfig.ConnectTimeout, config.RedactDatabaseURL(dbURL))
+	return dbConfig, nil
+}
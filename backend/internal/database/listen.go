@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Listen holds a dedicated connection LISTENing on channel and calls handle for each
+// notification until ctx is cancelled. The URL must be a session connection: the
+// transaction pooler drops LISTEN. After every (re)connect, once LISTEN is in place,
+// onConnect is called with reconnected set when an earlier connection was lost, so the
+// caller can catch up on what it may have missed. Connection loss is retried with
+// backoff from one second up to thirty.
+func Listen(ctx context.Context, url, channel string, onConnect func(ctx context.Context, reconnected bool), handle func(*pgconn.Notification)) {
+	delay := time.Second
+	connected := false
+	for {
+		err := listenOnce(ctx, url, channel, func(ctx context.Context) {
+			if onConnect != nil {
+				onConnect(ctx, connected)
+			}
+			connected = true
+			delay = time.Second
+		}, handle)
+		if ctx.Err() != nil {
+			log.Printf("Listener on %s stopped", channel)
+			return
+		}
+		log.Printf("Warning: LISTEN %s connection lost: %v; reconnecting in %s", channel, err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, 30*time.Second)
+	}
+}
+
+func listenOnce(ctx context.Context, url, channel string, onConnect func(context.Context), handle func(*pgconn.Notification)) error {
+	connConfig, err := pgx.ParseConfig(url)
+	if err != nil {
+		return err
+	}
+	connConfig.DefaultQueryExecMode = pgx.QueryExecModeSimpleProtocol
+	conn, err := pgx.ConnectConfig(ctx, connConfig)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+	log.Printf("Listening on %s", channel)
+	onConnect(ctx)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(n)
+	}
+}
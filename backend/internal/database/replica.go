@@ -0,0 +1,176 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"village_project/internal/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pools routes queries between the primary and an optional read replica. Writes and
+// transactions always use Primary; read-only queries ask Reader, which picks the replica
+// unless it is missing, unhealthy or too far behind, the request asked for the primary
+// (WithPrimary), or a write to the collection being read was seen within the
+// read-your-writes window.
+type Pools struct {
+	Primary *pgxpool.Pool
+	Replica *pgxpool.Pool // nil without DATABASE_READ_URL
+
+	window   time.Duration // Read-your-writes window after a write
+	maxLag   time.Duration // Replica is skipped when replay lags by more than this
+	interval time.Duration // Between replica health checks
+
+	mu        sync.Mutex
+	status    ReplicaStatus
+	lastWrite map[string]time.Time // By collection ("news", "jobs")
+}
+
+// ReplicaStatus is reported by the health check
+type ReplicaStatus struct {
+	Configured bool      `json:"configured"`
+	Healthy    bool      `json:"healthy"`
+	LagSeconds float64   `json:"lag_seconds"`
+	CheckedAt  time.Time `json:"checked_at,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// NewPools wraps the primary pool and, when DATABASE_READ_URL is set, creates the
+// replica pool with the same DB_* settings. An unreachable replica is not fatal: it
+// starts unhealthy and reads use the primary until a health check succeeds.
+func NewPools(cfg config.Config, primary *pgxpool.Pool) (*Pools, error) {
+	p := &Pools{
+		Primary:   primary,
+		lastWrite: map[string]time.Time{},
+		window:    time.Duration(cfg.DatabaseReadYourWritesSeconds) * time.Second,
+		maxLag:    time.Duration(cfg.DatabaseReadMaxLagSeconds) * time.Second,
+		interval:  time.Duration(cfg.DatabaseReadCheckSeconds) * time.Second,
+	}
+	if cfg.DatabaseReadURL == "" {
+		return p, nil
+	}
+
+	dbConfig, err := newPoolConfig(cfg, cfg.DatabaseReadURL, "replica")
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	p.Replica, err = pgxpool.NewWithConfig(context.Background(), dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	p.status.Configured = true
+	p.check(context.Background())
+	return p, nil
+}
+
+// Close closes the replica pool; the primary is closed by its owner
+func (p *Pools) Close() {
+	if p.Replica != nil {
+		p.Replica.Close()
+	}
+}
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that Reader returns the primary, e.g. for a request that
+// writes or follows a recent write by the same client, and for background workers
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether ctx was marked by WithPrimary
+func UsesPrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// Writer returns the pool for writes and transactions
+func (p *Pools) Writer() *pgxpool.Pool {
+	return p.Primary
+}
+
+// Reader returns the pool for a read-only query of collection. Writes to other
+// collections do not keep it on the primary.
+func (p *Pools) Reader(ctx context.Context, collection string) *pgxpool.Pool {
+	if p.Replica == nil || UsesPrimary(ctx) {
+		return p.Primary
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.status.Healthy || time.Since(p.lastWrite[collection]) < p.window {
+		return p.Primary
+	}
+	return p.Replica
+}
+
+// NoteWrite starts the read-your-writes window for collection. It is called for every
+// write this instance hears about (see cache.Registry.OnInvalidate), so a cache refilled
+// right after an invalidation is not filled from a replica that has not replayed the
+// write yet. Only reads of that collection move to the primary.
+func (p *Pools) NoteWrite(collection string) {
+	p.mu.Lock()
+	p.lastWrite[collection] = time.Now()
+	p.mu.Unlock()
+}
+
+// ReplicaStatus returns the result of the latest health check
+func (p *Pools) ReplicaStatus() ReplicaStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// MonitorReplica checks the replica every DATABASE_READ_CHECK_SECONDS until ctx is cancelled
+func (p *Pools) MonitorReplica(ctx context.Context) {
+	if p.Replica == nil {
+		return
+	}
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.check(ctx)
+		}
+	}
+}
+
+// check pings the replica and measures how far its WAL replay is behind. Lag is taken as
+// zero on an idle primary, where the last replayed transaction is simply old.
+func (p *Pools) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var lag float64
+	err := p.Replica.QueryRow(ctx, `
+		SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN 0
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		END::float8;
+	`).Scan(&lag)
+	if err == nil && lag > p.maxLag.Seconds() {
+		err = fmt.Errorf("replication lag %.1fs exceeds %s", lag, p.maxLag)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	wasHealthy, first := p.status.Healthy, p.status.CheckedAt.IsZero()
+	p.status.CheckedAt = time.Now()
+	p.status.LagSeconds = lag
+	p.status.Healthy = err == nil
+	p.status.Error = ""
+	if err != nil {
+		p.status.Error = err.Error()
+		if wasHealthy || first {
+			log.Printf("Warning: read replica unhealthy, reads fall back to the primary: %v", err)
+		}
+	} else if !wasHealthy {
+		log.Printf("Read replica healthy (lag %.1fs); routing reads to it", lag)
+	}
+}
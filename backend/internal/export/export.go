@@ -0,0 +1,189 @@
+// Package export writes jobs and news as CSV, JSON or NDJSON one item at a time, so an
+// export streamed from the database is never held in memory. CSV columns use the import
+// column names (see package importer) so an exported sheet can be edited and imported.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"village_project/internal/i18n"
+	"village_project/internal/models"
+)
+
+// Supported formats
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"   // One array
+	FormatNDJSON = "ndjson" // One object per line
+)
+
+// Formats lists the supported formats
+var Formats = []string{FormatCSV, FormatJSON, FormatNDJSON}
+
+// ContentType returns the MIME type for a format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Writer encodes items of one kind in one format. Close must be called to finish the
+// output (the closing bracket of a JSON array) and flush it.
+type Writer[T any] struct {
+	format string
+	out    *bufio.Writer
+	csv    *csv.Writer
+	record func(T) []string
+	count  int
+}
+
+var jobHeader = []string{"id", "created_at", "updated_at", "title", "description", "location",
+	"payment_details", "contact_info", "status", "posted_by_user_id", "expires_at",
+	"positions_needed", "auto_fill_on_hire", "language"}
+
+// NewJobWriter returns a Writer for jobs in format
+func NewJobWriter(w io.Writer, format string) (*Writer[models.Job], error) {
+	return newWriter(w, format, jobHeader, func(j models.Job) []string {
+		return []string{j.ID, formatTime(&j.CreatedAt), formatTime(&j.UpdatedAt), j.Title, j.Description,
+			deref(j.Location), deref(j.PaymentDetails), j.ContactInfo, j.Status, deref(j.PostedByUserID),
+			formatTime(j.ExpiresAt), strconv.Itoa(j.PositionsNeeded), strconv.FormatBool(j.AutoFillOnHire), j.Language}
+	})
+}
+
+var newsHeader = []string{"id", "created_at", "updated_at", "title", "content", "published_at", "status", "language"}
+
+// NewNewsWriter returns a Writer for news items in format
+func NewNewsWriter(w io.Writer, format string) (*Writer[models.News], error) {
+	return newWriter(w, format, newsHeader, func(n models.News) []string {
+		return []string{n.ID, formatTime(&n.CreatedAt), formatTime(&n.UpdatedAt), n.Title, deref(n.Content),
+			formatTime(&n.PublishedAt), n.Status, n.Language}
+	})
+}
+
+func newWriter[T any](w io.Writer, format string, header []string, record func(T) []string) (*Writer[T], error) {
+	ew := &Writer[T]{format: format, out: bufio.NewWriterSize(w, 64<<10), record: record}
+	switch format {
+	case FormatCSV:
+		ew.csv = csv.NewWriter(ew.out)
+		if err := ew.csv.Write(header); err != nil {
+			return nil, err
+		}
+	case FormatJSON:
+		if err := ew.out.WriteByte('['); err != nil {
+			return nil, err
+		}
+	case FormatNDJSON:
+	default:
+		return nil, fmt.Errorf("unknown export format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+	return ew, nil
+}
+
+// Write encodes one item
+func (w *Writer[T]) Write(item T) error {
+	w.count++
+	if w.csv != nil {
+		record := w.record(item)
+		for i, cell := range record {
+			record[i] = defuse(cell)
+		}
+		return w.csv.Write(record)
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if w.format == FormatJSON && w.count > 1 {
+		w.out.WriteByte(',')
+	}
+	if w.format == FormatJSON {
+		w.out.WriteByte('\n')
+	}
+	w.out.Write(data)
+	if w.format == FormatNDJSON {
+		w.out.WriteByte('\n')
+	}
+	return nil
+}
+
+// Count returns the number of items written
+func (w *Writer[T]) Count() int {
+	return w.count
+}
+
+// Flush sends buffered output on, e.g. so a slow export shows progress
+func (w *Writer[T]) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.out.Flush()
+}
+
+// Close finishes the output and flushes it; it does not close the underlying writer
+func (w *Writer[T]) Close() error {
+	if w.format == FormatJSON {
+		if w.count > 0 {
+			w.out.WriteByte('\n')
+		}
+		w.out.WriteByte(']')
+		w.out.WriteByte('\n')
+	}
+	return w.Flush()
+}
+
+// formatTime writes times in IST with their offset, which the importer reads back
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.In(i18n.IST).Format(time.RFC3339)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// defuse stops spreadsheet programs from running a cell as a formula: anyone can post a
+// job whose title starts with "=". The quote shows in the cell but nothing executes.
+func defuse(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// ParseDate reads a range bound: a date (YYYY-MM-DD, IST) or an RFC 3339 time. A date
+// used as the upper bound includes that whole day. Empty means unbounded.
+func ParseDate(value string, upper bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, i18n.IST)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a date (use YYYY-MM-DD)", value)
+	}
+	if upper {
+		day = day.AddDate(0, 0, 1)
+	}
+	return &day, nil
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"village_project/internal/export"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAuditLimit is the page size when ?limit= is not given (max 500)
+const defaultAuditLimit = 100
+
+// AuditHandler lets admins search the audit log
+type AuditHandler struct {
+	Repo *repository.AuditRepository
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(repo *repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{Repo: repo}
+}
+
+// auditQuery holds the filters accepted by ListAuditLog
+type auditQuery struct {
+	Actor      string `form:"actor" binding:"omitempty,uuid"`
+	Action     string `form:"action"`
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Before     int64  `form:"before" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// ListAuditLog godoc
+// @Summary Search the audit log (admin only)
+// @Description Changes made through the API, newest first: who (actor, IP, request ID), what (action, target)
+// @Description and the changed fields. Filters combine; pass "before" from a response for the next page.
+// @Description Dates are IST and "to" includes that day. Check the log's integrity with `go run ./cmd/audit -verify`.
+// @Tags audit
+// @Produce json
+// @Param   actor       query string false "User ID"
+// @Param   action      query string false "Handler name, e.g. PublishNews, DeleteJob"
+// @Param   target_type query string false "e.g. news, jobs, grievances"
+// @Param   target_id   query string false "ID of the changed item"
+// @Param   from        query string false "On or after (YYYY-MM-DD or RFC 3339)"
+// @Param   to          query string false "Up to and including (YYYY-MM-DD or RFC 3339)"
+// @Param   before      query int    false "Entries with a smaller ID (next page)"
+// @Param   limit       query int    false "Max entries (default 100, max 500)"
+// @Success 200 {object} models.AuditPage
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/audit [get]
+func (h *AuditHandler) ListAuditLog(c *gin.Context) {
+	var q auditQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter", "details": err.Error()})
+		return
+	}
+	filter := repository.AuditFilter{
+		ActorID:    q.Actor,
+		Action:     q.Action,
+		TargetType: q.TargetType,
+		TargetID:   q.TargetID,
+		Before:     q.Before,
+		Limit:      q.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	var err error
+	if filter.From, err = export.ParseDate(q.From, false); err == nil {
+		filter.To, err = export.ParseDate(q.To, true)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range", "details": err.Error()})
+		return
+	}
+
+	entries, next, err := h.Repo.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve audit log"})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.AuditPage{Entries: entries, Before: next})
+}
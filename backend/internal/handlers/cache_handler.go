@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"village_project/internal/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheHandler exposes the read cache's statistics and lets admins flush it
+type CacheHandler struct {
+	Registry *cache.Registry
+}
+
+// NewCacheHandler creates a new CacheHandler
+func NewCacheHandler(registry *cache.Registry) *CacheHandler {
+	return &CacheHandler{Registry: registry}
+}
+
+// Stats godoc
+// @Summary Read cache statistics
+// @Description Hits, misses, evictions and size per collection on this instance since start-up
+// @Tags admin
+// @Produce json
+// @Success 200 {array} cache.Stats
+// @Router /api/v1/cache/stats [get]
+func (h *CacheHandler) Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Registry.Stats())
+}
+
+// Flush godoc
+// @Summary Clear the read cache on this instance
+// @Tags admin
+// @Produce json
+// @Success 200 {array} cache.Stats
+// @Router /api/v1/cache/flush [post]
+func (h *CacheHandler) Flush(c *gin.Context) {
+	h.Registry.InvalidateAll()
+	log.Println("Handler: Read cache flushed")
+	c.JSON(http.StatusOK, h.Registry.Stats())
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	const etag = `"abc123"`
+	changed := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	before := changed.Add(-time.Hour).Format(http.TimeFormat)
+	same := changed.Format(http.TimeFormat)
+	after := changed.Add(time.Hour).Format(http.TimeFormat)
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		lastModified time.Time
+		want         bool
+	}{
+		{"no validators", nil, changed, false},
+		{"matching etag", map[string]string{"If-None-Match": etag}, changed, true},
+		{"weak etag compares weakly", map[string]string{"If-None-Match": `W/"abc123"`}, changed, true},
+		{"etag in a list", map[string]string{"If-None-Match": `"old", "abc123"`}, changed, true},
+		{"wildcard", map[string]string{"If-None-Match": "*"}, changed, true},
+		{"stale etag", map[string]string{"If-None-Match": `"old"`}, changed, false},
+		{"etag wins over date", map[string]string{"If-None-Match": `"old"`, "If-Modified-Since": after}, changed, false},
+		{"unchanged since", map[string]string{"If-Modified-Since": same}, changed, true},
+		{"unchanged since later", map[string]string{"If-Modified-Since": after}, changed, true},
+		{"changed since", map[string]string{"If-Modified-Since": before}, changed, false},
+		{"unparseable date", map[string]string{"If-Modified-Since": "yesterday"}, changed, false},
+		{"no last modified", map[string]string{"If-Modified-Since": after}, time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := notModified(r, etag, tt.lastModified); got != tt.want {
+				t.Errorf("notModified = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"village_project/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler reports configuration reloads
+type ConfigHandler struct {
+	Reloader *config.Reloader
+}
+
+// NewConfigHandler creates a new ConfigHandler
+func NewConfigHandler(reloader *config.Reloader) *ConfigHandler {
+	return &ConfigHandler{Reloader: reloader}
+}
+
+// ReloadStats godoc
+// @Summary Configuration reloads on this instance
+// @Description Counts of applied and failed reloads (SIGHUP or a config file change) and of rejected
+// @Description changes to settings that need a restart, since start-up. live_keys lists what a reload can change.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} config.ReloadStats
+// @Router /api/v1/config/reloads [get]
+func (h *ConfigHandler) ReloadStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Reloader.Stats())
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+	"village_project/internal/export"
+	"village_project/internal/i18n"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFlushRows is how often a running export is flushed to the client
+const exportFlushRows = 500
+
+var (
+	jobStatuses  = []string{"Pending", "Open", "Filled", "Expired"}
+	newsStatuses = []string{models.NewsStatusDraft, models.NewsStatusPublished}
+)
+
+// ExportHandler streams jobs and news out as files for archives and reports
+type ExportHandler struct {
+	NewsRepo *repository.NewsRepository
+	JobRepo  *repository.JobRepository
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler(newsRepo *repository.NewsRepository, jobRepo *repository.JobRepository) *ExportHandler {
+	return &ExportHandler{NewsRepo: newsRepo, JobRepo: jobRepo}
+}
+
+// ExportJobs godoc
+// @Summary Export jobs as CSV, JSON or NDJSON (admin only)
+// @Description Streams every job (including closed ones, excluding deleted) created in the range,
+// @Description oldest first, in its original language. Dates are IST; "to" includes that day.
+// @Description The CSV columns can be imported again with POST /import/jobs.
+// @Tags export
+// @Produce text/csv,json,application/x-ndjson
+// @Param   format query string false "csv (default), json or ndjson"
+// @Param   from   query string false "Created on or after (YYYY-MM-DD or RFC 3339)"
+// @Param   to     query string false "Created up to and including (YYYY-MM-DD or RFC 3339)"
+// @Param   status query string false "Pending, Open, Filled or Expired"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Bad format, date or status"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/export/jobs [get]
+func (h *ExportHandler) ExportJobs(c *gin.Context) {
+	format, filter, ok := exportParams(c, jobStatuses)
+	if !ok {
+		return
+	}
+	w, err := export.NewJobWriter(c.Writer, format)
+	if err == nil {
+		streamExport(c, "jobs", format, w, func(ctx context.Context, fn func(models.Job) error) (int, error) {
+			return h.JobRepo.ExportJobs(ctx, filter, fn)
+		})
+	}
+}
+
+// ExportNews godoc
+// @Summary Export news as CSV, JSON or NDJSON (admin only)
+// @Description Streams news items (drafts too, unless filtered) published in the range, oldest first.
+// @Description Dates are IST; "to" includes that day.
+// @Tags export
+// @Produce text/csv,json,application/x-ndjson
+// @Param   format query string false "csv (default), json or ndjson"
+// @Param   from   query string false "Published on or after (YYYY-MM-DD or RFC 3339)"
+// @Param   to     query string false "Published up to and including (YYYY-MM-DD or RFC 3339)"
+// @Param   status query string false "draft or published"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string "Bad format, date or status"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/export/news [get]
+func (h *ExportHandler) ExportNews(c *gin.Context) {
+	format, filter, ok := exportParams(c, newsStatuses)
+	if !ok {
+		return
+	}
+	w, err := export.NewNewsWriter(c.Writer, format)
+	if err == nil {
+		streamExport(c, "news", format, w, func(ctx context.Context, fn func(models.News) error) (int, error) {
+			return h.NewsRepo.ExportNews(ctx, filter, fn)
+		})
+	}
+}
+
+// exportParams validates the format and filter query parameters
+func exportParams(c *gin.Context, statuses []string) (string, repository.ExportFilter, bool) {
+	var filter repository.ExportFilter
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if !slices.Contains(export.Formats, format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown format", "supported": export.Formats})
+		return "", filter, false
+	}
+	var err error
+	if filter.From, err = export.ParseDate(c.Query("from"), false); err == nil {
+		filter.To, err = export.ParseDate(c.Query("to"), true)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range", "details": err.Error()})
+		return "", filter, false
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range", "details": "from must be before to"})
+		return "", filter, false
+	}
+	filter.Status = c.Query("status")
+	if filter.Status != "" && !slices.Contains(statuses, filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown status", "supported": statuses})
+		return "", filter, false
+	}
+	return format, filter, true
+}
+
+// streamExport writes rows to the response as the repository reads them. A failing
+// query still gets a 500; once rows have been sent an error cuts the response short
+// and is only logged (a JSON array is then left unterminated).
+func streamExport[T any](c *gin.Context, kind, format string, w *export.Writer[T], run func(context.Context, func(T) error) (int, error)) {
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		name := fmt.Sprintf("%s-%s.%s", kind, time.Now().In(i18n.IST).Format("20060102-150405"), format)
+		c.Header("Content-Type", export.ContentType(format))
+		c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+		c.Header("Cache-Control", "no-store")
+		c.Status(http.StatusOK)
+	}
+
+	count, err := run(c.Request.Context(), func(item T) error {
+		start() // Before the writer's buffer can fill and send the body
+		if err := w.Write(item); err != nil {
+			return err
+		}
+		if w.Count()%exportFlushRows == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export " + kind})
+		}
+		return
+	}
+	start()
+	if err := w.Close(); err != nil {
+		log.Printf("Error writing %s export: %v\n", kind, err)
+		return
+	}
+	log.Printf("Exported %d %s as %s\n", count, kind, format)
+}
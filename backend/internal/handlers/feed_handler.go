@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"village_project/internal/feeds"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedItemLimit caps the number of entries in each feed
+const feedItemLimit = 50
+
+// FeedHandler serves RSS and Atom feeds for news and open jobs
+type FeedHandler struct {
+	News         *repository.NewsRepository
+	Jobs         *repository.JobRepository
+	AppURL       string // Item links point at the app, not the API
+	CacheControl string
+}
+
+// NewFeedHandler creates a new FeedHandler
+func NewFeedHandler(news *repository.NewsRepository, jobs *repository.JobRepository, appURL, cacheControl string) *FeedHandler {
+	return &FeedHandler{News: news, Jobs: jobs, AppURL: strings.TrimRight(appURL, "/"), CacheControl: cacheControl}
+}
+
+// NewsRSS godoc
+// @Summary Published news as RSS 2.0
+// @Tags news
+// @Produce xml
+// @Success 200 {string} string "RSS document"
+// @Success 304 "Not modified"
+// @Router /api/v1/news/feed.rss [get]
+func (h *FeedHandler) NewsRSS(c *gin.Context) { h.serveNews(c, "application/rss+xml", feeds.RSS) }
+
+// NewsAtom godoc
+// @Summary Published news as Atom 1.0
+// @Tags news
+// @Produce xml
+// @Success 200 {string} string "Atom document"
+// @Success 304 "Not modified"
+// @Router /api/v1/news/feed.atom [get]
+func (h *FeedHandler) NewsAtom(c *gin.Context) { h.serveNews(c, "application/atom+xml", feeds.Atom) }
+
+// JobsRSS godoc
+// @Summary Open job postings as RSS 2.0
+// @Tags jobs
+// @Produce xml
+// @Success 200 {string} string "RSS document"
+// @Router /api/v1/jobs/feed.rss [get]
+func (h *FeedHandler) JobsRSS(c *gin.Context) { h.serveJobs(c, "application/rss+xml", feeds.RSS) }
+
+// JobsAtom godoc
+// @Summary Open job postings as Atom 1.0
+// @Tags jobs
+// @Produce xml
+// @Success 200 {string} string "Atom document"
+// @Router /api/v1/jobs/feed.atom [get]
+func (h *FeedHandler) JobsAtom(c *gin.Context) { h.serveJobs(c, "application/atom+xml", feeds.Atom) }
+
+func (h *FeedHandler) serveNews(c *gin.Context, contentType string, render func(feeds.Feed) ([]byte, error)) {
+	newsList, err := h.News.GetRecentPublishedNews(c.Request.Context(), feedItemLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve news"})
+		return
+	}
+
+	feed := feeds.Feed{
+		ID:          h.AppURL + "/news",
+		Title:       "Village News",
+		Description: "Notices and news from the village panchayat",
+		Link:        h.AppURL + "/news",
+		SelfURL:     requestURL(c),
+		Language:    "en-IN",
+	}
+	for _, n := range newsList {
+		item := feeds.Item{
+			ID:        "urn:uuid:" + n.ID,
+			Title:     n.Title,
+			Link:      h.AppURL + "/news/" + n.ID,
+			Published: n.PublishedAt,
+			Updated:   n.UpdatedAt,
+		}
+		if n.Content != nil {
+			item.Content = *n.Content
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	h.serveFeed(c, feed, collectionChangedAt(c, "news", h.News.CollectionVersion), contentType, render)
+}
+
+func (h *FeedHandler) serveJobs(c *gin.Context, contentType string, render func(feeds.Feed) ([]byte, error)) {
+	jobList, err := h.Jobs.GetAllOpenJobs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job listings"})
+		return
+	}
+	if len(jobList) > feedItemLimit {
+		jobList = jobList[:feedItemLimit]
+	}
+
+	feed := feeds.Feed{
+		ID:          h.AppURL + "/jobs",
+		Title:       "Village Jobs",
+		Description: "Open work postings in and around the village",
+		Link:        h.AppURL + "/jobs",
+		SelfURL:     requestURL(c),
+		Language:    "en-IN",
+	}
+	for _, j := range jobList {
+		feed.Items = append(feed.Items, feeds.Item{
+			ID:        "urn:uuid:" + j.ID,
+			Title:     j.Title,
+			Link:      h.AppURL + "/jobs/" + j.ID,
+			Content:   jobFeedContent(j),
+			Published: j.CreatedAt,
+			Updated:   j.UpdatedAt,
+		})
+	}
+	h.serveFeed(c, feed, collectionChangedAt(c, "jobs", h.Jobs.CollectionVersion), contentType, render)
+}
+
+// jobFeedContent lays out the job details as plain text
+func jobFeedContent(j models.Job) string {
+	var b strings.Builder
+	b.WriteString(j.Description)
+	if j.Location != nil && *j.Location != "" {
+		fmt.Fprintf(&b, "\n\nLocation: %s", *j.Location)
+	}
+	if j.PaymentDetails != nil && *j.PaymentDetails != "" {
+		fmt.Fprintf(&b, "\nPayment: %s", *j.PaymentDetails)
+	}
+	if j.PositionsNeeded > 1 {
+		fmt.Fprintf(&b, "\nPositions: %d", j.PositionsNeeded)
+	}
+	fmt.Fprintf(&b, "\nContact: %s", j.ContactInfo)
+	if j.ExpiresAt != nil {
+		fmt.Fprintf(&b, "\nApply before: %s", j.ExpiresAt.Format("02 Jan 2006"))
+	}
+	return b.String()
+}
+
+// collectionChangedAt reads when a collection last changed. Unlike the newest item's
+// update time it also moves when an item is deleted, unpublished or filled. The zero
+// time is returned if the version cannot be read.
+func collectionChangedAt(c *gin.Context, name string, version func(context.Context) (int64, time.Time, error)) time.Time {
+	_, changedAt, err := version(c.Request.Context())
+	if err != nil {
+		log.Printf("Error reading %s collection version: %v\n", name, err)
+		return time.Time{}
+	}
+	return changedAt
+}
+
+// serveFeed answers conditional GETs with 304 and otherwise renders the feed.
+// Last-Modified is the collection's change time, falling back to the newest item.
+func (h *FeedHandler) serveFeed(c *gin.Context, feed feeds.Feed, changedAt time.Time, contentType string, render func(feeds.Feed) ([]byte, error)) {
+	if changedAt.IsZero() {
+		changedAt = feed.Updated()
+	}
+	if conditionalGET(c, feed.ETag(), changedAt, h.CacheControl) {
+		return
+	}
+
+	body, err := render(feed)
+	if err != nil {
+		log.Printf("Error rendering feed %s: %v\n", feed.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render feed"})
+		return
+	}
+	c.Data(http.StatusOK, contentType+"; charset=utf-8", body)
+}
+
+// requestURL reconstructs the public URL of the current request, honouring proxy headers
+func requestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+	"village_project/internal/notify"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GrievanceHandler handles HTTP requests related to grievance tickets
+type GrievanceHandler struct {
+	Repo     *repository.GrievanceRepository
+	Notifier *notify.Service // Tells reporters about status changes; may be nil
+}
+
+// NewGrievanceHandler creates a new GrievanceHandler
+func NewGrievanceHandler(repo *repository.GrievanceRepository, notifier *notify.Service) *GrievanceHandler {
+	return &GrievanceHandler{Repo: repo, Notifier: notifier}
+}
+
+// grievanceID reads the :id path parameter, answering 400 unless it is a UUID (the
+// database would reject it with an error rather than find nothing)
+func grievanceID(c *gin.Context) (string, bool) {
+	var uri struct {
+		ID string `uri:"id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid grievance ID", "details": err.Error()})
+		return "", false
+	}
+	return uri.ID, true
+}
+
+// actorFromContext returns the caller's user ID (nil when anonymous) and a display name for timelines
+func actorFromContext(c *gin.Context) (*string, string) {
+	userID := middleware.UserID(c)
+	if userID == "" {
+		return nil, "Anonymous"
+	}
+	switch middleware.UserRole(c) {
+	case middleware.RoleAdmin:
+		return &userID, "Administrator"
+	case middleware.RoleOfficial:
+		return &userID, "Panchayat Official"
+	default:
+		return &userID, "Resident"
+	}
+}
+
+// ListCategories godoc
+// @Summary List grievance categories
+// @Tags grievances
+// @Produce json
+// @Success 200 {array} models.GrievanceCategory
+// @Router /api/v1/grievances/categories [get]
+func (h *GrievanceHandler) ListCategories(c *gin.Context) {
+	categories, err := h.Repo.ListCategories(c.Request.Context())
+	if err != nil {
+		log.Printf("Error getting grievance categories: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve categories"})
+		return
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// CreateGrievance godoc
+// @Summary Report a new grievance
+// @Description Residents report a complaint; login is optional
+// @Tags grievances
+// @Accept  json
+// @Produce json
+// @Param   grievance body models.CreateGrievanceRequest true "Complaint details"
+// @Success 201 {object} models.Grievance "Ticket created"
+// @Failure 400 {object} map[string]string "Invalid input data"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/grievances [post]
+func (h *GrievanceHandler) CreateGrievance(c *gin.Context) {
+	log.Println("Handler: CreateGrievance called")
+	var req models.CreateGrievanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("Error binding JSON for create grievance: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	reporterID, _ := actorFromContext(c)
+	grievance, err := h.Repo.CreateGrievance(c.Request.Context(), req, reporterID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUnknownCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown grievance category"})
+			return
+		}
+		log.Printf("Error creating grievance in repository: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create grievance"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, grievance)
+}
+
+// TrackGrievance godoc
+// @Summary Public status lookup by ticket number
+// @Description Anyone can check the status of a ticket without logging in
+// @Tags grievances
+// @Produce json
+// @Param   ticket path string true "Ticket number (e.g. GRV-000123)"
+// @Success 200 {object} models.PublicGrievanceStatus
+// @Failure 404 {object} map[string]string "Ticket not found"
+// @Router /api/v1/grievances/track/{ticket} [get]
+func (h *GrievanceHandler) TrackGrievance(c *gin.Context) {
+	ticket := c.Param("ticket")
+	status, err := h.Repo.GetPublicStatus(c.Request.Context(), ticket)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Ticket not found"})
+			return
+		}
+		log.Printf("Error tracking grievance %s: %v\n", ticket, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticket status"})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// ListGrievances godoc
+// @Summary List grievances for staff
+// @Tags grievances
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param ward query string false "Filter by ward"
+// @Param category query string false "Filter by category"
+// @Param assigned_to query string false "Filter by assignee UUID ('me' for the caller)"
+// @Param overdue query bool false "Only tickets past their SLA"
+// @Success 200 {array} models.Grievance
+// @Router /api/v1/grievances [get]
+func (h *GrievanceHandler) ListGrievances(c *gin.Context) {
+	filter := models.GrievanceFilter{
+		Status:     c.Query("status"),
+		Ward:       c.Query("ward"),
+		Category:   c.Query("category"),
+		AssignedTo: c.Query("assigned_to"),
+		Overdue:    c.Query("overdue") == "true",
+	}
+	if filter.AssignedTo == "me" {
+		filter.AssignedTo = middleware.UserID(c)
+	}
+
+	grievances, err := h.Repo.ListGrievances(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Error listing grievances: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve grievances"})
+		return
+	}
+	c.JSON(http.StatusOK, grievances)
+}
+
+// GetGrievanceByID godoc
+// @Summary Get a ticket with its full timeline
+// @Tags grievances
+// @Produce json
+// @Param id path string true "Grievance ID (UUID)"
+// @Success 200 {object} map[string]interface{} "Ticket and timeline"
+// @Failure 400 {object} map[string]string "ID is not a UUID"
+// @Failure 404 {object} map[string]string "Ticket not found"
+// @Router /api/v1/grievances/{id} [get]
+func (h *GrievanceHandler) GetGrievanceByID(c *gin.Context) {
+	id, ok := grievanceID(c)
+	if !ok {
+		return
+	}
+	grievance, err := h.Repo.GetGrievanceByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Grievance not found"})
+			return
+		}
+		log.Printf("Error getting grievance %s: %v\n", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve grievance"})
+		return
+	}
+
+	timeline, err := h.Repo.ListComments(c.Request.Context(), id, true)
+	if err != nil {
+		log.Printf("Error getting timeline for grievance %s: %v\n", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve grievance timeline"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"grievance": grievance, "timeline": timeline})
+}
+
+// UpdateGrievanceStatus godoc
+// @Summary Move a ticket through its lifecycle
+// @Description New -> Acknowledged -> In Progress -> Resolved/Rejected
+// @Tags grievances
+// @Accept  json
+// @Produce json
+// @Param id path string true "Grievance ID (UUID)"
+// @Param status body models.UpdateGrievanceStatusRequest true "New status"
+// @Success 200 {object} models.Grievance
+// @Failure 409 {object} map[string]string "Transition not allowed"
+// @Router /api/v1/grievances/{id}/status [put]
+func (h *GrievanceHandler) UpdateGrievanceStatus(c *gin.Context) {
+	id, ok := grievanceID(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateGrievanceStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	actorID, actorName := actorFromContext(c)
+	grievance, err := h.Repo.UpdateStatus(c.Request.Context(), id, req, actorID, actorName)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Grievance not found"})
+		case errors.Is(err, repository.ErrInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Error updating grievance %s status: %v\n", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update grievance"})
+		}
+		return
+	}
+
+	// Let the reporter know without holding up the response; shutdown waits for it
+	h.Notifier.Go(func(ctx context.Context) { h.Notifier.GrievanceUpdated(ctx, grievance, req.Comment) })
+
+	c.JSON(http.StatusOK, grievance)
+}
+
+// AssignGrievance godoc
+// @Summary Assign a ticket to an official
+// @Tags grievances
+// @Accept  json
+// @Produce json
+// @Param id path string true "Grievance ID (UUID)"
+// @Param assignee body models.AssignGrievanceRequest true "Official to assign"
+// @Success 200 {object} models.Grievance
+// @Router /api/v1/grievances/{id}/assign [put]
+func (h *GrievanceHandler) AssignGrievance(c *gin.Context) {
+	id, ok := grievanceID(c)
+	if !ok {
+		return
+	}
+	var req models.AssignGrievanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	actorID, actorName := actorFromContext(c)
+	grievance, err := h.Repo.Assign(c.Request.Context(), id, req.AssigneeID, actorID, actorName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Grievance not found"})
+			return
+		}
+		log.Printf("Error assigning grievance %s: %v\n", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign grievance"})
+		return
+	}
+	c.JSON(http.StatusOK, grievance)
+}
+
+// AddGrievanceComment godoc
+// @Summary Add a note to a ticket's timeline
+// @Tags grievances
+// @Accept  json
+// @Produce json
+// @Param id path string true "Grievance ID (UUID)"
+// @Param comment body models.AddGrievanceCommentRequest true "Comment"
+// @Success 201 {object} models.GrievanceComment
+// @Router /api/v1/grievances/{id}/comments [post]
+func (h *GrievanceHandler) AddGrievanceComment(c *gin.Context) {
+	id, ok := grievanceID(c)
+	if !ok {
+		return
+	}
+	var req models.AddGrievanceCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	actorID, actorName := actorFromContext(c)
+	comment, err := h.Repo.AddComment(c.Request.Context(), id, req, actorID, actorName)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Grievance not found"})
+			return
+		}
+		log.Printf("Error adding comment to grievance %s: %v\n", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"village_project/internal/audit"
+	"village_project/internal/i18n"
+	"village_project/internal/importer"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportHandler handles bulk imports of jobs and news from spreadsheets
+type ImportHandler struct {
+	NewsRepo *repository.NewsRepository
+	JobRepo  *repository.JobRepository
+	Locales  *i18n.Negotiator
+}
+
+// NewImportHandler creates a new ImportHandler
+func NewImportHandler(newsRepo *repository.NewsRepository, jobRepo *repository.JobRepository, locales *i18n.Negotiator) *ImportHandler {
+	return &ImportHandler{NewsRepo: newsRepo, JobRepo: jobRepo, Locales: locales}
+}
+
+// ImportJobs godoc
+// @Summary Bulk import jobs from a CSV or XLSX file (admin only)
+// @Description The first row names the columns: title, description, contact_info (required), location,
+// @Description payment_details, positions_needed, auto_fill_on_hire, language, status (default Open), expires_at.
+// @Description Rows are validated like POST /jobs. With mode=all (default) any bad row saves nothing;
+// @Description with mode=partial good rows are saved and bad ones reported. dry_run=true saves nothing
+// @Description and reports every problem. result=csv returns the per-row result as a CSV file.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json,text/csv
+// @Param   file    formData file   true  "CSV (UTF-8) or XLSX file"
+// @Param   mode    query    string false "all or partial (default all)"
+// @Param   dry_run query    bool   false "Validate and report without saving"
+// @Param   result  query    string false "csv for a result file instead of JSON"
+// @Success 200 {object} models.ImportReport "Imported, or dry run"
+// @Failure 400 {object} map[string]string "Missing or unreadable file, or bad parameters"
+// @Failure 422 {object} models.ImportReport "All-or-nothing import aborted; nothing was saved"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/import/jobs [post]
+func (h *ImportHandler) ImportJobs(c *gin.Context) {
+	mode, dryRun, table, ok := h.readUpload(c)
+	if !ok {
+		return
+	}
+	rows, err := importer.ParseJobs(table, h.Locales)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read the file", "details": err.Error()})
+		return
+	}
+	postedBy, _ := actorFromContext(c)
+	report, err := h.JobRepo.ImportJobs(c.Request.Context(), rows, mode, dryRun, postedBy)
+	h.respond(c, report, err)
+}
+
+// ImportNews godoc
+// @Summary Bulk import news from a CSV or XLSX file (admin only)
+// @Description Columns: title (required), content, language, status (draft or published; default draft)
+// @Description or publish (yes/no), published_at. A published row with published_at is backdated archive
+// @Description material and is not announced. Modes, dry runs and result files work as for jobs.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json,text/csv
+// @Param   file    formData file   true  "CSV (UTF-8) or XLSX file"
+// @Param   mode    query    string false "all or partial (default all)"
+// @Param   dry_run query    bool   false "Validate and report without saving"
+// @Param   result  query    string false "csv for a result file instead of JSON"
+// @Success 200 {object} models.ImportReport "Imported, or dry run"
+// @Failure 400 {object} map[string]string "Missing or unreadable file, or bad parameters"
+// @Failure 422 {object} models.ImportReport "All-or-nothing import aborted; nothing was saved"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/import/news [post]
+func (h *ImportHandler) ImportNews(c *gin.Context) {
+	mode, dryRun, table, ok := h.readUpload(c)
+	if !ok {
+		return
+	}
+	rows, err := importer.ParseNews(table, h.Locales)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read the file", "details": err.Error()})
+		return
+	}
+	report, err := h.NewsRepo.ImportNews(c.Request.Context(), rows, mode, dryRun)
+	h.respond(c, report, err)
+}
+
+// readUpload checks the query parameters and reads the uploaded file into a table
+func (h *ImportHandler) readUpload(c *gin.Context) (mode string, dryRun bool, table [][]string, ok bool) {
+	mode = c.DefaultQuery("mode", models.ImportModeAll)
+	if mode != models.ImportModeAll && mode != models.ImportModePartial {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be all or partial"})
+		return
+	}
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+	if result := c.Query("result"); result != "" && result != "csv" && result != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "result must be csv or json"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importer.MaxFileSize+1<<20) // Room for the multipart framing
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Upload the spreadsheet in the \"file\" field", "details": err.Error()})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read the file", "details": err.Error()})
+		return
+	}
+	defer file.Close()
+	table, err = importer.ReadTable(header.Filename, file)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, importer.ErrTooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		c.JSON(status, gin.H{"error": "Could not read the file", "details": err.Error()})
+		return
+	}
+	return mode, dryRun, table, true
+}
+
+// respond sends the report as JSON or, with ?result=csv, as a downloadable result file
+func (h *ImportHandler) respond(c *gin.Context, report models.ImportReport, err error) {
+	if err != nil {
+		log.Printf("Error importing %s: %v\n", report.Kind, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Import failed; nothing was saved"})
+		return
+	}
+	if report.DryRun {
+		audit.Skip(c) // Nothing changed
+	}
+	audit.Target(c, report.Kind, "")
+	audit.After(c, gin.H{"mode": report.Mode, "total": report.Total, "created": report.Created,
+		"invalid": report.Invalid, "failed": report.Failed, "skipped": report.Skipped})
+	status := http.StatusOK
+	if !report.DryRun && !report.Committed {
+		status = http.StatusUnprocessableEntity
+	}
+	if c.Query("result") != "csv" {
+		c.JSON(status, report)
+		return
+	}
+	name := fmt.Sprintf("import-%s-%s.csv", report.Kind, time.Now().In(i18n.IST).Format("20060102-150405"))
+	c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	c.Header("X-Import-Committed", strconv.FormatBool(report.Committed))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(status)
+	if err := importer.WriteResultCSV(c.Writer, report); err != nil {
+		log.Printf("Error writing import result: %v\n", err)
+	}
+}
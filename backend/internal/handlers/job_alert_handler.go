@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobAlertHandler handles HTTP requests related to saved job alerts
+type JobAlertHandler struct {
+	Repo *repository.JobAlertRepository
+}
+
+// NewJobAlertHandler creates a new JobAlertHandler
+func NewJobAlertHandler(repo *repository.JobAlertRepository) *JobAlertHandler {
+	return &JobAlertHandler{Repo: repo}
+}
+
+// CreateAlert godoc
+// @Summary Save a job alert
+// @Tags job-alerts
+// @Accept  json
+// @Produce json
+// @Param   alert body models.CreateJobAlertRequest true "Keywords, location and minimum pay"
+// @Success 201 {object} models.JobAlert
+// @Router /api/v1/job-alerts [post]
+func (h *JobAlertHandler) CreateAlert(c *gin.Context) {
+	var req models.CreateJobAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	alert, err := h.Repo.CreateAlert(c.Request.Context(), middleware.UserID(c), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save job alert"})
+		return
+	}
+	c.JSON(http.StatusCreated, alert)
+}
+
+// ListAlerts godoc
+// @Summary List my job alerts
+// @Tags job-alerts
+// @Produce json
+// @Success 200 {array} models.JobAlert
+// @Router /api/v1/job-alerts [get]
+func (h *JobAlertHandler) ListAlerts(c *gin.Context) {
+	alerts, err := h.Repo.ListAlerts(c.Request.Context(), middleware.UserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job alerts"})
+		return
+	}
+	c.JSON(http.StatusOK, alerts)
+}
+
+// UpdateAlert godoc
+// @Summary Pause or resume a job alert
+// @Tags job-alerts
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Alert ID (UUID)"
+// @Param   update body models.UpdateJobAlertRequest true "Paused flag"
+// @Success 200 {object} models.JobAlert
+// @Router /api/v1/job-alerts/{id} [put]
+func (h *JobAlertHandler) UpdateAlert(c *gin.Context) {
+	var req models.UpdateJobAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	alert, err := h.Repo.SetPaused(c.Request.Context(), middleware.UserID(c), c.Param("id"), *req.Paused)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job alert not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job alert"})
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// DeleteAlert godoc
+// @Summary Delete a job alert
+// @Tags job-alerts
+// @Param   id path string true "Alert ID (UUID)"
+// @Success 204
+// @Router /api/v1/job-alerts/{id} [delete]
+func (h *JobAlertHandler) DeleteAlert(c *gin.Context) {
+	err := h.Repo.DeleteAlert(c.Request.Context(), middleware.UserID(c), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job alert not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job alert"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListNotifications godoc
+// @Summary List jobs that matched my alerts
+// @Tags job-alerts
+// @Produce json
+// @Success 200 {array} models.JobAlertNotification
+// @Router /api/v1/job-alerts/notifications [get]
+func (h *JobAlertHandler) ListNotifications(c *gin.Context) {
+	notifications, err := h.Repo.ListNotifications(c.Request.Context(), middleware.UserID(c))
+	if err != nil {
+		log.Printf("Error listing alert notifications: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, notifications)
+}
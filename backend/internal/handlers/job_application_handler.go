@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// JobApplicationHandler handles HTTP requests related to job applications
+type JobApplicationHandler struct {
+	Repo *repository.JobApplicationRepository
+	Jobs *repository.JobRepository
+}
+
+// NewJobApplicationHandler creates a new JobApplicationHandler
+func NewJobApplicationHandler(repo *repository.JobApplicationRepository, jobs *repository.JobRepository) *JobApplicationHandler {
+	return &JobApplicationHandler{Repo: repo, Jobs: jobs}
+}
+
+// CreateApplication godoc
+// @Summary Express interest in a job
+// @Description Workers leave a name, phone, short note and availability
+// @Tags jobs
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Job ID (UUID)"
+// @Param   application body models.CreateJobApplicationRequest true "Applicant details"
+// @Success 201 {object} models.JobApplication
+// @Failure 400 {object} map[string]string "Invalid input data"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Failure 409 {object} map[string]string "Already applied or job closed"
+// @Router /api/v1/jobs/{id}/applications [post]
+func (h *JobApplicationHandler) CreateApplication(c *gin.Context) {
+	jobID, ok := jobIDParam(c)
+	if !ok {
+		return
+	}
+	log.Printf("Handler: CreateApplication called for job %s", jobID)
+
+	var req models.CreateJobApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("Error binding JSON for job application: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	applicantID, _ := actorFromContext(c)
+	app, err := h.Repo.CreateApplication(c.Request.Context(), jobID, req, applicantID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		case errors.Is(err, repository.ErrJobNotOpen):
+			c.JSON(http.StatusConflict, gin.H{"error": "This job is no longer accepting applications"})
+		case errors.Is(err, repository.ErrAlreadyApplied):
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already applied to this job"})
+		default:
+			log.Printf("Error creating application in repository: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, app)
+}
+
+// ListApplications godoc
+// @Summary List applicants for a job
+// @Description Only the job's poster (or staff) can see applicants
+// @Tags jobs
+// @Produce json
+// @Param   id path string true "Job ID (UUID)"
+// @Success 200 {array} models.JobApplication
+// @Failure 400 {object} map[string]string "Invalid job ID"
+// @Failure 403 {object} map[string]string "Not the poster of this job"
+// @Router /api/v1/jobs/{id}/applications [get]
+func (h *JobApplicationHandler) ListApplications(c *gin.Context) {
+	jobID, ok := jobIDParam(c)
+	if !ok {
+		return
+	}
+	if !h.authorizePoster(c, jobID) {
+		return
+	}
+
+	apps, err := h.Repo.ListApplications(c.Request.Context(), jobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve applications"})
+		return
+	}
+	c.JSON(http.StatusOK, apps)
+}
+
+// UpdateApplication godoc
+// @Summary Shortlist, hire or decline an applicant
+// @Tags jobs
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Job ID (UUID)"
+// @Param   applicationId path string true "Application ID (UUID)"
+// @Param   update body models.UpdateJobApplicationRequest true "New status"
+// @Success 200 {object} models.JobApplicationUpdateResult
+// @Failure 400 {object} map[string]string "Invalid ID or input data"
+// @Failure 403 {object} map[string]string "Not the poster of this job"
+// @Router /api/v1/jobs/{id}/applications/{applicationId} [put]
+func (h *JobApplicationHandler) UpdateApplication(c *gin.Context) {
+	var uri struct {
+		JobID         string `uri:"id" binding:"required,uuid"`
+		ApplicationID string `uri:"applicationId" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job or application ID", "details": err.Error()})
+		return
+	}
+	jobID, applicationID := uri.JobID, uri.ApplicationID
+
+	var req models.UpdateJobApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	if !h.authorizePoster(c, jobID) {
+		return
+	}
+
+	result, err := h.Repo.UpdateApplicationStatus(c.Request.Context(), jobID, applicationID, req.Status)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
+		log.Printf("Error updating application %s: %v\n", applicationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// authorizePoster checks that the caller posted the job (or is staff).
+// It writes the error response itself and returns false when access is denied.
+func (h *JobApplicationHandler) authorizePoster(c *gin.Context, jobID string) bool {
+	job, err := h.Jobs.GetJobByID(c.Request.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job details"})
+		return false
+	}
+
+	if middleware.IsStaff(c) {
+		return true
+	}
+	userID := middleware.UserID(c)
+	if job.PostedByUserID == nil || *job.PostedByUserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the poster of this job can review applicants"})
+		return false
+	}
+	return true
+}
@@ -4,6 +4,10 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strings"
+	"time"
+	"village_project/internal/audit"
+	"village_project/internal/middleware"
 	"village_project/internal/models"     // Adjust import path
 	"village_project/internal/repository" // Adjust import path
 
@@ -13,27 +17,56 @@ import (
 
 // JobHandler handles HTTP requests related to jobs
 type JobHandler struct {
-	Repo *repository.JobRepository
+	Repo      *repository.JobRepository
+	Localizer *Localizer
+	Cache     CachePolicy
 }
 
 // NewJobHandler creates a new JobHandler
-func NewJobHandler(repo *repository.JobRepository) *JobHandler {
-	return &JobHandler{Repo: repo}
+func NewJobHandler(repo *repository.JobRepository, localizer *Localizer, cache CachePolicy) *JobHandler {
+	return &JobHandler{Repo: repo, Localizer: localizer, Cache: cache}
 }
 
 // ListOpenJobs godoc
 // @Summary List open job postings
-// @Description Get a list of all jobs currently marked as 'Open'
+// @Description Get a list of all jobs currently marked as 'Open', each in the best language for the request
 // @Tags jobs
 // @Accept  json
 // @Produce json
+// @Param   lang query string false "Preferred language (overrides Accept-Language)"
+// @Param   q    query string false "Search in Telugu, romanized Telugu or English (e.g. pani, koolie, వరి)"
 // @Success 200 {array} models.Job "Successfully retrieved list of open jobs"
+// @Success 304 "Not modified (If-None-Match / If-Modified-Since)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/jobs [get]
 func (h *JobHandler) ListOpenJobs(c *gin.Context) {
 	log.Println("Handler: ListOpenJobs called")
+	prefs, ok := h.Localizer.preferences(c)
+	if !ok {
+		return
+	}
 
-	jobList, err := h.Repo.GetAllOpenJobs(c.Request.Context())
+	// The collection version answers conditional requests before the list is queried
+	q := c.Query("q")
+	if version, changedAt, err := h.Repo.CollectionVersion(c.Request.Context()); err == nil {
+		etag := strongETag("jobs", version, q, strings.Join(prefs, ","))
+		if conditionalGET(c, etag, changedAt, h.Cache.List) {
+			return
+		}
+	} else {
+		log.Printf("Error reading jobs collection version: %v\n", err)
+	}
+
+	var jobList []models.Job
+	var err error
+	if q != "" {
+		jobList, err = h.Repo.SearchOpenJobs(c.Request.Context(), q)
+	} else {
+		jobList, err = h.Repo.GetAllOpenJobs(c.Request.Context())
+	}
+	if err == nil {
+		_, err = h.Localizer.localizeJobs(c.Request.Context(), prefs, jobList)
+	}
 	if err != nil {
 		log.Printf("Error getting open jobs from repository: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job listings"})
@@ -44,6 +77,18 @@ func (h *JobHandler) ListOpenJobs(c *gin.Context) {
 	c.JSON(http.StatusOK, jobList)
 }
 
+// jobIDParam binds the job ID from the URL, answering 400 itself if it is not a UUID
+func jobIDParam(c *gin.Context) (string, bool) {
+	var uri struct {
+		ID string `uri:"id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID", "details": err.Error()})
+		return "", false
+	}
+	return uri.ID, true
+}
+
 // GetJobByID godoc
 // @Summary Get a single job by ID
 // @Description Get details of a specific job using its UUID
@@ -51,16 +96,31 @@ func (h *JobHandler) ListOpenJobs(c *gin.Context) {
 // @Accept  json
 // @Produce json
 // @Param   id   path      string  true  "Job ID (UUID)"
+// @Param   lang query     string  false "Preferred language (overrides Accept-Language)"
 // @Success 200 {object} models.Job "Successfully retrieved job"
-// @Failure 400 {object} map[string]string "Invalid ID format" // Not implemented yet
+// @Success 304 "Not modified (If-None-Match / If-Modified-Since)"
+// @Failure 400 {object} map[string]string "Invalid ID format"
 // @Failure 404 {object} map[string]string "Job not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /api/v1/jobs/{id} [get]
 func (h *JobHandler) GetJobByID(c *gin.Context) {
-	jobID := c.Param("id")
+	jobID, ok := jobIDParam(c)
+	if !ok {
+		return
+	}
 	log.Printf("Handler: GetJobByID called with ID: %s", jobID)
+	prefs, ok := h.Localizer.preferences(c)
+	if !ok {
+		return
+	}
 
 	job, err := h.Repo.GetJobByID(c.Request.Context(), jobID)
+	var changed map[string]time.Time
+	if err == nil {
+		jobs := []models.Job{job}
+		changed, err = h.Localizer.localizeJobs(c.Request.Context(), prefs, jobs)
+		job = jobs[0]
+	}
 	if err != nil {
 		// Check specifically for pgx.ErrNoRows
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -74,6 +134,11 @@ func (h *JobHandler) GetJobByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("Content-Language", job.Language)
+	etag := strongETag("job", job.ID, changed[job.ID].UnixNano(), job.Language, strings.Join(job.AvailableLanguages, ","))
+	if conditionalGET(c, etag, changed[job.ID], h.Cache.Item) {
+		return
+	}
 	log.Printf("Handler: Returning job with ID: %s", jobID)
 	c.JSON(http.StatusOK, job)
 }
@@ -100,9 +165,14 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
 		return
 	}
+	var ok bool
+	if req.Language, ok = h.Localizer.contentLanguage(c, req.Language); !ok {
+		return
+	}
 
-	// Call repository to create the job
-	newJob, err := h.Repo.CreateJob(c.Request.Context(), req)
+	// Call repository to create the job, recording the poster when signed in
+	postedBy, _ := actorFromContext(c)
+	newJob, err := h.Repo.CreateJob(c.Request.Context(), req, postedBy)
 	if err != nil {
 		log.Printf("Error creating job in repository: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job listing"})
@@ -111,5 +181,102 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 
 	log.Printf("Handler: Successfully created job with ID: %s", newJob.ID)
 	// Return 201 Created status and the newly created job object
+	audit.Target(c, "jobs", newJob.ID)
+	audit.After(c, newJob)
 	c.JSON(http.StatusCreated, newJob)
 }
+
+// UpdateJobStatus godoc
+// @Summary Change a job's status
+// @Description Approve (Open), fill or expire a job. Only the poster or staff may do this.
+// @Tags jobs
+// @Accept  json
+// @Produce json
+// @Param   id   path      string  true  "Job ID (UUID)"
+// @Param   status body models.UpdateJobStatusRequest true "New status"
+// @Success 200 {object} models.Job "Successfully updated job"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 403 {object} map[string]string "Not the poster of this job"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Router /api/v1/jobs/{id}/status [put]
+func (h *JobHandler) UpdateJobStatus(c *gin.Context) {
+	jobID, ok := jobIDParam(c)
+	if !ok {
+		return
+	}
+	log.Printf("Handler: UpdateJobStatus called with ID: %s", jobID)
+
+	var req models.UpdateJobStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	job, err := h.Repo.GetJobByID(c.Request.Context(), jobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve job details"})
+		return
+	}
+	// Only staff can approve; posters can fill or expire their own listings
+	isPoster := job.PostedByUserID != nil && *job.PostedByUserID == middleware.UserID(c)
+	if !middleware.IsStaff(c) && (!isPoster || req.Status == "Open" || req.Status == "Pending") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to change this job's status"})
+		return
+	}
+
+	updated, err := h.Repo.UpdateJobStatus(c.Request.Context(), jobID, req.Status)
+	if err != nil {
+		log.Printf("Error updating job %s status: %v\n", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job status"})
+		return
+	}
+	audit.Before(c, job)
+	audit.After(c, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteJob godoc
+// @Summary Delete a job listing
+// @Description Soft delete: the job disappears from lists and feeds, and syncing clients receive a tombstone.
+// @Description Admins can restore it from the trash until it is purged. Only the poster or staff may do this.
+// @Tags jobs
+// @Produce json
+// @Param   id path string true "Job ID (UUID)"
+// @Success 204 "Deleted"
+// @Failure 400 {object} map[string]string "Invalid ID format"
+// @Failure 403 {object} map[string]string "Not the poster of this job"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Router /api/v1/jobs/{id} [delete]
+func (h *JobHandler) DeleteJob(c *gin.Context) {
+	jobID, ok := jobIDParam(c)
+	if !ok {
+		return
+	}
+	job, err := h.Repo.GetJobByID(c.Request.Context(), jobID)
+	if err == nil {
+		isPoster := job.PostedByUserID != nil && *job.PostedByUserID == middleware.UserID(c)
+		if !middleware.IsStaff(c) && !isPoster {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to delete this job"})
+			return
+		}
+		deletedBy, _ := actorFromContext(c)
+		err = h.Repo.DeleteJob(c.Request.Context(), jobID, deletedBy)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		log.Printf("Error deleting job %s: %v\n", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job"})
+		return
+	}
+
+	audit.Before(c, job) // Record what was removed
+	log.Printf("Handler: Deleted job %s", jobID)
+	c.Status(http.StatusNoContent)
+}
@@ -4,6 +4,11 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"village_project/internal/audit"
+	"village_project/internal/models"
 	"village_project/internal/repository" // Adjust import path
 
 	"github.com/gin-gonic/gin"
@@ -11,18 +16,46 @@ import (
 
 // NewsHandler handles HTTP requests related to news
 type NewsHandler struct {
-	Repo *repository.NewsRepository
+	Repo      *repository.NewsRepository
+	Localizer *Localizer
+	Cache     CachePolicy
 }
 
 // NewNewsHandler creates a new NewsHandler
-func NewNewsHandler(repo *repository.NewsRepository) *NewsHandler {
-	return &NewsHandler{Repo: repo}
+func NewNewsHandler(repo *repository.NewsRepository, localizer *Localizer, cache CachePolicy) *NewsHandler {
+	return &NewsHandler{Repo: repo, Localizer: localizer, Cache: cache}
 }
 
-// ListNews fetches all published news
+// ListNews fetches all published news, each in the best language for the request
+// (?lang= or Accept-Language, then the fallback locale, then the original).
+// ?q= searches in Telugu, romanized Telugu or English. Conditional requests are answered
+// from the collection version without querying the list.
 func (h *NewsHandler) ListNews(c *gin.Context) {
 	log.Println("Handler: ListNews called")
-	newsList, err := h.Repo.GetAllPublishedNews(c.Request.Context())
+	prefs, ok := h.Localizer.preferences(c)
+	if !ok {
+		return
+	}
+	q := c.Query("q")
+	if version, changedAt, err := h.Repo.CollectionVersion(c.Request.Context()); err == nil {
+		etag := strongETag("news", version, q, strings.Join(prefs, ","))
+		if conditionalGET(c, etag, changedAt, h.Cache.List) {
+			return
+		}
+	} else {
+		log.Printf("Error reading news collection version: %v\n", err)
+	}
+
+	var newsList []models.News
+	var err error
+	if q != "" {
+		newsList, err = h.Repo.SearchPublishedNews(c.Request.Context(), q)
+	} else {
+		newsList, err = h.Repo.GetAllPublishedNews(c.Request.Context())
+	}
+	if err == nil {
+		_, err = h.Localizer.localizeNews(c.Request.Context(), prefs, newsList)
+	}
 	if err != nil {
 		log.Printf("Error getting news from repository: %v\n", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve news"})
@@ -37,8 +70,18 @@ func (h *NewsHandler) ListNews(c *gin.Context) {
 func (h *NewsHandler) GetNewsByID(c *gin.Context) {
 	itemID := c.Param("id")
 	log.Printf("Handler: GetNewsByID called with ID: %s", itemID)
+	prefs, ok := h.Localizer.preferences(c)
+	if !ok {
+		return
+	}
 
 	newsItem, err := h.Repo.GetNewsByID(c.Request.Context(), itemID)
+	var changed map[string]time.Time
+	if err == nil {
+		items := []models.News{newsItem}
+		changed, err = h.Localizer.localizeNews(c.Request.Context(), prefs, items)
+		newsItem = items[0]
+	}
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			log.Printf("Handler: News item not found for ID: %s", itemID)
@@ -50,6 +93,175 @@ func (h *NewsHandler) GetNewsByID(c *gin.Context) {
 		return
 	}
 
+	c.Header("Content-Language", newsItem.Language)
+	etag := strongETag("news", newsItem.ID, changed[newsItem.ID].UnixNano(), newsItem.Language, strings.Join(newsItem.AvailableLanguages, ","))
+	if conditionalGET(c, etag, changed[newsItem.ID], h.Cache.Item) {
+		return
+	}
 	log.Printf("Handler: Returning news item with ID: %s", itemID)
 	c.JSON(http.StatusOK, newsItem)
 }
+
+// CreateNews godoc
+// @Summary Post a news item
+// @Description Saves a draft, or publishes immediately when "publish" is true. Publishing notifies subscribers.
+// @Description "language" is the locale the text is written in (default: the fallback locale).
+// @Tags news
+// @Accept  json
+// @Produce json
+// @Param   news body models.CreateNewsRequest true "News item"
+// @Success 201 {object} models.News
+// @Router /api/v1/news [post]
+func (h *NewsHandler) CreateNews(c *gin.Context) {
+	var req models.CreateNewsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	var ok bool
+	if req.Language, ok = h.Localizer.contentLanguage(c, req.Language); !ok {
+		return
+	}
+
+	newsItem, err := h.Repo.CreateNews(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create news item"})
+		return
+	}
+	audit.Target(c, "news", newsItem.ID)
+	audit.After(c, newsItem)
+	c.JSON(http.StatusCreated, newsItem)
+}
+
+// PublishNews godoc
+// @Summary Publish a draft news item
+// @Tags news
+// @Produce json
+// @Param   id path string true "News ID"
+// @Success 200 {object} models.News
+// @Failure 409 {object} map[string]string "Already published"
+// @Router /api/v1/news/{id}/publish [put]
+func (h *NewsHandler) PublishNews(c *gin.Context) {
+	itemID := c.Param("id")
+	newsItem, err := h.Repo.PublishNews(c.Request.Context(), itemID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "News item not found"})
+		case errors.Is(err, repository.ErrAlreadyPublished):
+			c.JSON(http.StatusConflict, gin.H{"error": "News item is already published"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish news item"})
+		}
+		return
+	}
+
+	// Only drafts are published, so that is all that changed
+	audit.Before(c, gin.H{"status": models.NewsStatusDraft})
+	audit.After(c, gin.H{"status": newsItem.Status, "published_at": newsItem.PublishedAt})
+	log.Printf("Handler: Published news item %s", itemID)
+	c.JSON(http.StatusOK, newsItem)
+}
+
+// DeleteNews godoc
+// @Summary Delete a news item
+// @Description Soft delete: the item disappears from lists and feeds, and syncing clients receive a tombstone.
+// @Description Admins can restore it from the trash until it is purged.
+// @Tags news
+// @Produce json
+// @Param   id path string true "News ID"
+// @Success 204 "Deleted"
+// @Failure 404 {object} map[string]string "News item not found"
+// @Router /api/v1/news/{id} [delete]
+func (h *NewsHandler) DeleteNews(c *gin.Context) {
+	itemID := c.Param("id")
+	deletedBy, _ := actorFromContext(c)
+	if newsItem, err := h.Repo.GetNewsByID(c.Request.Context(), itemID); err == nil {
+		audit.Before(c, newsItem) // Record what was removed
+	}
+	if err := h.Repo.DeleteNews(c.Request.Context(), itemID, deletedBy); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "News item not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete news item"})
+		return
+	}
+
+	log.Printf("Handler: Deleted news item %s", itemID)
+	c.Status(http.StatusNoContent)
+}
+
+// GetNewsArchive godoc
+// @Summary Months that have published news, with counts
+// @Description Newest month first. Months are in IST, so an item published at 00:30 IST on
+// @Description 1 March is in March even though it is still February in UTC.
+// @Tags news
+// @Produce json
+// @Success 200 {array} models.NewsArchiveMonth
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/news/archive [get]
+func (h *NewsHandler) GetNewsArchive(c *gin.Context) {
+	if version, changedAt, err := h.Repo.CollectionVersion(c.Request.Context()); err == nil {
+		if conditionalGET(c, strongETag("news-archive", version), changedAt, h.Cache.List) {
+			return
+		}
+	} else {
+		log.Printf("Error reading news collection version: %v\n", err)
+	}
+
+	months, err := h.Repo.GetNewsArchive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve news archive"})
+		return
+	}
+	c.JSON(http.StatusOK, months)
+}
+
+// ListNewsForMonth godoc
+// @Summary Published news for one month of the archive
+// @Description Newest first, localized like GET /news. The month is in IST. A month without news is an empty list.
+// @Tags news
+// @Produce json
+// @Param   year  path  int    true  "Year, e.g. 2025"
+// @Param   month path  int    true  "Month, 1-12"
+// @Param   lang  query string false "Preferred language (overrides Accept-Language)"
+// @Success 200 {array} models.News
+// @Failure 400 {object} map[string]string "Invalid year or month"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /api/v1/news/archive/{year}/{month} [get]
+func (h *NewsHandler) ListNewsForMonth(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil || year < 2000 || year > 9999 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return
+	}
+	month, err := strconv.Atoi(c.Param("month"))
+	if err != nil || month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month; use 1-12"})
+		return
+	}
+	prefs, ok := h.Localizer.preferences(c)
+	if !ok {
+		return
+	}
+	if version, changedAt, err := h.Repo.CollectionVersion(c.Request.Context()); err == nil {
+		etag := strongETag("news-archive", version, year, month, strings.Join(prefs, ","))
+		if conditionalGET(c, etag, changedAt, h.Cache.List) {
+			return
+		}
+	} else {
+		log.Printf("Error reading news collection version: %v\n", err)
+	}
+
+	newsList, err := h.Repo.GetPublishedNewsForMonth(c.Request.Context(), year, time.Month(month))
+	if err == nil {
+		_, err = h.Localizer.localizeNews(c.Request.Context(), prefs, newsList)
+	}
+	if err != nil {
+		log.Printf("Error getting news for %04d-%02d: %v\n", year, month, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve news"})
+		return
+	}
+	c.JSON(http.StatusOK, newsList)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"village_project/internal/audit"
+	"village_project/internal/models"
+	"village_project/internal/notify"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles emergency broadcasts and SMS delivery callbacks
+type NotificationHandler struct {
+	Notifier         *notify.Service
+	SMSRepo          *repository.SMSRepository
+	SMSCallbackToken string
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(notifier *notify.Service, smsRepo *repository.SMSRepository, smsCallbackToken string) *NotificationHandler {
+	return &NotificationHandler{Notifier: notifier, SMSRepo: smsRepo, SMSCallbackToken: smsCallbackToken}
+}
+
+// EmergencyNoticeRequest is an urgent broadcast to all residents
+type EmergencyNoticeRequest struct {
+	Title   string `json:"title" binding:"required,min=3,max=80"`
+	Message string `json:"message" binding:"required,min=5,max=300"`
+}
+
+// SendEmergencyNotice godoc
+// @Summary Broadcast an emergency notice
+// @Description Sends an urgent notice by SMS and email to every reachable resident. SMS bypasses the daily cap.
+// @Tags notifications
+// @Accept  json
+// @Produce json
+// @Param   notice body EmergencyNoticeRequest true "Notice"
+// @Success 202 {object} map[string]string "Broadcast started"
+// @Router /api/v1/notices/emergency [post]
+func (h *NotificationHandler) SendEmergencyNotice(c *gin.Context) {
+	var req EmergencyNoticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	if h.Notifier == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Notifications are not configured"})
+		return
+	}
+
+	log.Printf("Handler: emergency notice %q requested", req.Title)
+	// Shutdown waits for the fan-out instead of cutting it off part way through the residents
+	h.Notifier.Go(func(ctx context.Context) { h.Notifier.EmergencyNotice(ctx, req.Title, req.Message) })
+	c.JSON(http.StatusAccepted, gin.H{"message": "Emergency notice is being sent"})
+}
+
+// smsCallbackPayload accepts the field names used by common gateways (MSG91, Twilio, generic)
+type smsCallbackPayload struct {
+	ID            string `json:"id" form:"id"`
+	RequestID     string `json:"requestId" form:"requestId"`
+	MessageSid    string `json:"MessageSid" form:"MessageSid"`
+	Reference     string `json:"reference" form:"reference"`
+	Status        string `json:"status" form:"status"`
+	MessageStatus string `json:"MessageStatus" form:"MessageStatus"`
+	Description   string `json:"description" form:"description"`
+	ErrorCode     string `json:"ErrorCode" form:"ErrorCode"`
+}
+
+// normalizeSMSStatus maps gateway-specific status words to our SMS statuses
+func normalizeSMSStatus(status string) string {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "delivered", "delivrd", "1":
+		return models.SMSStatusDelivered
+	case "sent", "submitted", "accepted", "queued", "8":
+		return models.SMSStatusSent
+	case "failed", "undelivered", "undeliv", "rejectd", "rejected", "expired", "2", "16":
+		return models.SMSStatusFailed
+	default:
+		return ""
+	}
+}
+
+// SMSDeliveryCallback godoc
+// @Summary Receive an SMS delivery report
+// @Description Called by the SMS gateway; authenticated with ?token=SMS_CALLBACK_TOKEN
+// @Tags notifications
+// @Accept  json
+// @Param   provider path string true "Provider name (http or fake)"
+// @Param   token query string true "Shared callback token"
+// @Success 204
+// @Router /api/v1/sms/callback/{provider} [post]
+func (h *NotificationHandler) SMSDeliveryCallback(c *gin.Context) {
+	audit.Skip(c) // The gateway's status reports are not user actions
+	token := c.Query("token")
+	if h.SMSCallbackToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.SMSCallbackToken)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid callback token"})
+		return
+	}
+
+	var payload smsCallbackPayload
+	if err := c.ShouldBind(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid callback payload", "details": err.Error()})
+		return
+	}
+
+	cb := models.SMSDeliveryCallback{Detail: payload.Description}
+	for _, id := range []string{payload.MessageSid, payload.RequestID, payload.ID, payload.Reference} {
+		if id != "" {
+			cb.ProviderMessageID = id
+			break
+		}
+	}
+	status := payload.Status
+	if payload.MessageStatus != "" {
+		status = payload.MessageStatus
+	}
+	cb.Status = normalizeSMSStatus(status)
+	if cb.Detail == "" && payload.ErrorCode != "" {
+		cb.Detail = "error code " + payload.ErrorCode
+	}
+	if cb.ProviderMessageID == "" || cb.Status == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Callback needs a message ID and a known status"})
+		return
+	}
+
+	err := h.SMSRepo.ApplyDeliveryCallback(c.Request.Context(), c.Param("provider"), cb)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Unknown or already-delivered message; acknowledge so the gateway stops retrying
+			c.Status(http.StatusNoContent)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record delivery status"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
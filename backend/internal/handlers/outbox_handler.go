@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OutboxHandler lets admins inspect domain events and requeue dead letters
+type OutboxHandler struct {
+	Repo *repository.OutboxRepository
+}
+
+// NewOutboxHandler creates a new OutboxHandler
+func NewOutboxHandler(repo *repository.OutboxRepository) *OutboxHandler {
+	return &OutboxHandler{Repo: repo}
+}
+
+// ListEvents godoc
+// @Summary List outbox events
+// @Tags admin
+// @Produce json
+// @Param   status query string false "pending, done or dead"
+// @Param   limit query int false "Max rows (default 100)"
+// @Success 200 {array} models.OutboxEvent
+// @Router /api/v1/outbox [get]
+func (h *OutboxHandler) ListEvents(c *gin.Context) {
+	var filter models.OutboxFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter", "details": err.Error()})
+		return
+	}
+	events, err := h.Repo.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve outbox events"})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// RequeueEvent godoc
+// @Summary Requeue a dead-lettered event
+// @Description Handlers that already succeeded for the event are not run again
+// @Tags admin
+// @Produce json
+// @Param   id path int true "Outbox event ID"
+// @Success 200 {object} models.OutboxEvent
+// @Router /api/v1/outbox/{id}/requeue [post]
+func (h *OutboxHandler) RequeueEvent(c *gin.Context) {
+	event, err := h.Repo.Requeue(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No dead-lettered event with that ID"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to requeue event"})
+		return
+	}
+	log.Printf("Handler: outbox event %d (%s) requeued", event.ID, event.EventType)
+	c.JSON(http.StatusOK, event)
+}
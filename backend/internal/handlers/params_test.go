@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Malformed IDs must be turned away before any handler reaches its repository,
+// so every handler here runs with nil dependencies.
+func TestMalformedIDsAreRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	polls := &PollHandler{}
+	r.GET("/polls/:id", polls.GetPoll)
+	r.GET("/polls/:id/results.csv", polls.ExportResultsCSV)
+	r.POST("/polls/:id/close", polls.ClosePoll)
+	jobs := &JobHandler{}
+	r.GET("/jobs/:id", jobs.GetJobByID)
+	r.PUT("/jobs/:id/status", jobs.UpdateJobStatus)
+	r.DELETE("/jobs/:id", jobs.DeleteJob)
+	r.POST("/trash/:collection/:id/restore", (&TrashHandler{}).RestoreFromTrash)
+	applications := &JobApplicationHandler{}
+	r.POST("/jobs/:id/applications", applications.CreateApplication)
+	r.GET("/jobs/:id/applications", applications.ListApplications)
+	r.PUT("/jobs/:id/applications/:applicationId", applications.UpdateApplication)
+	webhooks := &WebhookHandler{}
+	r.GET("/webhooks/:id", webhooks.GetWebhook)
+	r.PUT("/webhooks/:id", webhooks.UpdateWebhook)
+	r.DELETE("/webhooks/:id", webhooks.DeleteWebhook)
+	r.POST("/webhooks/:id/ping", webhooks.PingWebhook)
+	r.GET("/webhooks/:id/deliveries", webhooks.ListDeliveries)
+	r.POST("/webhooks/deliveries/:deliveryId/redeliver", webhooks.RedeliverDelivery)
+
+	tests := []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/polls/not-a-uuid"},
+		{http.MethodGet, "/polls/1;DROP/results.csv"},
+		{http.MethodPost, "/polls/42/close"},
+		{http.MethodGet, "/jobs/abc"},
+		{http.MethodPut, "/jobs/abc/status"},
+		{http.MethodDelete, "/jobs/abc"},
+		{http.MethodPost, "/trash/jobs/abc/restore"},
+		{http.MethodPost, "/jobs/abc/applications"},
+		{http.MethodGet, "/jobs/abc/applications"},
+		{http.MethodPut, "/jobs/abc/applications/0b6f3a52-3f0e-4c57-9a51-1b7e0c2d9f10"},
+		{http.MethodPut, "/jobs/0b6f3a52-3f0e-4c57-9a51-1b7e0c2d9f10/applications/abc"},
+		{http.MethodGet, "/webhooks/7"},
+		{http.MethodPut, "/webhooks/7"},
+		{http.MethodDelete, "/webhooks/7"},
+		{http.MethodPost, "/webhooks/7/ping"},
+		{http.MethodGet, "/webhooks/7/deliveries"},
+		{http.MethodPost, "/webhooks/deliveries/7/redeliver"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400 (body %s)", w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"village_project/internal/audit"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceTokenHeader identifies a device for anonymous polls and push subscriptions
+const DeviceTokenHeader = "X-Device-Token"
+
+// PollHandler handles HTTP requests related to polls
+type PollHandler struct {
+	Repo *repository.PollRepository
+}
+
+// NewPollHandler creates a new PollHandler
+func NewPollHandler(repo *repository.PollRepository) *PollHandler {
+	return &PollHandler{Repo: repo}
+}
+
+// deviceKey hashes the caller's X-Device-Token so raw tokens are never stored.
+// Tokens shorter than 16 characters are rejected as guessable.
+func deviceKey(c *gin.Context) string {
+	token := c.GetHeader(DeviceTokenHeader)
+	if len(token) < 16 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// voterKey identifies the caller for a poll: the user ID for normal polls,
+// or a hash of the device token for anonymous polls. Returns "" if the caller
+// cannot vote in this mode.
+func voterKey(c *gin.Context, anonymous bool) string {
+	if anonymous {
+		if key := deviceKey(c); key != "" {
+			return "device:" + key
+		}
+		return ""
+	}
+	if userID := middleware.UserID(c); userID != "" {
+		return "user:" + userID
+	}
+	return ""
+}
+
+// pollID binds the poll ID from the URL, answering 400 itself if it is not a UUID
+func pollID(c *gin.Context) (string, bool) {
+	var uri struct {
+		ID string `uri:"id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid poll ID", "details": err.Error()})
+		return "", false
+	}
+	return uri.ID, true
+}
+
+// canSeeResults applies the poll's results visibility setting to the caller
+func canSeeResults(c *gin.Context, poll models.Poll) bool {
+	if middleware.IsStaff(c) || !time.Now().Before(poll.ClosesAt) {
+		return true
+	}
+	return poll.ResultsVisibility == models.PollResultsAfterVote && poll.HasVoted
+}
+
+// CreatePoll godoc
+// @Summary Create a poll
+// @Tags polls
+// @Accept  json
+// @Produce json
+// @Param   poll body models.CreatePollRequest true "Poll details"
+// @Success 201 {object} models.Poll
+// @Failure 400 {object} map[string]string "Invalid input data"
+// @Router /api/v1/polls [post]
+func (h *PollHandler) CreatePoll(c *gin.Context) {
+	var req models.CreatePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	opensAt := req.OpensAt
+	if opensAt.IsZero() {
+		opensAt = time.Now()
+	}
+	if !req.ClosesAt.After(opensAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "closes_at must be after opens_at"})
+		return
+	}
+	if req.MaxChoices != nil && *req.MaxChoices > len(req.Options) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_choices cannot exceed the number of options"})
+		return
+	}
+
+	createdBy, _ := actorFromContext(c)
+	poll, err := h.Repo.CreatePoll(c.Request.Context(), req, createdBy)
+	if err != nil {
+		log.Printf("Error creating poll in repository: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create poll"})
+		return
+	}
+	c.JSON(http.StatusCreated, poll)
+}
+
+// ListPolls godoc
+// @Summary List polls
+// @Tags polls
+// @Produce json
+// @Param active query bool false "Only polls currently open"
+// @Success 200 {array} models.Poll
+// @Router /api/v1/polls [get]
+func (h *PollHandler) ListPolls(c *gin.Context) {
+	polls, err := h.Repo.ListPolls(c.Request.Context(), c.Query("active") == "true")
+	if err != nil {
+		log.Printf("Error listing polls: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve polls"})
+		return
+	}
+	c.JSON(http.StatusOK, polls)
+}
+
+// GetPoll godoc
+// @Summary Get a poll with its options
+// @Tags polls
+// @Produce json
+// @Param id path string true "Poll ID (UUID)"
+// @Success 200 {object} models.Poll
+// @Failure 400 {object} map[string]string "Invalid poll ID"
+// @Failure 404 {object} map[string]string "Poll not found"
+// @Router /api/v1/polls/{id} [get]
+func (h *PollHandler) GetPoll(c *gin.Context) {
+	poll, ok := h.loadPoll(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, poll)
+}
+
+// CastVote godoc
+// @Summary Vote in a poll
+// @Description One ballot per verified resident, or per X-Device-Token for anonymous polls
+// @Tags polls
+// @Accept  json
+// @Produce json
+// @Param id path string true "Poll ID (UUID)"
+// @Param vote body models.CastVoteRequest true "Chosen option IDs"
+// @Success 201 {object} models.PollResults "Vote recorded; results included when visible"
+// @Failure 409 {object} map[string]string "Already voted or poll closed"
+// @Router /api/v1/polls/{id}/votes [post]
+func (h *PollHandler) CastVote(c *gin.Context) {
+	audit.Skip(c) // Recording who voted where would undo the ballot's secrecy
+	poll, ok := h.loadPoll(c)
+	if !ok {
+		return
+	}
+	var req models.CastVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	key := voterKey(c, poll.Anonymous)
+	if key == "" {
+		if poll.Anonymous {
+			c.JSON(http.StatusBadRequest, gin.H{"error": DeviceTokenHeader + " header is required for this poll"})
+		} else {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Please sign in to vote in this poll"})
+		}
+		return
+	}
+
+	err := h.Repo.CastVote(c.Request.Context(), poll.ID, key, req.OptionIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAlreadyVoted):
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already voted in this poll"})
+		case errors.Is(err, repository.ErrPollNotOpen):
+			c.JSON(http.StatusConflict, gin.H{"error": "This poll is not open for voting"})
+		case errors.Is(err, repository.ErrInvalidChoice):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("Error casting vote in poll %s: %v\n", poll.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record vote"})
+		}
+		return
+	}
+
+	poll.HasVoted = true
+	if !canSeeResults(c, poll) {
+		c.JSON(http.StatusCreated, gin.H{"message": "Vote recorded. Results will be shown when the poll closes."})
+		return
+	}
+	results, err := h.Repo.GetResults(c.Request.Context(), poll.ID)
+	if err != nil {
+		c.JSON(http.StatusCreated, gin.H{"message": "Vote recorded"})
+		return
+	}
+	c.JSON(http.StatusCreated, results)
+}
+
+// GetResults godoc
+// @Summary Live tally for a poll
+// @Tags polls
+// @Produce json
+// @Param id path string true "Poll ID (UUID)"
+// @Success 200 {object} models.PollResults
+// @Failure 403 {object} map[string]string "Results not yet visible"
+// @Router /api/v1/polls/{id}/results [get]
+func (h *PollHandler) GetResults(c *gin.Context) {
+	poll, ok := h.loadPoll(c)
+	if !ok {
+		return
+	}
+	if !canSeeResults(c, poll) {
+		msg := "Results are visible after you vote"
+		if poll.ResultsVisibility == models.PollResultsAfterClose {
+			msg = "Results are visible after the poll closes"
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": msg})
+		return
+	}
+
+	results, err := h.Repo.GetResults(c.Request.Context(), poll.ID)
+	if err != nil {
+		log.Printf("Error getting results for poll %s: %v\n", poll.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve results"})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// ExportResultsCSV godoc
+// @Summary Export poll results as CSV
+// @Tags polls
+// @Produce text/csv
+// @Param id path string true "Poll ID (UUID)"
+// @Success 200 {string} string "CSV file"
+// @Router /api/v1/polls/{id}/results.csv [get]
+func (h *PollHandler) ExportResultsCSV(c *gin.Context) {
+	id, ok := pollID(c)
+	if !ok {
+		return
+	}
+	results, err := h.Repo.GetResults(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Poll not found"})
+			return
+		}
+		log.Printf("Error exporting results for poll %s: %v\n", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export results"})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="poll-%s-results.csv"`, id))
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"option_id", "option", "votes", "percent_of_ballots"})
+	for _, opt := range results.Options {
+		percent := 0.0
+		if results.TotalBallots > 0 {
+			percent = float64(opt.Votes) * 100 / float64(results.TotalBallots)
+		}
+		w.Write([]string{opt.OptionID, opt.Label, strconv.Itoa(opt.Votes), strconv.FormatFloat(percent, 'f', 1, 64)})
+	}
+	w.Write([]string{"", "Total ballots", strconv.Itoa(results.TotalBallots), ""})
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Printf("Error writing CSV for poll %s: %v\n", id, err)
+	}
+}
+
+// ClosePoll godoc
+// @Summary Close a poll immediately
+// @Tags polls
+// @Param id path string true "Poll ID (UUID)"
+// @Success 204
+// @Router /api/v1/polls/{id}/close [post]
+func (h *PollHandler) ClosePoll(c *gin.Context) {
+	id, ok := pollID(c)
+	if !ok {
+		return
+	}
+	if err := h.Repo.ClosePoll(c.Request.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrPollNotOpen) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Poll is already closed or does not exist"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close poll"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadPoll fetches the poll named in the URL, including whether the caller has voted.
+// It writes the error response itself and returns false on failure.
+func (h *PollHandler) loadPoll(c *gin.Context) (models.Poll, bool) {
+	id, ok := pollID(c)
+	if !ok {
+		return models.Poll{}, false
+	}
+	poll, err := h.Repo.GetPoll(c.Request.Context(), id, func(anonymous bool) string {
+		return voterKey(c, anonymous)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Poll not found"})
+			return poll, false
+		}
+		log.Printf("Error getting poll %s: %v\n", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve poll"})
+		return poll, false
+	}
+	return poll, true
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+	"village_project/internal/audit"
+	"village_project/internal/middleware"
+	"village_project/internal/models"
+	"village_project/internal/notify"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PushHandler manages Web Push subscriptions for the Flutter web build
+type PushHandler struct {
+	Repo    *repository.PushRepository
+	Channel *notify.PushChannel // nil when push is not configured
+}
+
+// NewPushHandler creates a new PushHandler
+func NewPushHandler(repo *repository.PushRepository, channel *notify.PushChannel) *PushHandler {
+	return &PushHandler{Repo: repo, Channel: channel}
+}
+
+// pushOwner returns the signed-in user and/or the hashed device token of the caller
+func pushOwner(c *gin.Context) (userID, device *string) {
+	if id := middleware.UserID(c); id != "" {
+		userID = &id
+	}
+	if key := deviceKey(c); key != "" {
+		device = &key
+	}
+	return userID, device
+}
+
+// GetVAPIDPublicKey godoc
+// @Summary Get the VAPID application server key
+// @Description Pass this as applicationServerKey to PushManager.subscribe
+// @Tags push
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 503 {object} map[string]string "Push not configured"
+// @Router /api/v1/push/vapid-public-key [get]
+func (h *PushHandler) GetVAPIDPublicKey(c *gin.Context) {
+	if h.Channel == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Push notifications are not enabled"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"public_key": h.Channel.Keys.PublicKey})
+}
+
+// RegisterSubscription godoc
+// @Summary Register a push subscription
+// @Description Stores a browser PushSubscription for the signed-in user, or for the device in X-Device-Token
+// @Tags push
+// @Accept  json
+// @Produce json
+// @Param   subscription body models.RegisterPushSubscriptionRequest true "PushSubscription.toJSON()"
+// @Success 201 {object} models.PushSubscription
+// @Router /api/v1/push/subscriptions [post]
+func (h *PushHandler) RegisterSubscription(c *gin.Context) {
+	audit.Skip(c) // Device housekeeping
+	if h.Channel == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Push notifications are not enabled"})
+		return
+	}
+	userID, device := pushOwner(c)
+	if userID == nil && device == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Sign in or send an " + DeviceTokenHeader + " header to subscribe"})
+		return
+	}
+
+	var req models.RegisterPushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	if u, err := url.Parse(req.Endpoint); err != nil || u.Scheme != "https" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "endpoint must be an https URL"})
+		return
+	}
+	if err := notify.ValidateSubscriptionKeys(req.Keys.P256dh, req.Keys.Auth); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub := models.PushSubscription{
+		UserID:    userID,
+		DeviceKey: device,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+	}
+	if ua := c.GetHeader("User-Agent"); ua != "" {
+		sub.UserAgent = &ua
+	}
+	if req.ExpirationTime != nil {
+		expires := time.UnixMilli(*req.ExpirationTime)
+		sub.ExpiresAt = &expires
+	}
+
+	saved, err := h.Repo.SaveSubscription(c.Request.Context(), sub)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save subscription"})
+		return
+	}
+	c.JSON(http.StatusCreated, saved)
+}
+
+// UnregisterSubscription godoc
+// @Summary Remove a push subscription
+// @Description Only the user or device that registered the subscription can remove it
+// @Tags push
+// @Accept  json
+// @Param   subscription body models.UnregisterPushSubscriptionRequest true "Endpoint to remove"
+// @Success 204
+// @Router /api/v1/push/subscriptions [delete]
+func (h *PushHandler) UnregisterSubscription(c *gin.Context) {
+	audit.Skip(c)
+	var req models.UnregisterPushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	userID, device := pushOwner(c)
+	if userID == nil && device == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Sign in or send an " + DeviceTokenHeader + " header to unsubscribe"})
+		return
+	}
+
+	err := h.Repo.DeleteOwnedSubscription(c.Request.Context(), req.Endpoint, userID, device)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Subscription not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove subscription"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"village_project/internal/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamHandler serves the live updates stream
+type StreamHandler struct {
+	Broker    *stream.Broker
+	Heartbeat time.Duration
+}
+
+// NewStreamHandler creates a new StreamHandler
+func NewStreamHandler(broker *stream.Broker, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{Broker: broker, Heartbeat: heartbeat}
+}
+
+// Stream godoc
+// @Summary Live updates (Server-Sent Events)
+// @Description Pushes job.created, job.status_changed and news.published events. Resume with the Last-Event-ID header
+// @Description (or ?last_event_id=); a "resync" event means the ID is too old and the client should refetch lists.
+// @Tags stream
+// @Produce text/event-stream
+// @Param   types query string false "Comma-separated event types to receive (default all)"
+// @Success 200 {string} string "Event stream"
+// @Router /api/v1/stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		for _, t := range strings.Split(raw, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(stream.EventTypes, t) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown event type " + t, "available": stream.EventTypes})
+				return
+			}
+			types = append(types, t)
+		}
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id") // For EventSource polyfills that cannot set headers
+	}
+
+	client, replay, resync, err := h.Broker.Subscribe(lastEventID, types)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+		return
+	}
+	defer h.Broker.Unsubscribe(client)
+
+	w := c.Writer
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	w.WriteHeader(http.StatusOK)
+
+	// Ask browsers to wait 5s before reconnecting
+	fmt.Fprint(w, "retry: 5000\n\n")
+	if resync {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", stream.EventResync)
+	}
+	for _, ev := range replay {
+		writeStreamEvent(w, ev)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case ev, ok := <-client.Events():
+			if !ok {
+				return // Dropped as too slow, or the server is shutting down
+			}
+			if writeStreamEvent(w, ev) != nil {
+				return
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+func writeStreamEvent(w gin.ResponseWriter, ev stream.Event) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+	return err
+}
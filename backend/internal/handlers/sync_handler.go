@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"village_project/internal/database"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+)
+
+// SyncHandler serves the delta sync feed used by offline-first clients
+type SyncHandler struct {
+	Repo      *repository.SyncRepository
+	Localizer *Localizer
+}
+
+// NewSyncHandler creates a new SyncHandler
+func NewSyncHandler(repo *repository.SyncRepository, localizer *Localizer) *SyncHandler {
+	return &SyncHandler{Repo: repo, Localizer: localizer}
+}
+
+// Sync godoc
+// @Summary Changes to news and jobs since a sync token
+// @Description Without ?since= this is a full snapshot of published news and open jobs ("reset": true).
+// @Description With the token from the previous response it returns only what changed: live items in full,
+// @Description and tombstones ("deleted": true) for items that were deleted, unpublished or are no longer open.
+// @Description An item may be repeated across calls; apply changes as upserts. While "has_more" is true,
+// @Description call again with the new token straight away. Items are localized like the list endpoints.
+// @Description A token from before deleted items were purged from the trash gets a full snapshot instead
+// @Description ("reset": true): replace the local copy with it.
+// @Description On 400 (e.g. a token from another server) discard the local copy and sync without a token.
+// @Tags sync
+// @Produce json
+// @Param   since query string false "Token from the previous sync"
+// @Param   limit query int    false "Max changes per response (default 500, max 1000)"
+// @Param   lang  query string false "Preferred language (overrides Accept-Language)"
+// @Success 200 {object} models.SyncResponse
+// @Failure 400 {object} map[string]string "Invalid token or limit"
+// @Router /api/v1/sync [get]
+func (h *SyncHandler) Sync(c *gin.Context) {
+	prefs, ok := h.Localizer.preferences(c)
+	if !ok {
+		return
+	}
+	cursor, err := repository.ParseSyncToken(c.Query("since"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sync token", "details": err.Error()})
+		return
+	}
+	limit := defaultSyncLimit
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxSyncLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+			return
+		}
+	}
+
+	// Translations are read from the same server as the changes
+	ctx := database.WithPrimary(c.Request.Context())
+	changes, next, hasMore, err := h.Repo.Changes(ctx, cursor, limit)
+	if errors.Is(err, repository.ErrSyncTokenExpired) {
+		cursor = repository.SyncCursor{Full: true}
+		changes, next, hasMore, err = h.Repo.Changes(ctx, cursor, limit)
+	}
+	if err == nil {
+		err = h.localize(ctx, prefs, changes)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSyncToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sync token", "details": err.Error()})
+			return
+		}
+		log.Printf("Error reading sync changes: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read changes"})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, models.SyncResponse{
+		Changes: changes,
+		Token:   next.Token(),
+		HasMore: hasMore,
+		Reset:   cursor.Full && cursor.After == 0, // Later pages of a full snapshot add to it
+	})
+}
+
+// localize puts each live item in the best language for the request
+func (h *SyncHandler) localize(ctx context.Context, prefs []string, changes []models.SyncChange) error {
+	var news []models.News
+	var jobs []models.Job
+	for _, change := range changes {
+		switch {
+		case change.News != nil:
+			news = append(news, *change.News)
+		case change.Job != nil:
+			jobs = append(jobs, *change.Job)
+		}
+	}
+	if len(news) > 0 {
+		if _, err := h.Localizer.localizeNews(ctx, prefs, news); err != nil {
+			return err
+		}
+	}
+	if len(jobs) > 0 {
+		if _, err := h.Localizer.localizeJobs(ctx, prefs, jobs); err != nil {
+			return err
+		}
+	}
+	for i := range changes {
+		switch {
+		case changes[i].News != nil:
+			changes[i].News, news = &news[0], news[1:]
+		case changes[i].Job != nil:
+			changes[i].Job, jobs = &jobs[0], jobs[1:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"village_project/internal/i18n"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Localizer serves news items and jobs in the language a request prefers
+type Localizer struct {
+	Translations *repository.TranslationRepository
+	Locales      *i18n.Negotiator
+}
+
+// NewLocalizer creates a new Localizer
+func NewLocalizer(translations *repository.TranslationRepository, locales *i18n.Negotiator) *Localizer {
+	return &Localizer{Translations: translations, Locales: locales}
+}
+
+// preferences negotiates ?lang= and Accept-Language, answering 400 for an unsupported ?lang=
+func (l *Localizer) preferences(c *gin.Context) ([]string, bool) {
+	c.Header("Vary", "Accept-Language")
+	prefs, err := l.Locales.Preferences(c.Query("lang"), c.GetHeader("Accept-Language"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language", "supported": l.Locales.Supported})
+		return nil, false
+	}
+	return prefs, true
+}
+
+// contentLanguage validates the language of newly written content, defaulting to the fallback
+func (l *Localizer) contentLanguage(c *gin.Context, lang string) (string, bool) {
+	if lang == "" {
+		return l.Locales.Fallback, true
+	}
+	lang = i18n.Normalize(lang)
+	if !l.Locales.IsSupported(lang) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language", "supported": l.Locales.Supported})
+		return "", false
+	}
+	return lang, true
+}
+
+// localizeNews swaps in the best translation of each item and lists the languages it is
+// available in. It returns when each item was last changed, counting its translations.
+func (l *Localizer) localizeNews(ctx context.Context, prefs []string, items []models.News) (map[string]time.Time, error) {
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	translations, err := l.Translations.NewsTranslations(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]time.Time, len(items))
+	for i := range items {
+		n := &items[i]
+		changed[n.ID] = n.UpdatedAt
+		locales := make([]string, 0, len(translations[n.ID]))
+		for _, t := range translations[n.ID] {
+			locales = append(locales, t.Locale)
+			if t.UpdatedAt.After(changed[n.ID]) {
+				changed[n.ID] = t.UpdatedAt
+			}
+		}
+		n.AvailableLanguages = i18n.Available(n.Language, locales)
+		best := i18n.Best(prefs, n.AvailableLanguages)
+		for _, t := range translations[n.ID] {
+			if t.Locale == best {
+				t.Apply(n)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// localizeJobs swaps in the best translation of each job and lists the languages it is
+// available in. It returns when each job was last changed, counting its translations.
+func (l *Localizer) localizeJobs(ctx context.Context, prefs []string, jobs []models.Job) (map[string]time.Time, error) {
+	ids := make([]string, len(jobs))
+	for i := range jobs {
+		ids[i] = jobs[i].ID
+	}
+	translations, err := l.Translations.JobTranslations(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]time.Time, len(jobs))
+	for i := range jobs {
+		j := &jobs[i]
+		changed[j.ID] = j.UpdatedAt
+		locales := make([]string, 0, len(translations[j.ID]))
+		for _, t := range translations[j.ID] {
+			locales = append(locales, t.Locale)
+			if t.UpdatedAt.After(changed[j.ID]) {
+				changed[j.ID] = t.UpdatedAt
+			}
+		}
+		j.AvailableLanguages = i18n.Available(j.Language, locales)
+		best := i18n.Best(prefs, j.AvailableLanguages)
+		for _, t := range translations[j.ID] {
+			if t.Locale == best {
+				t.Apply(j)
+			}
+		}
+	}
+	return changed, nil
+}
+
+// TranslationHandler lets editors manage the translations of news items and jobs
+type TranslationHandler struct {
+	Repo    *repository.TranslationRepository
+	Locales *i18n.Negotiator
+}
+
+// NewTranslationHandler creates a new TranslationHandler
+func NewTranslationHandler(repo *repository.TranslationRepository, locales *i18n.Negotiator) *TranslationHandler {
+	return &TranslationHandler{Repo: repo, Locales: locales}
+}
+
+// translationLocale validates the :lang path parameter
+func (h *TranslationHandler) translationLocale(c *gin.Context) (string, bool) {
+	locale := i18n.Normalize(c.Param("lang"))
+	if !h.Locales.IsSupported(locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language", "supported": h.Locales.Supported})
+		return "", false
+	}
+	return locale, true
+}
+
+// respondTranslationError maps repository errors shared by the upsert endpoints
+func respondTranslationError(c *gin.Context, err error, notFound string) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+	case errors.Is(err, repository.ErrOriginalLanguage):
+		c.JSON(http.StatusConflict, gin.H{"error": "This is the original language; it cannot be translated into itself"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save translation"})
+	}
+}
+
+// ListNewsTranslations godoc
+// @Summary List a news item's translations
+// @Tags translations
+// @Produce json
+// @Param   id path string true "News ID"
+// @Success 200 {array} models.NewsTranslation
+// @Router /api/v1/news/{id}/translations [get]
+func (h *TranslationHandler) ListNewsTranslations(c *gin.Context) {
+	newsID := c.Param("id")
+	byNews, err := h.Repo.NewsTranslations(c.Request.Context(), []string{newsID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve translations"})
+		return
+	}
+	translations := byNews[newsID]
+	if translations == nil {
+		translations = []models.NewsTranslation{}
+	}
+	c.JSON(http.StatusOK, translations)
+}
+
+// UpsertNewsTranslation godoc
+// @Summary Add or update a news translation
+// @Description Stores the item's text in another language. The original item is not modified.
+// @Tags translations
+// @Accept  json
+// @Produce json
+// @Param   id   path string true "News ID"
+// @Param   lang path string true "Locale, e.g. te or en"
+// @Param   translation body models.UpsertNewsTranslationRequest true "Translated text"
+// @Success 200 {object} models.NewsTranslation
+// @Failure 409 {object} map[string]string "Locale is the original language"
+// @Router /api/v1/news/{id}/translations/{lang} [put]
+func (h *TranslationHandler) UpsertNewsTranslation(c *gin.Context) {
+	locale, ok := h.translationLocale(c)
+	if !ok {
+		return
+	}
+	var req models.UpsertNewsTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	editorID, _ := actorFromContext(c)
+	t, err := h.Repo.UpsertNewsTranslation(c.Request.Context(), c.Param("id"), locale, req, editorID)
+	if err != nil {
+		respondTranslationError(c, err, "News item not found")
+		return
+	}
+	log.Printf("Handler: Saved %s translation of news %s", locale, t.NewsID)
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteNewsTranslation godoc
+// @Summary Remove a news translation
+// @Tags translations
+// @Param   id   path string true "News ID"
+// @Param   lang path string true "Locale"
+// @Success 204
+// @Router /api/v1/news/{id}/translations/{lang} [delete]
+func (h *TranslationHandler) DeleteNewsTranslation(c *gin.Context) {
+	err := h.Repo.DeleteNewsTranslation(c.Request.Context(), c.Param("id"), i18n.Normalize(c.Param("lang")))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListJobTranslations godoc
+// @Summary List a job's translations
+// @Tags translations
+// @Produce json
+// @Param   id path string true "Job ID"
+// @Success 200 {array} models.JobTranslation
+// @Router /api/v1/jobs/{id}/translations [get]
+func (h *TranslationHandler) ListJobTranslations(c *gin.Context) {
+	jobID := c.Param("id")
+	byJob, err := h.Repo.JobTranslations(c.Request.Context(), []string{jobID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve translations"})
+		return
+	}
+	translations := byJob[jobID]
+	if translations == nil {
+		translations = []models.JobTranslation{}
+	}
+	c.JSON(http.StatusOK, translations)
+}
+
+// UpsertJobTranslation godoc
+// @Summary Add or update a job translation
+// @Description Stores the job's text in another language. Omitted location or payment details fall back to the original's.
+// @Tags translations
+// @Accept  json
+// @Produce json
+// @Param   id   path string true "Job ID"
+// @Param   lang path string true "Locale, e.g. te or en"
+// @Param   translation body models.UpsertJobTranslationRequest true "Translated text"
+// @Success 200 {object} models.JobTranslation
+// @Failure 409 {object} map[string]string "Locale is the original language"
+// @Router /api/v1/jobs/{id}/translations/{lang} [put]
+func (h *TranslationHandler) UpsertJobTranslation(c *gin.Context) {
+	locale, ok := h.translationLocale(c)
+	if !ok {
+		return
+	}
+	var req models.UpsertJobTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+
+	editorID, _ := actorFromContext(c)
+	t, err := h.Repo.UpsertJobTranslation(c.Request.Context(), c.Param("id"), locale, req, editorID)
+	if err != nil {
+		respondTranslationError(c, err, "Job not found")
+		return
+	}
+	log.Printf("Handler: Saved %s translation of job %s", locale, t.JobID)
+	c.JSON(http.StatusOK, t)
+}
+
+// DeleteJobTranslation godoc
+// @Summary Remove a job translation
+// @Tags translations
+// @Param   id   path string true "Job ID"
+// @Param   lang path string true "Locale"
+// @Success 204
+// @Router /api/v1/jobs/{id}/translations/{lang} [delete]
+func (h *TranslationHandler) DeleteJobTranslation(c *gin.Context) {
+	err := h.Repo.DeleteJobTranslation(c.Request.Context(), c.Param("id"), i18n.Normalize(c.Param("lang")))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete translation"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
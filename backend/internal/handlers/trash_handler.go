@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"slices"
+	"village_project/internal/audit"
+	"village_project/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrashHandler lets admins see and restore deleted content before it is purged
+type TrashHandler struct {
+	Repo *repository.TrashRepository
+}
+
+// NewTrashHandler creates a new TrashHandler
+func NewTrashHandler(repo *repository.TrashRepository) *TrashHandler {
+	return &TrashHandler{Repo: repo}
+}
+
+// ListTrash godoc
+// @Summary Deleted news and jobs that can still be restored (admin only)
+// @Description Most recently deleted first; purge_at is when each item will be removed for good.
+// @Tags trash
+// @Produce json
+// @Param   collection query string false "news or jobs (default both)"
+// @Success 200 {array} models.TrashItem
+// @Failure 400 {object} map[string]string "Unknown collection"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/trash [get]
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	collection := c.Query("collection")
+	if collection != "" && !slices.Contains(repository.TrashCollections, collection) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown collection", "supported": repository.TrashCollections})
+		return
+	}
+	items, err := h.Repo.List(c.Request.Context(), collection)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve trash"})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// RestoreFromTrash godoc
+// @Summary Restore a deleted news item or job (admin only)
+// @Description The item is live again with the status it had; syncing clients receive it as a change.
+// @Tags trash
+// @Produce json
+// @Param   collection path string true "news or jobs"
+// @Param   id         path string true "Item ID (UUID)"
+// @Success 204 "Restored"
+// @Failure 400 {object} map[string]string "Invalid item ID"
+// @Failure 404 {object} map[string]string "Not in the trash (never deleted, or already purged)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/trash/{collection}/{id}/restore [post]
+func (h *TrashHandler) RestoreFromTrash(c *gin.Context) {
+	var uri struct {
+		Collection string `uri:"collection" binding:"required"`
+		ID         string `uri:"id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID", "details": err.Error()})
+		return
+	}
+	collection, id := uri.Collection, uri.ID
+	if err := h.Repo.Restore(c.Request.Context(), collection, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found in the trash"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore item"})
+		return
+	}
+	audit.Target(c, collection, id)
+	audit.Before(c, gin.H{"deleted": true})
+	audit.After(c, gin.H{"deleted": false})
+	log.Printf("Handler: Restored %s %s from the trash", collection, id)
+	c.Status(http.StatusNoContent)
+}
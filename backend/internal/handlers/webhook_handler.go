@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+	"village_project/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler lets admins manage webhook subscriptions and inspect deliveries
+type WebhookHandler struct {
+	Repo       *repository.WebhookRepository
+	Dispatcher *webhooks.Dispatcher
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(repo *repository.WebhookRepository, dispatcher *webhooks.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{Repo: repo, Dispatcher: dispatcher}
+}
+
+// validateWebhookTarget checks the URL scheme and event types of a create or update
+func validateWebhookTarget(rawURL *string, eventTypes []string) string {
+	if rawURL != nil {
+		u, err := url.Parse(*rawURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return "url must be an http or https URL"
+		}
+	}
+	for _, e := range eventTypes {
+		if !webhooks.IsKnownEvent(e) {
+			return "unknown event type " + e + " (want one of " + strings.Join(webhooks.EventTypes, ", ") + ")"
+		}
+	}
+	return ""
+}
+
+// ListEventTypes godoc
+// @Summary List webhook event types
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} string
+// @Router /api/v1/webhooks/event-types [get]
+func (h *WebhookHandler) ListEventTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, webhooks.EventTypes)
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description The response is the only time the signing secret is shown
+// @Tags webhooks
+// @Accept  json
+// @Produce json
+// @Param   webhook body models.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} models.WebhookSubscription
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	if msg := validateWebhookTarget(&req.URL, req.EventTypes); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = webhooks.GenerateSecret(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+			return
+		}
+	}
+	createdBy, _ := actorFromContext(c)
+	webhook, err := h.Repo.CreateSubscription(c.Request.Context(), req, secret, createdBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.WebhookSubscription
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	list, err := h.Repo.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve webhooks"})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Tags webhooks
+// @Produce json
+// @Param   id path string true "Webhook ID (UUID)"
+// @Success 200 {object} models.WebhookSubscription
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	webhook, err := h.Repo.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err, "Failed to retrieve webhook")
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Change the name, URL, event types or active flag. The secret cannot be changed; create a new webhook instead.
+// @Tags webhooks
+// @Accept  json
+// @Produce json
+// @Param   id path string true "Webhook ID (UUID)"
+// @Param   webhook body models.UpdateWebhookRequest true "Changes"
+// @Success 200 {object} models.WebhookSubscription
+// @Router /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data", "details": err.Error()})
+		return
+	}
+	if msg := validateWebhookTarget(req.URL, req.EventTypes); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	webhook, err := h.Repo.UpdateSubscription(c.Request.Context(), id, req)
+	if err != nil {
+		h.respondError(c, err, "Failed to update webhook")
+		return
+	}
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook and its delivery log
+// @Tags webhooks
+// @Param   id path string true "Webhook ID (UUID)"
+// @Success 204
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	if err := h.Repo.DeleteSubscription(c.Request.Context(), id); err != nil {
+		h.respondError(c, err, "Failed to delete webhook")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// PingWebhook godoc
+// @Summary Send a test event
+// @Description Queues a "ping" event for this webhook, whatever event types it selected
+// @Tags webhooks
+// @Param   id path string true "Webhook ID (UUID)"
+// @Success 202 {object} map[string]string
+// @Router /api/v1/webhooks/{id}/ping [post]
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	if _, err := h.Repo.GetSubscription(c.Request.Context(), id); err != nil {
+		h.respondError(c, err, "Failed to retrieve webhook")
+		return
+	}
+	if err := h.Dispatcher.Ping(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue test event"})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Test event queued"})
+}
+
+// ListDeliveries godoc
+// @Summary List a webhook's deliveries
+// @Tags webhooks
+// @Produce json
+// @Param   id path string true "Webhook ID (UUID)"
+// @Param   status query string false "pending, sending, succeeded, failed or cancelled"
+// @Param   limit query int false "Max rows (default 50)"
+// @Success 200 {array} models.WebhookDelivery
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+	var filter models.WebhookDeliveryFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter", "details": err.Error()})
+		return
+	}
+	list, err := h.Repo.ListDeliveries(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// RedeliverDelivery godoc
+// @Summary Redeliver a webhook delivery
+// @Description Queues a new delivery with the same payload and event ID; the original stays in the log
+// @Tags webhooks
+// @Produce json
+// @Param   deliveryId path string true "Delivery ID (UUID)"
+// @Success 202 {object} models.WebhookDelivery
+// @Router /api/v1/webhooks/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	var uri struct {
+		DeliveryID string `uri:"deliveryId" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID", "details": err.Error()})
+		return
+	}
+	delivery, err := h.Repo.Redeliver(c.Request.Context(), uri.DeliveryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue redelivery"})
+		return
+	}
+	log.Printf("Handler: redelivery %s queued for %s", delivery.ID, delivery.EventType)
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// webhookID binds the webhook ID from the URL, answering 400 itself if it is not a UUID
+func webhookID(c *gin.Context) (string, bool) {
+	var uri struct {
+		ID string `uri:"id" binding:"required,uuid"`
+	}
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID", "details": err.Error()})
+		return "", false
+	}
+	return uri.ID, true
+}
+
+func (h *WebhookHandler) respondError(c *gin.Context, err error, message string) {
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+}
@@ -0,0 +1,132 @@
+// Package i18n picks which language to serve content in. Content is written in one
+// language (its original) and may carry translations into others; a request states its
+// preference with ?lang= or Accept-Language and gets the best available match, falling
+// back to the configured fallback locale and finally to the original.
+package i18n
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedLocale is returned for a ?lang= value outside the supported set
+var ErrUnsupportedLocale = errors.New("unsupported language")
+
+// Negotiator holds the locales the site serves
+type Negotiator struct {
+	Supported []string // Base language codes, e.g. "te", "en"
+	Fallback  string   // Served when none of the requested languages is available
+}
+
+// NewNegotiator parses a comma-separated locale list such as "te,en". The fallback is
+// added to the supported set if missing.
+func NewNegotiator(supported, fallback string) *Negotiator {
+	n := &Negotiator{Fallback: Normalize(fallback)}
+	for _, s := range strings.Split(supported, ",") {
+		if s = Normalize(s); s != "" && !slices.Contains(n.Supported, s) {
+			n.Supported = append(n.Supported, s)
+		}
+	}
+	if n.Fallback != "" && !slices.Contains(n.Supported, n.Fallback) {
+		n.Supported = append(n.Supported, n.Fallback)
+	}
+	return n
+}
+
+// Normalize reduces a language tag to its lower-case base code ("te-IN" -> "te")
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// IsSupported reports whether the locale is one the site serves
+func (n *Negotiator) IsSupported(locale string) bool {
+	return slices.Contains(n.Supported, locale)
+}
+
+// Preferences returns the supported locales the request asks for, most preferred first,
+// followed by the fallback. An explicit ?lang= wins over Accept-Language.
+func (n *Negotiator) Preferences(queryLang, acceptLanguage string) ([]string, error) {
+	var prefs []string
+	if queryLang != "" {
+		lang := Normalize(queryLang)
+		if !n.IsSupported(lang) {
+			return nil, ErrUnsupportedLocale
+		}
+		prefs = append(prefs, lang)
+	}
+	for _, lang := range ParseAcceptLanguage(acceptLanguage) {
+		if n.IsSupported(lang) && !slices.Contains(prefs, lang) {
+			prefs = append(prefs, lang)
+		}
+	}
+	if n.Fallback != "" && !slices.Contains(prefs, n.Fallback) {
+		prefs = append(prefs, n.Fallback)
+	}
+	return prefs, nil
+}
+
+// ParseAcceptLanguage returns the base language codes of an Accept-Language header,
+// highest quality first. Wildcards and q=0 entries are dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var entries []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang := Normalize(tag)
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		entries = append(entries, weighted{lang, q})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	var langs []string
+	for _, e := range entries {
+		if !slices.Contains(langs, e.lang) {
+			langs = append(langs, e.lang)
+		}
+	}
+	return langs
+}
+
+// Available lists the original language first, then the translated locales
+func Available(original string, translated []string) []string {
+	langs := []string{original}
+	for _, t := range translated {
+		if !slices.Contains(langs, t) {
+			langs = append(langs, t)
+		}
+	}
+	return langs
+}
+
+// Best picks the first preference present in available, or the original (available[0])
+func Best(prefs, available []string) string {
+	for _, p := range prefs {
+		if slices.Contains(available, p) {
+			return p
+		}
+	}
+	return available[0]
+}
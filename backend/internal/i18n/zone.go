@@ -0,0 +1,8 @@
+package i18n
+
+import "time"
+
+// IST is the village's time zone. Calendar questions (which day a job expires, which
+// month a news item belongs to) are answered in it, not in UTC. India has no DST, so
+// a fixed offset matches Asia/Kolkata without needing the tz database.
+var IST = time.FixedZone("IST", 5*60*60+30*60)
@@ -0,0 +1,26 @@
+package importer
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"village_project/internal/models"
+)
+
+// WriteResultCSV writes the per-row outcome of an import (line, status, id, errors) so
+// staff can fix the failed rows in their sheet and upload just those again
+func WriteResultCSV(w io.Writer, report models.ImportReport) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"line", "status", "id", "errors"}); err != nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		record := []string{strconv.Itoa(row.Line), row.Status, row.ID, strings.Join(row.Errors, "; ")}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
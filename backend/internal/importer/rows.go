@@ -0,0 +1,303 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"village_project/internal/i18n"
+	"village_project/internal/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// column parses one cell into the row; the value is already trimmed and non-empty
+type column[T any] func(row *T, value string) error
+
+var jobColumns = map[string]column[models.ImportJobRow]{
+	"title":             func(r *models.ImportJobRow, v string) error { r.Title = v; return nil },
+	"description":       func(r *models.ImportJobRow, v string) error { r.Description = v; return nil },
+	"location":          func(r *models.ImportJobRow, v string) error { r.Location = v; return nil },
+	"payment_details":   func(r *models.ImportJobRow, v string) error { r.PaymentDetails = v; return nil },
+	"contact_info":      func(r *models.ImportJobRow, v string) error { r.ContactInfo = v; return nil },
+	"positions_needed":  func(r *models.ImportJobRow, v string) (err error) { r.PositionsNeeded, err = parseInt(v); return },
+	"auto_fill_on_hire": func(r *models.ImportJobRow, v string) (err error) { r.AutoFillOnHire, err = parseBool(v); return },
+	"language":          func(r *models.ImportJobRow, v string) error { r.Language = v; return nil },
+	"status":            func(r *models.ImportJobRow, v string) error { r.Status = titleCase(v); return nil },
+	"expires_at":        func(r *models.ImportJobRow, v string) (err error) { r.ExpiresAt, err = parseTime(v, true); return },
+}
+
+var newsColumns = map[string]column[models.ImportNewsRow]{
+	"title":        func(r *models.ImportNewsRow, v string) error { r.Title = v; return nil },
+	"content":      func(r *models.ImportNewsRow, v string) error { r.Content = &v; return nil },
+	"language":     func(r *models.ImportNewsRow, v string) error { r.Language = v; return nil },
+	"publish":      func(r *models.ImportNewsRow, v string) (err error) { r.Publish, err = parseBool(v); return },
+	"status":       func(r *models.ImportNewsRow, v string) error { r.Status = strings.ToLower(v); return nil },
+	"published_at": func(r *models.ImportNewsRow, v string) (err error) { r.PublishedAt, err = parseTime(v, false); return },
+}
+
+// exportOnlyColumns appear in exports (see package export) and are ignored on import
+var exportOnlyColumns = []string{"id", "created_at", "updated_at", "posted_by_user_id"}
+
+// Columns lists the accepted header names for a kind ("jobs" or "news"), for help texts
+func Columns(kind string) []string {
+	var names []string
+	switch kind {
+	case "jobs":
+		for name := range jobColumns {
+			names = append(names, name)
+		}
+	case "news":
+		for name := range newsColumns {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ParseJobs turns a table into job rows, validating each with CreateJobRequest's binding
+// rules. The error is for problems with the file as a whole (header, size); row problems
+// are returned in each row's Errors.
+func ParseJobs(table [][]string, locales *i18n.Negotiator) ([]models.ImportRow[models.ImportJobRow], error) {
+	now := time.Now()
+	return parseRows(table, jobColumns, []string{"title", "description", "contact_info"}, func(r *models.ImportJobRow) []string {
+		var errs []string
+		if r.Status == "" {
+			r.Status = "Open"
+		}
+		if r.Status == "Open" && r.ExpiresAt != nil && r.ExpiresAt.Before(now) {
+			errs = append(errs, "expires_at: is in the past for an Open job")
+		}
+		return append(errs, checkLanguage(&r.Language, locales)...)
+	})
+}
+
+// ParseNews turns a table into news rows. A status column, when present, decides
+// publish; rows default to drafts.
+func ParseNews(table [][]string, locales *i18n.Negotiator) ([]models.ImportRow[models.ImportNewsRow], error) {
+	now := time.Now()
+	return parseRows(table, newsColumns, []string{"title"}, func(r *models.ImportNewsRow) []string {
+		var errs []string
+		if r.Status != "" {
+			r.Publish = r.Status == models.NewsStatusPublished
+		}
+		if r.PublishedAt != nil {
+			if !r.Publish {
+				errs = append(errs, "published_at: only applies to published rows")
+			} else if r.PublishedAt.After(now) {
+				errs = append(errs, "published_at: is in the future; import it as a draft and publish it then")
+			}
+		}
+		return append(errs, checkLanguage(&r.Language, locales)...)
+	})
+}
+
+func parseRows[T any](table [][]string, columns map[string]column[T], required []string, finish func(*T) []string) ([]models.ImportRow[T], error) {
+	if len(table) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	if len(table)-1 > MaxRows {
+		return nil, fmt.Errorf("file has more than %d rows; split it", MaxRows)
+	}
+
+	header := make([]string, len(table[0]))
+	for i, name := range table[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.Join(strings.Fields(name), "_") // "Contact info" -> contact_info
+		if name == "" || slices.Contains(exportOnlyColumns, name) {
+			continue // Unnamed (usually empty) column, or one the database assigns
+		}
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q (expected: %s)", table[0][i], strings.Join(sortedKeys(columns), ", "))
+		}
+		if slices.Contains(header, name) {
+			return nil, fmt.Errorf("column %q appears twice", name)
+		}
+		header[i] = name
+	}
+	for _, name := range required {
+		if !slices.Contains(header, name) {
+			return nil, fmt.Errorf("missing required column %q", name)
+		}
+	}
+
+	var rows []models.ImportRow[T]
+	for i, cells := range table[1:] {
+		if isBlank(cells) {
+			continue
+		}
+		row := models.ImportRow[T]{Line: i + 2}
+		for col, cell := range cells {
+			cell = undefuse(strings.TrimSpace(cell))
+			if col >= len(header) || header[col] == "" || cell == "" {
+				continue
+			}
+			if err := columns[header[col]](&row.Value, cell); err != nil {
+				row.Errors = append(row.Errors, fmt.Sprintf("%s: %v", header[col], err))
+			}
+		}
+		if err := binding.Validator.ValidateStruct(&row.Value); err != nil {
+			row.Errors = append(row.Errors, validationMessages(err)...)
+		}
+		row.Errors = append(row.Errors, finish(&row.Value)...)
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("file has a header but no rows")
+	}
+	return rows, nil
+}
+
+func checkLanguage(lang *string, locales *i18n.Negotiator) []string {
+	if *lang == "" {
+		*lang = locales.Fallback
+		return nil
+	}
+	*lang = i18n.Normalize(*lang)
+	if !locales.IsSupported(*lang) {
+		return []string{fmt.Sprintf("language: %q is not supported (use one of %s)", *lang, strings.Join(locales.Supported, ", "))}
+	}
+	return nil
+}
+
+// validationMessages turns binding errors into "column: problem" lines office staff can act on
+func validationMessages(err error) []string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []string{err.Error()}
+	}
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		var problem string
+		switch fe.Tag() {
+		case "required":
+			problem = "is required"
+		case "min", "max":
+			bound := map[string]string{"min": "at least", "max": "at most"}[fe.Tag()]
+			if fe.Kind().String() == "string" {
+				problem = fmt.Sprintf("must be %s %s characters", bound, fe.Param())
+			} else {
+				problem = fmt.Sprintf("must be %s %s", bound, fe.Param())
+			}
+		case "oneof":
+			problem = "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+		default:
+			problem = "fails " + fe.Tag()
+		}
+		messages = append(messages, snakeCase(fe.Field())+": "+problem)
+	}
+	return messages
+}
+
+func parseInt(v string) (int, error) {
+	n, err := strconv.ParseFloat(v, 64) // XLSX stores every number as a float
+	if err != nil || n != math.Trunc(n) {
+		return 0, fmt.Errorf("%q is not a whole number", v)
+	}
+	return int(n), nil
+}
+
+func parseBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "true", "yes", "y", "1", "avunu", "అవును":
+		return true, nil
+	case "false", "no", "n", "0", "kaadu", "కాదు":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not yes/no", v)
+}
+
+// dateLayouts are tried in order; day-first matches how dates are written in India
+var dateLayouts = []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", "2-1-2006", "02.01.2006"}
+
+// parseTime accepts RFC 3339, a date in dateLayouts (IST) or an Excel serial date. A
+// date without a time means the start of that day, or its end when endOfDay is set (an
+// expiry date includes the day itself).
+func parseTime(v string, endOfDay bool) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", v, i18n.IST); err == nil {
+		return &t, nil
+	}
+	var day time.Time
+	var dateOnly bool
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, v, i18n.IST); err == nil {
+			day, dateOnly = t, true
+			break
+		}
+	}
+	if !dateOnly {
+		serial, err := strconv.ParseFloat(v, 64)
+		if err != nil || serial < 1 || serial > 2958465 { // 9999-12-31
+			return nil, fmt.Errorf("%q is not a date (use YYYY-MM-DD or DD/MM/YYYY)", v)
+		}
+		// Serial days count from 1899-12-30 in the 1900 date system; the fraction is the time of day
+		base := time.Date(1899, 12, 30, 0, 0, 0, 0, i18n.IST)
+		whole := math.Trunc(serial)
+		t := base.AddDate(0, 0, int(whole)).Add(time.Duration((serial - whole) * 24 * float64(time.Hour)).Round(time.Second))
+		if serial != whole {
+			return &t, nil
+		}
+		day = t
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return &day, nil
+}
+
+func titleCase(v string) string {
+	if v == "" {
+		return v
+	}
+	return strings.ToUpper(v[:1]) + strings.ToLower(v[1:])
+}
+
+// snakeCase maps a Go field name to its column: ContactInfo -> contact_info
+func snakeCase(field string) string {
+	var b strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// undefuse drops the quote that exports put before cells starting with a formula character
+func undefuse(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune("=+-@", rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
+
+func isBlank(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys[T any](columns map[string]column[T]) []string {
+	keys := make([]string, 0, len(columns))
+	for k := range columns {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
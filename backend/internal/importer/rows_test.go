@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"village_project/internal/i18n"
+)
+
+var testLocales = i18n.NewNegotiator("te,en", "te")
+
+func TestParseJobs(t *testing.T) {
+	table := [][]string{
+		{"Title", "Description", "Contact info", "Positions needed", "Auto fill on hire", "Language", "id", ""},
+		{"Paddy harvest", "Two days of harvest work", "9876543210", "3", "avunu", "EN", "ignored", "ignored"},
+		nil,
+		{"  ", ""},
+		{"Dig", "Short", "'=SUM(1)", "two", "maybe", "fr"},
+	}
+	rows, err := ParseJobs(table, testLocales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("%d rows, want blank lines skipped and 2 left", len(rows))
+	}
+
+	good := rows[0]
+	if good.Line != 2 || len(good.Errors) != 0 {
+		t.Errorf("row 1: line %d, errors %q; want line 2 without errors", good.Line, good.Errors)
+	}
+	v := good.Value
+	if v.Title != "Paddy harvest" || v.PositionsNeeded != 3 || !v.AutoFillOnHire || v.Language != "en" || v.Status != "Open" {
+		t.Errorf("row 1 parsed as %+v", v)
+	}
+
+	bad := rows[1]
+	if bad.Line != 5 {
+		t.Errorf("row 2 line = %d, want 5", bad.Line)
+	}
+	if bad.Value.ContactInfo != "=SUM(1)" {
+		t.Errorf("contact_info = %q, want the export's formula guard removed", bad.Value.ContactInfo)
+	}
+	wantErrors := []string{
+		`positions_needed: "two" is not a whole number`,
+		`auto_fill_on_hire: "maybe" is not yes/no`,
+		"title: must be at least 5 characters",
+		"description: must be at least 10 characters",
+		`language: "fr" is not supported (use one of te, en)`,
+	}
+	if !reflect.DeepEqual(bad.Errors, wantErrors) {
+		t.Errorf("row 2 errors:\n%q\nwant\n%q", bad.Errors, wantErrors)
+	}
+}
+
+func TestParseJobsRejectsExpiredOpenJob(t *testing.T) {
+	table := [][]string{
+		{"title", "description", "contact_info", "status", "expires_at"},
+		{"Paddy harvest", "Two days of harvest work", "9876543210", "open", "01/01/2020"},
+		{"Paddy harvest", "Two days of harvest work", "9876543210", "expired", "01/01/2020"},
+	}
+	rows, err := ParseJobs(table, testLocales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows[0].Errors, []string{"expires_at: is in the past for an Open job"}) {
+		t.Errorf("open row errors = %q", rows[0].Errors)
+	}
+	if len(rows[1].Errors) != 0 || rows[1].Value.Status != "Expired" {
+		t.Errorf("expired row: status %q, errors %q", rows[1].Value.Status, rows[1].Errors)
+	}
+	if rows[1].Value.ExpiresAt == nil {
+		t.Error("expires_at was not parsed")
+	}
+}
+
+func TestParseNews(t *testing.T) {
+	table := [][]string{
+		{"title", "content", "status", "published_at"},
+		{"Gram sabha on Sunday", "At the school ground", "Published", "2024-05-01"},
+		{"Water supply notice", "", "", "2024-05-01"},
+	}
+	rows, err := ParseNews(table, testLocales)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived := rows[0].Value
+	if len(rows[0].Errors) != 0 || !archived.Publish || archived.Content == nil || archived.PublishedAt == nil || archived.Language != "te" {
+		t.Errorf("published row: %+v, errors %q", archived, rows[0].Errors)
+	}
+	if !reflect.DeepEqual(rows[1].Errors, []string{"published_at: only applies to published rows"}) {
+		t.Errorf("draft row errors = %q", rows[1].Errors)
+	}
+}
+
+func TestParseRowsFileErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		table [][]string
+		want  string
+	}{
+		{"empty", nil, "file is empty"},
+		{"header only", [][]string{{"title", "description", "contact_info"}}, "no rows"},
+		{"unknown column", [][]string{{"title", "salary"}, {"x", "y"}}, `unknown column "salary"`},
+		{"duplicate column", [][]string{{"title", "Title"}, {"x", "y"}}, `column "title" appears twice`},
+		{"missing column", [][]string{{"title", "description"}, {"x", "y"}}, `missing required column "contact_info"`},
+		{"too many rows", make([][]string, MaxRows+2), "more than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJobs(tt.table, testLocales)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
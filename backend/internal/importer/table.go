@@ -0,0 +1,65 @@
+// Package importer reads the panchayat office's spreadsheets (CSV or XLSX) of jobs and
+// news into validated rows for the repository's bulk import. The first row is the header;
+// column names match the JSON fields of the create requests (see jobColumns, newsColumns).
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Limits on what one import may contain
+const (
+	MaxFileSize = 10 << 20
+	MaxRows     = 5000
+)
+
+// Supported file formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrTooLarge is returned for files over MaxFileSize
+var ErrTooLarge = fmt.Errorf("file is larger than %d MB", MaxFileSize>>20)
+
+// ReadTable reads a CSV or XLSX file (the first worksheet) into rows of cells. The format
+// comes from the file name's extension, falling back to sniffing the content.
+func ReadTable(name string, r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFileSize {
+		return nil, ErrTooLarge
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	if format != FormatCSV && format != FormatXLSX {
+		format = FormatCSV
+		if bytes.HasPrefix(data, []byte("PK\x03\x04")) { // XLSX is a zip archive
+			format = FormatXLSX
+		}
+	}
+	if format == FormatXLSX {
+		return readXLSX(data)
+	}
+	return readCSV(data)
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel's "CSV UTF-8" starts with a BOM
+	if !utf8.Valid(data) {
+		return nil, errors.New("CSV is not UTF-8; in Excel use \"Save as: CSV UTF-8\"")
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // Trailing empty cells are often dropped
+	reader.LazyQuotes = true
+	return reader.ReadAll()
+}
@@ -0,0 +1,117 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildXLSX zips the given parts into a workbook whose first sheet is sheetXML
+func buildXLSX(t *testing.T, sheetXML, sharedXML string) []byte {
+	t.Helper()
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+			xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+			<sheets><sheet name="Jobs" sheetId="1" r:id="rId1"/><sheet name="Notes" sheetId="2" r:id="rId2"/></sheets>
+		</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+			<Relationship Id="rId2" Target="worksheets/sheet2.xml"/>
+			<Relationship Id="rId1" Target="worksheets/sheet1.xml"/>
+		</Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + sheetXML + `</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+			<row r="1"><c r="A1" t="inlineStr"><is><t>wrong sheet</t></is></c></row></sheetData></worksheet>`,
+	}
+	if sharedXML != "" {
+		parts["xl/sharedStrings.xml"] = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` + sharedXML + `</sst>`
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	data := buildXLSX(t, `
+		<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>expires_at</t></is></c></row>
+		<row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3"><v>2</v></c><c r="C3" t="b"><v>1</v></c><c r="D3"><v>46100</v></c></row>
+		<row r="4"><c r="B4" t="e"><v>#N/A</v></c><c r="AA4" t="str"><v>far</v></c></row>`,
+		`<si><t>title</t></si><si><t>positions_needed</t></si><si><r><t>వరి </t></r><r><t>కోత</t></r></si>`)
+
+	table, err := readXLSX(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	far := make([]string, 27)
+	far[26] = "far"
+	want := [][]string{
+		{"title", "positions_needed", "", "expires_at"},
+		nil, // Row 2 is blank and omitted from the file
+		{"వరి కోత", "2", "true", "46100"},
+		far,
+	}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("table = %q\nwant    %q", table, want)
+	}
+}
+
+func TestReadXLSXErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"not a zip", []byte("title,description\n"), "not a valid XLSX file"},
+		{"bad shared string", buildXLSX(t, `<row r="1"><c r="A1" t="s"><v>5</v></c></row>`, `<si><t>title</t></si>`), "bad shared string index"},
+		{"bad reference", buildXLSX(t, `<row r="1"><c r="1A"><v>1</v></c></row>`, ""), "bad cell reference"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readXLSX(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	for ref, want := range map[string]int{"A1": 0, "Z9": 25, "AA10": 26, "AB12": 27, "XFD1": 16383} {
+		if got, err := columnIndex(ref); err != nil || got != want {
+			t.Errorf("columnIndex(%q) = %d, %v; want %d", ref, got, err, want)
+		}
+	}
+	for _, ref := range []string{"", "12", "a1", "XFE1", "A1B"} {
+		if _, err := columnIndex(ref); err == nil {
+			t.Errorf("columnIndex(%q) succeeded, want an error", ref)
+		}
+	}
+}
+
+func TestReadTableSniffsFormat(t *testing.T) {
+	xlsx := buildXLSX(t, `<row r="1"><c r="A1" t="inlineStr"><is><t>title</t></is></c></row>`, "")
+	table, err := ReadTable("upload", bytes.NewReader(xlsx))
+	if err != nil || len(table) != 1 || table[0][0] != "title" {
+		t.Errorf("XLSX without extension: %q, %v", table, err)
+	}
+	table, err = ReadTable("jobs.csv", strings.NewReader("\xef\xbb\xbftitle,description\nA,B\n"))
+	if err != nil || !reflect.DeepEqual(table, [][]string{{"title", "description"}, {"A", "B"}}) {
+		t.Errorf("CSV with BOM: %q, %v", table, err)
+	}
+	if _, err := ReadTable("jobs.csv", strings.NewReader("title\n\xff\xfe\n")); err == nil {
+		t.Error("non-UTF-8 CSV was accepted")
+	}
+}
@@ -0,0 +1,93 @@
+// Package logging filters the standard logger's output by LOG_LEVEL.
+//
+// The server logs with the log package, which has no levels. A line's level is its
+// message's first word, so it is always explicit and never guessed from the text:
+//
+//	DEBUG, Handler:                  debug ("Handler: ..." traces request handling)
+//	INFO, or any other word          info
+//	WARN, WARNING, Warning:          warn
+//	ERROR, Error, FATAL, PANIC       error
+//
+// A component tag such as "Notify:" is not a level; failures put the level first,
+// e.g. "Warning: Notify: ...".
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sync/atomic"
+)
+
+// Levels, lowest first, as named in LOG_LEVEL
+const (
+	LevelDebug = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// levelWords maps a message's first word (without a trailing colon) to its level
+var levelWords = map[string]int{
+	"DEBUG":   LevelDebug,
+	"Handler": LevelDebug,
+	"INFO":    LevelInfo,
+	"WARN":    LevelWarn,
+	"WARNING": LevelWarn,
+	"Warning": LevelWarn,
+	"ERROR":   LevelError,
+	"Error":   LevelError,
+	"FATAL":   LevelError,
+	"PANIC":   LevelError,
+}
+
+var level atomic.Int32 // Everything is written until SetLevel is called
+
+// SetLevel changes the minimum level written; safe to call while logging
+func SetLevel(name string) error {
+	for i, n := range levelNames {
+		if n == name {
+			level.Store(int32(i))
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown log level %q", name)
+}
+
+// Level returns the current level's name
+func Level() string {
+	return levelNames[level.Load()]
+}
+
+// Writer wraps out, dropping lines below the current level. Use it with log.SetOutput;
+// the log package hands it one whole line per call.
+func Writer(out io.Writer) io.Writer {
+	return &filter{out: out}
+}
+
+type filter struct {
+	out io.Writer
+}
+
+func (f *filter) Write(p []byte) (int, error) {
+	if levelOf(p) < int(level.Load()) {
+		return len(p), nil
+	}
+	return f.out.Write(p)
+}
+
+// timestamp matches the date and time the log package puts before the message
+var timestamp = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} )?(\d{2}:\d{2}:\d{2}(\.\d+)? )?`)
+
+func levelOf(line []byte) int {
+	message := line[len(timestamp.Find(line)):]
+	word, _, _ := bytes.Cut(message, []byte(" "))
+	word = bytes.TrimSuffix(bytes.TrimSpace(word), []byte(":"))
+	if l, ok := levelWords[string(word)]; ok {
+		return l
+	}
+	return LevelInfo
+}
@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestLevelOf(t *testing.T) {
+	tests := []struct {
+		line string
+		want int
+	}{
+		{"2026/10/19 09:30:00 Error querying news: connection refused\n", LevelError},
+		{"2026/10/19 09:30:00 FATAL: Could not load configuration\n", LevelError},
+		{"2026/10/19 09:30:00 ERROR: Audit entry not written\n", LevelError},
+		{"2026/10/19 09:30:00 Warning: read replica unhealthy\n", LevelWarn},
+		{"2026/10/19 09:30:00 Warning: Notify: job alert 7 failed: timeout\n", LevelWarn},
+		{"2026/10/19 09:30:00 Handler: GetNews failed with error: not found\n", LevelDebug},
+		{"2026/10/19 09:30:00.123456 DEBUG cache miss\n", LevelDebug},
+		{"09:30:00 Error without a date\n", LevelError},
+		{"Error without a timestamp\n", LevelError},
+		// Mentions of levels or errors later in the line do not count
+		{"2026/10/19 09:30:00 Configuration reloaded (SIGHUP): LOG_LEVEL=error\n", LevelInfo},
+		{"2026/10/19 09:30:00 Notify: news 5 queued for 12 email recipients\n", LevelInfo},
+		{"2026/10/19 09:30:00 Errors are counted per hour\n", LevelInfo},
+		{"2026/10/19 09:30:00 \n", LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := levelOf([]byte(tt.line)); got != tt.want {
+			t.Errorf("levelOf(%q) = %s, want %s", tt.line, levelNames[got], levelNames[tt.want])
+		}
+	}
+}
+
+func TestWriterFiltersBelowLevel(t *testing.T) {
+	defer SetLevel(Level())
+	var out bytes.Buffer
+	logger := log.New(Writer(&out), "", log.LstdFlags)
+
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Println("Handler: tracing")
+	logger.Println("Server listening on :8080")
+	logger.Println("Warning: slow replica")
+	logger.Println("Error reading news: boom")
+	if got := bytes.Count(out.Bytes(), []byte("\n")); got != 2 {
+		t.Errorf("wrote %d lines at warn, want 2:\n%s", got, out.String())
+	}
+
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("SetLevel accepted an unknown level")
+	}
+	if Level() != "warn" {
+		t.Errorf("level = %s after a rejected change, want warn", Level())
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Context keys set by Authenticate for downstream handlers
+const (
+	ContextUserID   = "userID"
+	ContextUserRole = "userRole"
+	ContextPhone    = "userPhone"
+)
+
+// Roles stored in the Supabase app_metadata.role claim
+const (
+	RoleResident = "resident"
+	RoleOfficial = "official"
+	RoleAdmin    = "admin"
+)
+
+var errInvalidToken = errors.New("invalid access token")
+
+// supabaseClaims is the subset of the Supabase access token we care about
+type supabaseClaims struct {
+	Sub         string `json:"sub"`
+	Exp         int64  `json:"exp"`
+	Phone       string `json:"phone"`
+	AppMetadata struct {
+		Role string `json:"role"`
+	} `json:"app_metadata"`
+}
+
+// Authenticate parses an optional "Authorization: Bearer <jwt>" header issued by
+// Supabase Auth. Valid tokens populate the user ID, role and phone in the gin
+// context; requests without a token pass through anonymously so public routes
+// keep working. A present but invalid token is rejected with 401.
+func Authenticate(jwtSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" || !strings.HasPrefix(header, "Bearer ") {
+			c.Next()
+			return
+		}
+		if jwtSecret == "" {
+			// Without a secret we cannot verify anything; treat as anonymous.
+			c.Next()
+			return
+		}
+
+		claims, err := verifyHS256(strings.TrimPrefix(header, "Bearer "), jwtSecret)
+		if err != nil {
+			log.Printf("Auth: rejecting token: %v", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired access token"})
+			return
+		}
+
+		role := claims.AppMetadata.Role
+		if role == "" {
+			role = RoleResident
+		}
+		c.Set(ContextUserID, claims.Sub)
+		c.Set(ContextUserRole, role)
+		c.Set(ContextPhone, claims.Phone)
+		c.Next()
+	}
+}
+
+// RequireUser aborts with 401 unless Authenticate identified a user
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if UserID(c) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole aborts with 403 unless the authenticated user has one of the given roles.
+// Admins are always allowed.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if UserID(c) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		role := UserRole(c)
+		if role == RoleAdmin {
+			c.Next()
+			return
+		}
+		for _, r := range roles {
+			if role == r {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You do not have permission to perform this action"})
+	}
+}
+
+// UserID returns the authenticated user's ID, or "" for anonymous requests
+func UserID(c *gin.Context) string {
+	return c.GetString(ContextUserID)
+}
+
+// UserRole returns the authenticated user's role, or "" for anonymous requests
+func UserRole(c *gin.Context) string {
+	return c.GetString(ContextUserRole)
+}
+
+// IsStaff reports whether the caller is an official or admin
+func IsStaff(c *gin.Context) bool {
+	role := UserRole(c)
+	return role == RoleOfficial || role == RoleAdmin
+}
+
+// verifyHS256 checks the signature and expiry of a compact JWT signed with HS256
+func verifyHS256(token, secret string) (supabaseClaims, error) {
+	var claims supabaseClaims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(rawHeader, &header) != nil || header.Alg != "HS256" {
+		return claims, errInvalidToken
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return claims, errInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payload, &claims) != nil {
+		return claims, errInvalidToken
+	}
+	// Supabase always sets exp; a token without one would never expire
+	if claims.Sub == "" || claims.Exp == 0 || time.Now().Unix() >= claims.Exp {
+		return claims, errInvalidToken
+	}
+	return claims, nil
+}
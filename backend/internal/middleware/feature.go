@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FeatureGate answers 503 for requests under a switched-off feature. routes maps a path
+// prefix ("/api/v1/polls") to its feature; enabled is asked on every request, so a
+// configuration reload applies at once.
+func FeatureGate(routes map[string]string, enabled func(feature string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for prefix, feature := range routes {
+			if (path == prefix || strings.HasPrefix(path, prefix+"/")) && !enabled(feature) {
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "This feature is switched off", "feature": feature})
+				return
+			}
+		}
+		c.Next()
+	}
+}
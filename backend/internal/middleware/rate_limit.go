@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimits are read on every request, so a configuration reload applies at once
+type RateLimits struct {
+	PerMinute int // 0 turns the limit off
+	Burst     int
+}
+
+// RateLimit lets each client IP make PerMinute requests a minute, and up to Burst at
+// once (a token bucket per IP). Requests over the limit get 429 with Retry-After.
+// The client IP only comes from X-Forwarded-For when the router trusts the proxy that
+// sent it (TRUSTED_PROXIES).
+func RateLimit(limits func() RateLimits) gin.HandlerFunc {
+	l := &limiter{buckets: make(map[string]*bucket)}
+	return func(c *gin.Context) {
+		current := limits()
+		if current.PerMinute <= 0 {
+			c.Next()
+			return
+		}
+		if wait, ok := l.take(c.ClientIP(), current, time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests; please try again shortly"})
+			return
+		}
+		c.Next()
+	}
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxBuckets bounds the limiter's memory however many addresses clients come from
+const maxBuckets = 10000
+
+type limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// take spends a token from key's bucket, or says how long until one is available
+func (l *limiter) take(key string, limits RateLimits, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	perSecond := float64(limits.PerMinute) / 60
+	burst := float64(max(limits.Burst, 1))
+	if now.Sub(l.swept) > time.Minute {
+		l.sweep(now, perSecond, burst)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.sweep(now, perSecond, burst)
+			if len(l.buckets) >= maxBuckets {
+				l.evictOldest()
+			}
+		}
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / perSecond * float64(time.Second)), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// sweep forgets full buckets: they hold nothing a new one would not
+func (l *limiter) sweep(now time.Time, perSecond, burst float64) {
+	for k, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*perSecond >= burst {
+			delete(l.buckets, k)
+		}
+	}
+	l.swept = now
+}
+
+// evictOldest drops the least recently used bucket when every bucket is still in use
+func (l *limiter) evictOldest() {
+	var oldest string
+	var oldestLast time.Time
+	for k, b := range l.buckets {
+		if oldest == "" || b.last.Before(oldestLast) {
+			oldest, oldestLast = k, b.last
+		}
+	}
+	delete(l.buckets, oldest)
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestLimiter() *limiter {
+	return &limiter{buckets: make(map[string]*bucket)}
+}
+
+func TestLimiterBurstThenRate(t *testing.T) {
+	l := newTestLimiter()
+	limits := RateLimits{PerMinute: 60, Burst: 3} // One token a second
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := range 3 {
+		if _, ok := l.take("a", limits, now); !ok {
+			t.Fatalf("request %d of the burst was limited", i+1)
+		}
+	}
+	wait, ok := l.take("a", limits, now)
+	if ok || wait != time.Second {
+		t.Fatalf("after the burst: ok=%v wait=%s, want limited for 1s", ok, wait)
+	}
+	if _, ok := l.take("b", limits, now); !ok {
+		t.Error("another client shares the first client's bucket")
+	}
+
+	wait, ok = l.take("a", limits, now.Add(400*time.Millisecond))
+	if ok || wait != 600*time.Millisecond {
+		t.Errorf("0.4s later: ok=%v wait=%s, want limited for 0.6s", ok, wait)
+	}
+	if _, ok := l.take("a", limits, now.Add(time.Second)); !ok {
+		t.Error("a token did not refill after a second")
+	}
+	if _, ok := l.take("a", limits, now.Add(time.Second)); ok {
+		t.Error("one refilled token allowed two requests")
+	}
+	// Refill stops at the burst size however long the client was idle
+	later := now.Add(time.Hour)
+	for i := range 3 {
+		if _, ok := l.take("a", limits, later); !ok {
+			t.Fatalf("request %d after an idle hour was limited", i+1)
+		}
+	}
+	if _, ok := l.take("a", limits, later); ok {
+		t.Error("idle time refilled more than the burst")
+	}
+}
+
+func TestLimiterForgetsIdleClients(t *testing.T) {
+	l := newTestLimiter()
+	limits := RateLimits{PerMinute: 60, Burst: 5}
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	l.take("idle", limits, now)
+	l.take("busy", limits, now)
+	for range 4 {
+		l.take("busy", limits, now.Add(2*time.Minute))
+	}
+	l.take("new", limits, now.Add(2*time.Minute))
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("a refilled bucket survived the sweep")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("a bucket still refilling was swept")
+	}
+}
+
+func TestLimiterBucketCap(t *testing.T) {
+	l := newTestLimiter()
+	limits := RateLimits{PerMinute: 1, Burst: 2}
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	// Every client spends a token, so no bucket is full and none can be swept
+	for i := range maxBuckets + 100 {
+		l.take(fmt.Sprintf("10.0.%d.%d", i/256, i%256), limits, now.Add(time.Duration(i)*time.Millisecond))
+	}
+	if len(l.buckets) > maxBuckets {
+		t.Errorf("%d buckets, want at most %d", len(l.buckets), maxBuckets)
+	}
+	if _, ok := l.buckets["10.0.0.0"]; ok {
+		t.Error("the least recently used bucket was kept")
+	}
+	last := maxBuckets + 99
+	if _, ok := l.buckets[fmt.Sprintf("10.0.%d.%d", last/256, last%256)]; !ok {
+		t.Error("the newest bucket was dropped")
+	}
+}
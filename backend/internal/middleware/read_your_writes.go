@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+	"village_project/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadYourWritesCookie marks a client that wrote recently; it expires after the window
+const ReadYourWritesCookie = "village_recent_write"
+
+// ReadYourWrites keeps a client's reads on the primary database for window after it
+// writes, so it sees its own changes even while the read replica catches up. Requests
+// with an unsafe method use the primary throughout and (re)start the window with a
+// short-lived cookie; later requests carrying the cookie use the primary too.
+// With a zero window only the writing request itself is kept on the primary.
+func ReadYourWrites(window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			if _, err := c.Cookie(ReadYourWritesCookie); err != nil {
+				c.Next()
+				return
+			}
+		default:
+			if window > 0 {
+				setReadYourWritesCookie(c, window)
+			}
+		}
+		c.Request = c.Request.WithContext(database.WithPrimary(c.Request.Context()))
+		c.Next()
+	}
+}
+
+// setReadYourWritesCookie is set before the handler runs, since handlers write headers
+// with the body. Browsers only send SameSite=None cookies cross-site over HTTPS, so plain
+// HTTP (local development, where the app and API are same-site) gets Lax.
+func setReadYourWritesCookie(c *gin.Context, window time.Duration) {
+	secure := c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+	sameSite := http.SameSiteLaxMode
+	if secure {
+		sameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     ReadYourWritesCookie,
+		Value:    "1",
+		Path:     "/",
+		MaxAge:   int(window.Seconds()),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	})
+}
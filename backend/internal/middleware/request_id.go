@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// ContextRequestID is the gin context key set by AssignRequestID
+const ContextRequestID = "requestID"
+
+// validRequestID accepts IDs from proxies and clients that are safe to log and store
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// AssignRequestID tags each request with an ID, reusing a well-formed X-Request-ID from the
+// proxy or client, and echoes it in the response so reports can be matched to logs
+func AssignRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			b := make([]byte, 16)
+			rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		c.Set(ContextRequestID, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// RequestID returns the ID set by AssignRequestID, or "" if it did not run
+func RequestID(c *gin.Context) string {
+	return c.GetString(ContextRequestID)
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is one recorded change made through the API. Each entry's hash covers its
+// fields and the previous entry's hash, chaining the log (see package audit).
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ActorID    *string         `json:"actor_id"`    // null for anonymous requests
+	ActorRole  string          `json:"actor_role"`  // resident, official, admin or anonymous
+	Action     string          `json:"action"`      // Handler name, e.g. PublishNews
+	TargetType string          `json:"target_type"` // e.g. news, jobs
+	TargetID   string          `json:"target_id"`
+	Diff       json.RawMessage `json:"diff"`      // {"field": {"before": ..., "after": ...}}; null when not recorded
+	IP         string          `json:"ip"`        // Client address, from X-Forwarded-For when sent by a trusted proxy
+	RemoteIP   string          `json:"remote_ip"` // Address of the TCP peer (the proxy, if any); '' on older entries
+	RequestID  string          `json:"request_id"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+// AuditPage is one page of audit log results, newest first
+type AuditPage struct {
+	Entries []AuditEntry `json:"entries"`
+	Before  int64        `json:"before,omitempty"` // Pass as ?before= for the next page; absent on the last page
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+)
+
+// Grievance ticket statuses
+const (
+	GrievanceStatusNew          = "New"
+	GrievanceStatusAcknowledged = "Acknowledged"
+	GrievanceStatusInProgress   = "In Progress"
+	GrievanceStatusResolved     = "Resolved"
+	GrievanceStatusRejected     = "Rejected"
+)
+
+// grievanceTransitions lists the statuses each status may move to
+var grievanceTransitions = map[string][]string{
+	GrievanceStatusNew:          {GrievanceStatusAcknowledged, GrievanceStatusRejected},
+	GrievanceStatusAcknowledged: {GrievanceStatusInProgress, GrievanceStatusResolved, GrievanceStatusRejected},
+	GrievanceStatusInProgress:   {GrievanceStatusResolved, GrievanceStatusRejected},
+}
+
+// CanTransitionGrievance reports whether a ticket may move from one status to another.
+// Resolved and Rejected are terminal.
+func CanTransitionGrievance(from, to string) bool {
+	for _, next := range grievanceTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GrievanceCategory defines a complaint category and its SLA
+type GrievanceCategory struct {
+	Code              string  `json:"code"`
+	Name              string  `json:"name"`
+	SLAHours          int     `json:"sla_hours"`
+	DefaultAssigneeID *string `json:"default_assignee_id"`
+}
+
+// Grievance represents a complaint ticket raised by a resident
+type Grievance struct {
+	ID             string     `json:"id"` // UUID
+	TicketNumber   string     `json:"ticket_number"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Category       string     `json:"category"`
+	Ward           string     `json:"ward"`
+	Description    string     `json:"description"`
+	PhotoURL       *string    `json:"photo_url"`
+	ReporterName   string     `json:"reporter_name"`
+	ReporterPhone  *string    `json:"reporter_phone"`
+	ReporterUserID *string    `json:"reporter_user_id"`
+	AssignedTo     *string    `json:"assigned_to"` // UUID of the responsible official
+	Status         string     `json:"status"`
+	SLADueAt       time.Time  `json:"sla_due_at"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	Overdue        bool       `json:"overdue"` // Computed: open past its SLA due date
+}
+
+// GrievanceComment is one entry in a ticket's timeline
+type GrievanceComment struct {
+	ID           string    `json:"id"`
+	GrievanceID  string    `json:"grievance_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	AuthorUserID *string   `json:"author_user_id"`
+	AuthorName   string    `json:"author_name"`
+	Body         string    `json:"body"`
+	StatusFrom   *string   `json:"status_from"`
+	StatusTo     *string   `json:"status_to"`
+	IsInternal   bool      `json:"is_internal"`
+}
+
+// PublicGrievanceStatus is what anyone can see when looking up a ticket number.
+// It deliberately leaves out reporter details and internal notes.
+type PublicGrievanceStatus struct {
+	TicketNumber string             `json:"ticket_number"`
+	Category     string             `json:"category"`
+	Ward         string             `json:"ward"`
+	Status       string             `json:"status"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	SLADueAt     time.Time          `json:"sla_due_at"`
+	ResolvedAt   *time.Time         `json:"resolved_at"`
+	Timeline     []GrievanceComment `json:"timeline"`
+}
+
+// CreateGrievanceRequest defines the structure for reporting a new complaint
+type CreateGrievanceRequest struct {
+	Category      string `json:"category" binding:"required"`
+	Ward          string `json:"ward" binding:"required"`
+	Description   string `json:"description" binding:"required,min=10"`
+	PhotoURL      string `json:"photo_url" binding:"omitempty,url"` // Optional, uploaded to storage by the app
+	ReporterName  string `json:"reporter_name" binding:"required,min=2"`
+	ReporterPhone string `json:"reporter_phone"` // Optional
+}
+
+// UpdateGrievanceStatusRequest moves a ticket through its lifecycle
+type UpdateGrievanceStatusRequest struct {
+	Status  string `json:"status" binding:"required,oneof=Acknowledged 'In Progress' Resolved Rejected"`
+	Comment string `json:"comment"` // Optional note recorded on the timeline
+}
+
+// AssignGrievanceRequest assigns a ticket to an official
+type AssignGrievanceRequest struct {
+	AssigneeID string `json:"assignee_id" binding:"required,uuid"`
+}
+
+// AddGrievanceCommentRequest adds a note to a ticket's timeline
+type AddGrievanceCommentRequest struct {
+	Body       string `json:"body" binding:"required,min=2"`
+	IsInternal bool   `json:"is_internal"`
+}
+
+// GrievanceFilter narrows the staff ticket list
+type GrievanceFilter struct {
+	Status     string
+	Ward       string
+	Category   string
+	AssignedTo string
+	Overdue    bool
+}
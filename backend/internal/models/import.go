@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Import modes
+const (
+	ImportModeAll     = "all"     // Transactional: any invalid or failing row aborts the whole import
+	ImportModePartial = "partial" // Good rows are saved; bad rows are reported in the result file
+)
+
+// Per-row import outcomes
+const (
+	ImportRowCreated = "created" // Saved (or, in a dry run, would be saved)
+	ImportRowInvalid = "invalid" // Failed validation; never sent to the database
+	ImportRowFailed  = "failed"  // Rejected by the database
+	ImportRowSkipped = "skipped" // Not saved because another row aborted an all-or-nothing import
+)
+
+// ImportJobRow is one spreadsheet row of a job import: the fields of CreateJobRequest
+// plus the status and expiry that office staff set in their sheet
+type ImportJobRow struct {
+	CreateJobRequest
+	Status    string     `json:"status" binding:"omitempty,oneof=Pending Open Filled Expired"` // Default Open
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// ImportNewsRow is one spreadsheet row of a news import. A published row with a
+// published_at is treated as archive material: it is backdated and not announced.
+type ImportNewsRow struct {
+	CreateNewsRequest
+	Status      string     `json:"status" binding:"omitempty,oneof=draft published"` // Default draft; overrides "publish"
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+// ImportRow pairs a parsed row with its spreadsheet line and validation errors
+type ImportRow[T any] struct {
+	Line   int // 1-based line in the file, counting the header
+	Value  T
+	Errors []string
+}
+
+// ImportRowResult reports what happened to one row
+type ImportRowResult struct {
+	Line   int      `json:"line"`
+	Status string   `json:"status"` // created, invalid, failed or skipped
+	ID     string   `json:"id,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ImportReport summarizes an import or dry run
+type ImportReport struct {
+	Kind      string            `json:"kind"` // jobs or news
+	Mode      string            `json:"mode"` // all or partial
+	DryRun    bool              `json:"dry_run"`
+	Committed bool              `json:"committed"` // false for dry runs and aborted all-or-nothing imports
+	Total     int               `json:"total"`
+	Created   int               `json:"created"`
+	Invalid   int               `json:"invalid"`
+	Failed    int               `json:"failed"`
+	Skipped   int               `json:"skipped"`
+	Rows      []ImportRowResult `json:"rows"`
+}
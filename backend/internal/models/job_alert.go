@@ -0,0 +1,122 @@
+package models
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"village_project/internal/search"
+)
+
+// JobAlert is a resident's saved search for new jobs
+type JobAlert struct {
+	ID        string    `json:"id"` // UUID
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Keywords  []string  `json:"keywords"`
+	Location  *string   `json:"location"`
+	MinPay    *int      `json:"min_pay"` // Rupees
+	Paused    bool      `json:"paused"`
+}
+
+// JobAlertNotification is a queued notice that a job matched a subscriber's alert
+type JobAlertNotification struct {
+	ID        string     `json:"id"`
+	AlertID   string     `json:"alert_id"`
+	JobID     string     `json:"job_id"`
+	UserID    string     `json:"user_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Status    string     `json:"status"` // pending, sent, failed
+	SentAt    *time.Time `json:"sent_at"`
+	JobTitle  string     `json:"job_title"`
+}
+
+// CreateJobAlertRequest defines the structure for saving a job alert
+type CreateJobAlertRequest struct {
+	Keywords []string `json:"keywords" binding:"max=10,dive,min=2,max=50"`
+	Location string   `json:"location" binding:"max=100"`
+	MinPay   *int     `json:"min_pay" binding:"omitempty,min=0"`
+}
+
+// UpdateJobAlertRequest pauses or resumes an alert
+type UpdateJobAlertRequest struct {
+	Paused *bool `json:"paused" binding:"required"`
+}
+
+var (
+	payAmountPattern = regexp.MustCompile(`\d[\d,]*`)
+	// An amount next to a currency marker: "₹500", "Rs. 1,200", "రూ.400", "500 rupees", "300/-"
+	rupeeAmountPattern = regexp.MustCompile(`(?i)(?:₹|\brs\.?|\binr\b|రూ\.?)\s*(\d[\d,]*)|(\d[\d,]*)\s*(?:/-|rupees\b|రూపాయలు)`)
+)
+
+// ParsePayAmount extracts the rupee amount from free-text payment details, e.g.
+// "8am-5pm, Rs. 1,200 per day" -> 1200. An amount marked as rupees wins; otherwise the
+// largest number is taken, as hours and dates are smaller than any day's pay. It returns
+// false when no amount is present.
+func ParsePayAmount(details *string) (int, bool) {
+	if details == nil {
+		return 0, false
+	}
+	if m := rupeeAmountPattern.FindStringSubmatch(*details); m != nil {
+		return parseAmount(m[1] + m[2])
+	}
+	best, found := 0, false
+	for _, match := range payAmountPattern.FindAllString(*details, -1) {
+		if amount, ok := parseAmount(match); ok && amount >= best {
+			best, found = amount, true
+		}
+	}
+	return best, found
+}
+
+func parseAmount(digits string) (int, bool) {
+	amount, err := strconv.Atoi(strings.ReplaceAll(digits, ",", ""))
+	if err != nil {
+		return 0, false
+	}
+	return amount, true
+}
+
+// Matches reports whether a job satisfies this alert. Any keyword may match the
+// title, description or location; location and minimum pay must both hold when set.
+func (a JobAlert) Matches(job Job) bool {
+	if a.Paused {
+		return false
+	}
+
+	if len(a.Keywords) > 0 {
+		haystack := strings.ToLower(job.Title + " " + job.Description)
+		if job.Location != nil {
+			haystack += " " + strings.ToLower(*job.Location)
+		}
+		// Substring match, or a search match so "koolie" finds "కూలీ" and "paddy" finds "వరి"
+		terms := search.Terms(haystack)
+		found := false
+		for _, kw := range a.Keywords {
+			groups := search.QueryTerms(kw)
+			if strings.Contains(haystack, strings.ToLower(strings.TrimSpace(kw))) ||
+				(len(groups) > 0 && search.Match(terms, groups)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if a.Location != nil && *a.Location != "" {
+		if job.Location == nil || !strings.Contains(strings.ToLower(*job.Location), strings.ToLower(*a.Location)) {
+			return false
+		}
+	}
+
+	if a.MinPay != nil && *a.MinPay > 0 {
+		pay, ok := ParsePayAmount(job.PaymentDetails)
+		if !ok || pay < *a.MinPay {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestParsePayAmount(t *testing.T) {
+	cases := []struct {
+		details string
+		want    int
+		ok      bool
+	}{
+		{"Rs. 1,200 per day", 1200, true},
+		{"8am-5pm, Rs 500/day", 500, true},
+		{"₹450 రోజుకు", 450, true},
+		{"రోజుకు రూ.400, 9 గంటలు", 400, true},
+		{"600 rupees, 2 meals", 600, true},
+		{"350/- per day from 7am", 350, true},
+		{"8 hours, 700 per day", 700, true},
+		{"negotiable", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := ParsePayAmount(&tc.details)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ParsePayAmount(%q) = %d, %v; want %d, %v", tc.details, got, ok, tc.want, tc.ok)
+		}
+	}
+	if _, ok := ParsePayAmount(nil); ok {
+		t.Error("ParsePayAmount(nil) found an amount")
+	}
+}
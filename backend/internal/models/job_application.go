@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// Job application statuses
+const (
+	ApplicationStatusApplied     = "Applied"
+	ApplicationStatusShortlisted = "Shortlisted"
+	ApplicationStatusHired       = "Hired"
+	ApplicationStatusDeclined    = "Declined"
+)
+
+// JobApplication represents a worker's expression of interest in a job
+type JobApplication struct {
+	ID              string    `json:"id"` // UUID
+	JobID           string    `json:"job_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	ApplicantName   string    `json:"applicant_name"`
+	ApplicantPhone  string    `json:"applicant_phone"`
+	Note            *string   `json:"note"`
+	Availability    *string   `json:"availability"` // e.g. "From Monday, mornings only"
+	ApplicantUserID *string   `json:"applicant_user_id"`
+	Status          string    `json:"status"` // Applied, Shortlisted, Hired, Declined
+}
+
+// CreateJobApplicationRequest defines the structure for applying to a job
+type CreateJobApplicationRequest struct {
+	ApplicantName  string `json:"applicant_name" binding:"required,min=2"`
+	ApplicantPhone string `json:"applicant_phone" binding:"required,min=10,max=15"`
+	Note           string `json:"note" binding:"max=500"`
+	Availability   string `json:"availability" binding:"max=200"`
+}
+
+// UpdateJobApplicationRequest lets a poster review an applicant
+type UpdateJobApplicationRequest struct {
+	Status string `json:"status" binding:"required,oneof=Shortlisted Hired Declined"`
+}
+
+// JobApplicationUpdateResult reports the reviewed application and whether the job was filled as a result
+type JobApplicationUpdateResult struct {
+	Application JobApplication `json:"application"`
+	HiredCount  int            `json:"hired_count"`
+	JobFilled   bool           `json:"job_filled"`
+}
@@ -13,4 +13,42 @@ type News struct {
 	Content     *string   `json:"content"` // Use pointer for nullable text field
 	PublishedAt time.Time `json:"published_at"`
 	Status      string    `json:"status"`
+	Language    string    `json:"language"` // Locale of Title/Content as returned
+
+	AvailableLanguages []string `json:"available_languages,omitempty"` // Original first, then translations
+}
+
+// NewsArchiveMonth is one month of the news archive: how many items were published in it
+// (months are in IST, the village's time zone)
+type NewsArchiveMonth struct {
+	Year  int `json:"year"`
+	Month int `json:"month"` // 1-12
+	Count int `json:"count"`
+}
+
+// News statuses
+const (
+	NewsStatusDraft     = "draft"
+	NewsStatusPublished = "published"
+)
+
+// CreateNewsRequest is the body for posting a news item. With Publish set the item
+// goes live (and subscribers are notified) immediately; otherwise it is saved as a draft.
+type CreateNewsRequest struct {
+	Title   string  `json:"title" binding:"required,min=5,max=200"`
+	Content *string `json:"content"`
+	Publish bool    `json:"publish"`
+	// Language the item is written in; defaults to the fallback locale
+	Language string `json:"language"`
+}
+
+// NewsNotification is one queued announcement of a published news item: an email to a
+// resident (UserID) or a Web Push to one browser (SubscriptionID)
+type NewsNotification struct {
+	ID             string  `json:"id"`
+	NewsID         string  `json:"news_id"`
+	UserID         *string `json:"user_id"`
+	SubscriptionID *string `json:"subscription_id"`
+	Status         string  `json:"status"` // pending, sending, sent, failed
+	News           News    `json:"news"`
 }
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Domain event types written to the outbox. Webhook subscribers see the same names.
+const (
+	EventJobCreated    = "job.created"
+	EventJobOpened     = "job.opened" // A pending job was approved
+	EventJobFilled     = "job.filled"
+	EventJobExpired    = "job.expired"
+	EventNewsPublished = "news.published"
+)
+
+// JobStatusEvent maps a job's new status to its event type, or "" if none applies
+func JobStatusEvent(status string) string {
+	switch status {
+	case "Open":
+		return EventJobOpened
+	case "Filled":
+		return EventJobFilled
+	case "Expired":
+		return EventJobExpired
+	}
+	return ""
+}
+
+// Outbox event statuses
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusDone    = "done"
+	OutboxStatusDead    = "dead" // Gave up after the maximum number of attempts
+)
+
+// OutboxEvent is a domain event waiting for (or finished with) in-process delivery
+type OutboxEvent struct {
+	ID                int64           `json:"id"`
+	EventID           string          `json:"event_id"`
+	CreatedAt         time.Time       `json:"created_at"`
+	AggregateType     string          `json:"aggregate_type"`
+	AggregateID       string          `json:"aggregate_id"`
+	EventType         string          `json:"event_type"`
+	Payload           json.RawMessage `json:"payload"`
+	Status            string          `json:"status"`
+	Attempts          int             `json:"attempts"`
+	NextAttemptAt     time.Time       `json:"next_attempt_at"`
+	CompletedHandlers []string        `json:"completed_handlers"`
+	LastError         *string         `json:"last_error"`
+	ProcessedAt       *time.Time      `json:"processed_at"`
+}
+
+// OutboxFilter narrows the outbox listing for admins
+type OutboxFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending done dead"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+)
+
+// Poll kinds
+const (
+	PollKindSingle   = "single"
+	PollKindMultiple = "multiple"
+)
+
+// Poll results visibility settings
+const (
+	PollResultsAfterVote  = "after_vote"  // Voters see tallies once they have voted
+	PollResultsAfterClose = "after_close" // Tallies are hidden until the poll closes
+)
+
+// Poll represents a council sentiment check or survey question
+type Poll struct {
+	ID                string       `json:"id"` // UUID
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+	Question          string       `json:"question"`
+	Description       *string      `json:"description"`
+	Kind              string       `json:"kind"`        // "single" or "multiple"
+	MaxChoices        *int         `json:"max_choices"` // Only for multiple-choice polls
+	Anonymous         bool         `json:"anonymous"`   // Vote per device token instead of per resident
+	ResultsVisibility string       `json:"results_visibility"`
+	OpensAt           time.Time    `json:"opens_at"`
+	ClosesAt          time.Time    `json:"closes_at"`
+	CreatedByUserID   *string      `json:"created_by_user_id"`
+	Options           []PollOption `json:"options"`
+	HasVoted          bool         `json:"has_voted"` // Computed for the current caller
+}
+
+// IsOpen reports whether votes are accepted at the given time
+func (p Poll) IsOpen(now time.Time) bool {
+	return !now.Before(p.OpensAt) && now.Before(p.ClosesAt)
+}
+
+// PollOption is one choice within a poll
+type PollOption struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Position int    `json:"position"`
+}
+
+// PollOptionTally is the vote count for one option
+type PollOptionTally struct {
+	OptionID string `json:"option_id"`
+	Label    string `json:"label"`
+	Votes    int    `json:"votes"`
+}
+
+// PollResults holds the live tally for a poll
+type PollResults struct {
+	PollID       string            `json:"poll_id"`
+	Question     string            `json:"question"`
+	TotalBallots int               `json:"total_ballots"`
+	Options      []PollOptionTally `json:"options"`
+	Closed       bool              `json:"closed"`
+	GeneratedAt  time.Time         `json:"generated_at"`
+}
+
+// CreatePollRequest defines the structure for creating a new poll
+type CreatePollRequest struct {
+	Question          string    `json:"question" binding:"required,min=5"`
+	Description       string    `json:"description"`
+	Kind              string    `json:"kind" binding:"required,oneof=single multiple"`
+	MaxChoices        *int      `json:"max_choices" binding:"omitempty,min=1"`
+	Anonymous         bool      `json:"anonymous"`
+	ResultsVisibility string    `json:"results_visibility" binding:"omitempty,oneof=after_vote after_close"`
+	OpensAt           time.Time `json:"opens_at"` // Optional, defaults to now
+	ClosesAt          time.Time `json:"closes_at" binding:"required"`
+	Options           []string  `json:"options" binding:"required,min=2,max=20,dive,required"`
+}
+
+// CastVoteRequest is a ballot for one poll
+type CastVoteRequest struct {
+	OptionIDs []string `json:"option_ids" binding:"required,min=1,dive,uuid"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// PushSubscription is a browser Web Push subscription owned by a user or a device
+type PushSubscription struct {
+	ID            string     `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	UserID        *string    `json:"user_id"`
+	DeviceKey     *string    `json:"-"`
+	Endpoint      string     `json:"endpoint"`
+	P256dh        string     `json:"-"`
+	Auth          string     `json:"-"`
+	UserAgent     *string    `json:"user_agent"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	LastSuccessAt *time.Time `json:"last_success_at"`
+	FailureCount  int        `json:"failure_count"`
+}
+
+// PushSubscriptionKeys are the browser's encryption keys from PushSubscription.toJSON()
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" binding:"required"`
+	Auth   string `json:"auth" binding:"required"`
+}
+
+// RegisterPushSubscriptionRequest is the JSON form of a browser PushSubscription
+type RegisterPushSubscriptionRequest struct {
+	Endpoint       string               `json:"endpoint" binding:"required,url,max=1024"`
+	ExpirationTime *int64               `json:"expirationTime"` // Milliseconds since the epoch, or null
+	Keys           PushSubscriptionKeys `json:"keys" binding:"required"`
+}
+
+// UnregisterPushSubscriptionRequest identifies the subscription to remove
+type UnregisterPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// SMS message statuses
+const (
+	SMSStatusQueued    = "queued"
+	SMSStatusSubmitted = "submitted" // Accepted by the provider
+	SMSStatusSent      = "sent"      // Handed to the operator
+	SMSStatusDelivered = "delivered" // Confirmed by a delivery callback
+	SMSStatusFailed    = "failed"
+	SMSStatusCapped    = "capped" // Not sent: recipient reached the daily cap
+)
+
+// SMSMessage is one outgoing text message and its delivery status
+type SMSMessage struct {
+	ID                string     `json:"id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	Recipient         string     `json:"recipient"`
+	Event             string     `json:"event"`
+	Body              string     `json:"body"`
+	Encoding          string     `json:"encoding"` // GSM-7 or UCS-2
+	Segments          int        `json:"segments"`
+	DLTTemplateID     *string    `json:"dlt_template_id"`
+	Provider          string     `json:"provider"`
+	ProviderMessageID *string    `json:"provider_message_id"`
+	Status            string     `json:"status"`
+	StatusDetail      *string    `json:"status_detail"`
+	DeliveredAt       *time.Time `json:"delivered_at"`
+}
+
+// SMSDeliveryCallback is the normalized body of a provider delivery report
+type SMSDeliveryCallback struct {
+	ProviderMessageID string
+	Status            string // One of the SMSStatus constants
+	Detail            string
+}
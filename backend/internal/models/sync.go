@@ -0,0 +1,27 @@
+package models
+
+// Collections returned by the delta sync endpoint
+const (
+	SyncCollectionNews = "news"
+	SyncCollectionJobs = "jobs"
+)
+
+// SyncChange is one item that changed since the client's token. Live items carry the
+// full record; anything the client should no longer show (deleted, unpublished, no longer
+// open) is a tombstone with Deleted set and only the ID.
+type SyncChange struct {
+	Collection string `json:"collection"` // "news" or "jobs"
+	ID         string `json:"id"`
+	Seq        int64  `json:"seq"`               // Position in the global change sequence
+	Deleted    bool   `json:"deleted,omitempty"` // Tombstone: drop the item locally
+	News       *News  `json:"news,omitempty"`
+	Job        *Job   `json:"job,omitempty"`
+}
+
+// SyncResponse is the body of GET /api/v1/sync
+type SyncResponse struct {
+	Changes []SyncChange `json:"changes"`  // Ordered by Seq
+	Token   string       `json:"token"`    // Pass as ?since= on the next call
+	HasMore bool         `json:"has_more"` // More changes are waiting; call again with Token now
+	Reset   bool         `json:"reset"`    // First page of a full snapshot (no token given, or it expired): replace the local copy
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// NewsTranslation is a news item's text in one additional locale
+type NewsTranslation struct {
+	NewsID    string    `json:"news_id"`
+	Locale    string    `json:"locale"`
+	Title     string    `json:"title"`
+	Content   *string   `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UpdatedBy *string   `json:"updated_by"`
+}
+
+// UpsertNewsTranslationRequest adds or replaces the translation for one locale
+type UpsertNewsTranslationRequest struct {
+	Title   string  `json:"title" binding:"required,min=5,max=200"`
+	Content *string `json:"content"`
+}
+
+// JobTranslation is a job's text in one additional locale. Location and
+// PaymentDetails are optional; when nil the original's values are shown.
+type JobTranslation struct {
+	JobID          string    `json:"job_id"`
+	Locale         string    `json:"locale"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description"`
+	Location       *string   `json:"location"`
+	PaymentDetails *string   `json:"payment_details"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	UpdatedBy      *string   `json:"updated_by"`
+}
+
+// UpsertJobTranslationRequest adds or replaces the translation for one locale
+type UpsertJobTranslationRequest struct {
+	Title          string  `json:"title" binding:"required,min=5"`
+	Description    string  `json:"description" binding:"required,min=10"`
+	Location       *string `json:"location"`
+	PaymentDetails *string `json:"payment_details"`
+}
+
+// Apply overlays the translation onto a news item
+func (t NewsTranslation) Apply(n *News) {
+	n.Title = t.Title
+	n.Content = t.Content
+	n.Language = t.Locale
+}
+
+// Apply overlays the translation onto a job; untranslated optional fields keep the original
+func (t JobTranslation) Apply(j *Job) {
+	j.Title = t.Title
+	j.Description = t.Description
+	if t.Location != nil {
+		j.Location = t.Location
+	}
+	if t.PaymentDetails != nil {
+		j.PaymentDetails = t.PaymentDetails
+	}
+	j.Language = t.Locale
+}
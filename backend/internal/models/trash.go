@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+// TrashItem is a deleted news item or job that can still be restored
+type TrashItem struct {
+	Collection string    `json:"collection"` // news or jobs
+	ID         string    `json:"id"`
+	Title      string    `json:"title"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	DeletedBy  *string   `json:"deleted_by"` // User ID; null if deleted before this was recorded
+	PurgeAt    time.Time `json:"purge_at"`   // When it will be removed for good
+}
@@ -0,0 +1,9 @@
+package models
+
+// UserContact holds how to reach a Supabase user (read from auth.users)
+type UserContact struct {
+	UserID string  `json:"user_id"`
+	Name   string  `json:"name"`
+	Email  *string `json:"email"`
+	Phone  *string `json:"phone"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySending   = "sending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"    // Gave up after the maximum number of attempts
+	WebhookDeliveryCancelled = "cancelled" // The subscription was deactivated before it was sent
+)
+
+// WebhookSubscription is an external endpoint that receives selected events
+type WebhookSubscription struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	Name       string    `json:"name"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"` // Only returned when the subscription is created
+	Active     bool      `json:"active"`
+	CreatedBy  *string   `json:"created_by"`
+}
+
+// CreateWebhookRequest registers a webhook. A secret is generated when none is given.
+type CreateWebhookRequest struct {
+	Name       string   `json:"name" binding:"required,min=3,max=100"`
+	URL        string   `json:"url" binding:"required,url,max=1024"`
+	EventTypes []string `json:"event_types" binding:"required,min=1,dive,required"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=200"`
+}
+
+// UpdateWebhookRequest changes a webhook; omitted fields are left as they are
+type UpdateWebhookRequest struct {
+	Name       *string  `json:"name" binding:"omitempty,min=3,max=100"`
+	URL        *string  `json:"url" binding:"omitempty,url,max=1024"`
+	EventTypes []string `json:"event_types" binding:"omitempty,min=1,dive,required"`
+	Active     *bool    `json:"active"`
+}
+
+// WebhookDelivery is one attempt sequence to deliver an event to a subscription
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseCode   *int            `json:"response_code"`
+	ResponseBody   *string         `json:"response_body"`
+	LastError      *string         `json:"last_error"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	RedeliveryOf   *string         `json:"redelivery_of"`
+
+	// Filled when a delivery is claimed for sending
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookDeliveryFilter narrows the delivery log
+type WebhookDeliveryFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending sending succeeded failed cancelled"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=200"`
+}
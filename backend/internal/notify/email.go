@@ -0,0 +1,247 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+	"village_project/internal/config"
+)
+
+// Email delivery modes (EMAIL_MODE)
+const (
+	EmailModeSMTP     = "smtp"     // Real SMTP server, or a local catcher such as MailHog on :1025
+	EmailModeFile     = "file"     // Development: write each message as an .eml file
+	EmailModeDisabled = "disabled" // Log and drop
+)
+
+// Mailer transmits one encoded RFC 5322 message
+type Mailer interface {
+	Deliver(ctx context.Context, from string, to []string, raw []byte) error
+}
+
+// EmailChannel renders nothing itself: it encodes rendered Messages as
+// multipart/alternative emails and delivers them with retry and backoff.
+type EmailChannel struct {
+	From   mail.Address
+	Mailer Mailer
+	Retry  RetryPolicy
+}
+
+// NewEmailChannel builds the email channel selected by cfg.EmailMode.
+// It returns nil when email is disabled.
+func NewEmailChannel(cfg config.Config) (*EmailChannel, error) {
+	from, err := mail.ParseAddress(cfg.SMTPFrom)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_FROM %q: %w", cfg.SMTPFrom, err)
+	}
+
+	retry := DefaultRetryPolicy
+	if cfg.NotifyMaxAttempts > 0 {
+		retry.MaxAttempts = cfg.NotifyMaxAttempts
+	}
+
+	var mailer Mailer
+	switch cfg.EmailMode {
+	case EmailModeSMTP:
+		mailer = &SMTPMailer{
+			Host:        cfg.SMTPHost,
+			Port:        cfg.SMTPPort,
+			Username:    cfg.SMTPUsername,
+			Password:    cfg.SMTPPassword,
+			ImplicitTLS: cfg.SMTPImplicitTLS,
+		}
+		log.Printf("Email channel: SMTP via %s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	case EmailModeFile:
+		if err := os.MkdirAll(cfg.EmailOutboxDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating EMAIL_OUTBOX_DIR: %w", err)
+		}
+		mailer = &FileMailer{Dir: cfg.EmailOutboxDir}
+		log.Printf("Email channel: writing messages to %s", cfg.EmailOutboxDir)
+	case EmailModeDisabled, "":
+		log.Println("Email channel: disabled")
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown EMAIL_MODE %q (want smtp, file or disabled)", cfg.EmailMode)
+	}
+
+	return &EmailChannel{From: *from, Mailer: mailer, Retry: retry}, nil
+}
+
+// Send encodes and delivers a rendered message, retrying transient failures
+func (e *EmailChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To.Email == "" {
+		return fmt.Errorf("%w: recipient has no email address", ErrPermanent)
+	}
+	to, err := mail.ParseAddress(msg.To.Email)
+	if err != nil {
+		return fmt.Errorf("%w: invalid recipient address %q", ErrPermanent, msg.To.Email)
+	}
+	to.Name = msg.To.Name
+
+	raw, err := encodeEmail(e.From, *to, msg)
+	if err != nil {
+		return fmt.Errorf("%w: encoding message: %v", ErrPermanent, err)
+	}
+
+	return Retry(ctx, e.Retry, "email to "+to.Address, func(ctx context.Context) error {
+		return e.Mailer.Deliver(ctx, e.From.Address, []string{to.Address}, raw)
+	})
+}
+
+// encodeEmail builds a multipart/alternative message with quoted-printable UTF-8 parts
+func encodeEmail(from, to mail.Address, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	idBytes := make([]byte, 12)
+	rand.Read(idBytes)
+	domain := "localhost"
+	if at := bytes.LastIndexByte([]byte(from.Address), '@'); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", hex.EncodeToString(idBytes), domain)
+	fmt.Fprintf(&buf, "X-Village-Event: %s\r\n", msg.Event)
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.TextBody},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	} {
+		if part.body == "" {
+			continue
+		}
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SMTPMailer delivers through an SMTP server. STARTTLS is used when the server
+// offers it; ImplicitTLS connects with TLS from the start (port 465).
+// MailHog and similar catchers work with no credentials on port 1025.
+type SMTPMailer struct {
+	Host        string
+	Port        int
+	Username    string
+	Password    string
+	ImplicitTLS bool
+}
+
+// Deliver sends one message over a fresh SMTP connection
+func (m *SMTPMailer) Deliver(ctx context.Context, from string, to []string, raw []byte) error {
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+
+	var conn net.Conn
+	var err error
+	if m.ImplicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: m.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !m.ImplicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: m.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return fmt.Errorf("%w: SMTP auth: %v", ErrPermanent, err)
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			// 5xx replies mean the address was rejected; retrying will not help
+			if tpErr, ok := err.(*textproto.Error); ok && tpErr.Code >= 500 {
+				return fmt.Errorf("%w: %v", ErrPermanent, err)
+			}
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// FileMailer writes each message to Dir as an .eml file for local development
+type FileMailer struct {
+	Dir string
+}
+
+// Deliver writes the raw message to disk
+func (m *FileMailer) Deliver(ctx context.Context, from string, to []string, raw []byte) error {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000"), hex.EncodeToString(suffix))
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		return err
+	}
+	log.Printf("Email written to %s (to %v)", path, to)
+	return nil
+}
@@ -0,0 +1,86 @@
+// Package notify delivers user-facing notifications (email and SMS) for domain events
+// such as job alert matches, published news, grievance updates and emergency notices.
+package notify
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// Events that produce notifications. Each has a template set under templates/.
+const (
+	EventJobAlertMatch    = "job_alert_match"
+	EventNewsPublished    = "news_published"
+	EventGrievanceUpdated = "grievance_updated"
+	EventEmergencyNotice  = "emergency_notice"
+)
+
+var allEvents = []string{EventJobAlertMatch, EventNewsPublished, EventGrievanceUpdated, EventEmergencyNotice}
+
+// Recipient is the person a message is rendered for and sent to
+type Recipient struct {
+	UserID string
+	Name   string
+	Email  string
+	Phone  string
+}
+
+// Message is a fully rendered notification ready for a channel
+type Message struct {
+	Event    string
+	To       Recipient
+	Subject  string
+	TextBody string
+	HTMLBody string
+	SMSBody  string // Empty when the event has no SMS template
+}
+
+// ErrPermanent wraps failures that retrying cannot fix (bad address, rejected template...)
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// RetryPolicy controls how often a send is attempted
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // Doubled after each failed attempt, with jitter
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is used when a channel is built without an explicit policy
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 4, BaseDelay: 2 * time.Second, MaxDelay: time.Minute}
+
+// Retry calls fn until it succeeds, returns an ErrPermanent error, the attempts run
+// out or ctx is cancelled. Delays grow exponentially with +/-20% jitter.
+func Retry(ctx context.Context, policy RetryPolicy, what string, fn func(ctx context.Context) error) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := policy.BaseDelay
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if errors.Is(err, ErrPermanent) || attempt == attempts {
+			break
+		}
+
+		wait := delay + time.Duration((rand.Float64()*0.4-0.2)*float64(delay))
+		log.Printf("Warning: Notify: %s failed (attempt %d/%d): %v; retrying in %s", what, attempt, attempts, err, wait.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		delay *= 2
+		if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+	return err
+}
@@ -0,0 +1,421 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"village_project/internal/config"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+)
+
+// Service renders notifications per recipient and hands them to the configured channels.
+// It also drains the job alert notification queue filled by JobRepository and the news
+// notification queue filled by HandleNewsPublishedEvent.
+type Service struct {
+	Renderer *Renderer
+	Email    *EmailChannel // nil when EMAIL_MODE=disabled
+	SMS      *SMSChannel   // nil when SMS_PROVIDER=disabled
+	Push     *PushChannel  // nil when no VAPID keys are configured
+	AppURL   string
+
+	Contacts *repository.UserContactRepository
+	Alerts   *repository.JobAlertRepository
+	Jobs     *repository.JobRepository
+	News     *repository.NewsNotificationRepository
+
+	PollInterval time.Duration
+	BatchSize    int
+
+	// Sends started with Go, so Drain can wait for them at shutdown
+	inflight sync.WaitGroup
+	bgOnce   sync.Once
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+}
+
+// NewService wires the notification channels selected in cfg
+func NewService(cfg config.Config, contacts *repository.UserContactRepository, alerts *repository.JobAlertRepository, jobs *repository.JobRepository, news *repository.NewsNotificationRepository, smsRepo *repository.SMSRepository, pushRepo *repository.PushRepository) (*Service, error) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	email, err := NewEmailChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sms, err := NewSMSChannel(cfg, smsRepo)
+	if err != nil {
+		return nil, err
+	}
+	push, err := NewPushChannel(cfg, pushRepo)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		Renderer:     renderer,
+		Email:        email,
+		SMS:          sms,
+		Push:         push,
+		AppURL:       cfg.PublicAppURL,
+		Contacts:     contacts,
+		Alerts:       alerts,
+		Jobs:         jobs,
+		News:         news,
+		PollInterval: 15 * time.Second,
+		BatchSize:    50,
+	}, nil
+}
+
+// Go runs send in the background with a context that outlives the request, and tracks
+// it so Drain can wait for it at shutdown. A nil Service runs nothing.
+func (s *Service) Go(send func(ctx context.Context)) {
+	if s == nil {
+		return
+	}
+	ctx := s.background()
+	s.inflight.Add(1)
+	go func() {
+		defer s.inflight.Done()
+		send(ctx)
+	}()
+}
+
+// background returns the context of sends started with Go; Drain cancels it
+func (s *Service) background() context.Context {
+	s.bgOnce.Do(func() { s.bgCtx, s.bgCancel = context.WithCancel(context.Background()) })
+	return s.bgCtx
+}
+
+// Drain waits for the sends started with Go. When ctx ends first they are cancelled,
+// so they stop between retries instead of being cut off when the process exits.
+func (s *Service) Drain(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.background()
+		s.bgCancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Notify renders event for one recipient and sends it on every channel the recipient can use.
+// It returns an error only if no channel succeeded.
+func (s *Service) Notify(ctx context.Context, event string, to Recipient, data any) error {
+	return s.notify(ctx, event, to, data, false)
+}
+
+func (s *Service) notify(ctx context.Context, event string, to Recipient, data any, priority bool) error {
+	msg, err := s.Renderer.Render(event, to, s.AppURL, data)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	delivered := false
+	if s.Email != nil && to.Email != "" {
+		if err := s.Email.Send(ctx, msg); err != nil {
+			errs = append(errs, fmt.Errorf("email: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+	if s.SMS != nil && to.Phone != "" && msg.SMSBody != "" {
+		if err := s.SMS.Send(ctx, msg, priority); err != nil {
+			errs = append(errs, fmt.Errorf("sms: %w", err))
+		} else {
+			delivered = true
+		}
+	}
+
+	if delivered {
+		return nil
+	}
+	if len(errs) == 0 {
+		return errNoChannel
+	}
+	return errors.Join(errs...)
+}
+
+// errNoChannel means the recipient cannot be reached on any enabled channel
+var errNoChannel = errors.New("no notification channel available for recipient")
+
+// recipientFor looks up a user's contact details
+func (s *Service) recipientFor(ctx context.Context, userID string) (Recipient, error) {
+	contact, err := s.Contacts.GetContact(ctx, userID)
+	if err != nil {
+		return Recipient{}, err
+	}
+	to := Recipient{UserID: userID, Name: contact.Name}
+	if contact.Email != nil {
+		to.Email = *contact.Email
+	}
+	if contact.Phone != nil {
+		to.Phone = *contact.Phone
+	}
+	return to, nil
+}
+
+// GrievanceData is the template payload for EventGrievanceUpdated
+type GrievanceData struct {
+	Grievance models.Grievance
+	Comment   string
+}
+
+// GrievanceUpdated tells the reporter that their ticket changed. Anonymous reporters
+// without an account are skipped here. Handlers run it through Go.
+func (s *Service) GrievanceUpdated(ctx context.Context, g models.Grievance, comment string) {
+	if s == nil || g.ReporterUserID == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	to, err := s.recipientFor(ctx, *g.ReporterUserID)
+	if err != nil {
+		log.Printf("Warning: Notify: cannot find reporter of grievance %s: %v", g.TicketNumber, err)
+		return
+	}
+	if to.Name == "" {
+		to.Name = g.ReporterName
+	}
+	if err := s.Notify(ctx, EventGrievanceUpdated, to, GrievanceData{Grievance: g, Comment: comment}); err != nil {
+		log.Printf("Warning: Notify: grievance %s update not delivered: %v", g.TicketNumber, err)
+	}
+}
+
+// HandleNewsPublishedEvent is the outbox handler for news.published. It only queues one
+// notification per recipient on the enabled channels; Run delivers them. An error makes
+// the outbox retry, and the queue ignores recipients already queued for the item.
+func (s *Service) HandleNewsPublishedEvent(ctx context.Context, event models.OutboxEvent) error {
+	var news models.News
+	if err := json.Unmarshal(event.Payload, &news); err != nil {
+		return fmt.Errorf("decoding news payload: %w", err)
+	}
+	if s.Push != nil {
+		queued, err := s.News.QueuePushes(ctx, news.ID)
+		if err != nil {
+			return fmt.Errorf("queueing news pushes: %w", err)
+		}
+		log.Printf("Notify: news %s queued for %d push subscriptions", news.ID, queued)
+	}
+	if s.Email != nil {
+		queued, err := s.News.QueueEmails(ctx, news.ID)
+		if err != nil {
+			return fmt.Errorf("queueing news emails: %w", err)
+		}
+		log.Printf("Notify: news %s queued for %d email recipients", news.ID, queued)
+	}
+	return nil
+}
+
+// link builds an absolute app URL for notifications
+func (s *Service) link(path string) string {
+	return strings.TrimRight(s.AppURL, "/") + path
+}
+
+// pushExcerpt shortens text to fit comfortably in a system notification
+func pushExcerpt(text *string) string {
+	if text == nil {
+		return ""
+	}
+	excerpt := strings.Join(strings.Fields(*text), " ")
+	if runes := []rune(excerpt); len(runes) > 140 {
+		excerpt = strings.TrimSpace(string(runes[:139])) + "…"
+	}
+	return excerpt
+}
+
+// EmergencyData is the template payload for EventEmergencyNotice
+type EmergencyData struct {
+	Title   string
+	Message string
+}
+
+// EmergencyNotice sends an urgent notice to every resident with a phone number or email.
+// SMS for emergencies bypasses the daily cap. Handlers run it through Go.
+func (s *Service) EmergencyNotice(ctx context.Context, title, message string) {
+	if s == nil {
+		return
+	}
+	contacts, err := s.Contacts.ListReachableContacts(ctx)
+	if err != nil {
+		log.Printf("Warning: Notify: cannot list residents for emergency notice: %v", err)
+		return
+	}
+
+	sent := 0
+	for _, c := range contacts {
+		to := Recipient{UserID: c.UserID, Name: c.Name}
+		if c.Email != nil {
+			to.Email = *c.Email
+		}
+		if c.Phone != nil {
+			to.Phone = *c.Phone
+		}
+		if err := s.notify(ctx, EventEmergencyNotice, to, EmergencyData{Title: title, Message: message}, true); err != nil {
+			log.Printf("Warning: Notify: emergency notice not delivered to %s: %v", c.UserID, err)
+			continue
+		}
+		sent++
+	}
+	log.Printf("Notify: emergency notice %q sent to %d/%d residents", title, sent, len(contacts))
+}
+
+// Run drains the job alert and news notification queues until ctx is cancelled
+func (s *Service) Run(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	if !s.hasChannel() {
+		// Claiming would fail every queued alert for good; leave them pending until a
+		// channel is configured
+		log.Println("Warning: Notify: no email, SMS or push channel enabled; notifications stay queued")
+	} else {
+		log.Printf("Notify: notification dispatcher started (every %s)", s.PollInterval)
+	}
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatchJobAlerts(ctx)
+		s.dispatchNews(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("Notify: notification dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasChannel reports whether any delivery channel is enabled
+func (s *Service) hasChannel() bool {
+	return s.Email != nil || s.SMS != nil || s.Push != nil
+}
+
+// dispatchJobAlerts sends one batch of queued job alert notifications. Nothing is claimed
+// while no channel is enabled.
+func (s *Service) dispatchJobAlerts(ctx context.Context) {
+	if !s.hasChannel() {
+		return
+	}
+	batch, err := s.Alerts.ClaimPendingNotifications(ctx, s.BatchSize)
+	if err != nil || len(batch) == 0 {
+		return
+	}
+
+	for _, n := range batch {
+		err := s.deliverJobAlert(ctx, n)
+		if err != nil {
+			log.Printf("Warning: Notify: job alert %s failed: %v", n.ID, err)
+			if markErr := s.Alerts.MarkNotificationFailed(ctx, n.ID, err.Error()); markErr != nil {
+				log.Printf("Warning: Notify: could not mark job alert %s failed: %v", n.ID, markErr)
+			}
+			continue
+		}
+		if err := s.Alerts.MarkNotificationSent(ctx, n.ID); err != nil {
+			log.Printf("Warning: Notify: could not mark job alert %s sent: %v", n.ID, err)
+		}
+	}
+}
+
+func (s *Service) deliverJobAlert(ctx context.Context, n models.JobAlertNotification) error {
+	job, err := s.Jobs.GetJobByID(ctx, n.JobID)
+	if err != nil {
+		return err
+	}
+	to, err := s.recipientFor(ctx, n.UserID)
+	if err != nil {
+		return err
+	}
+
+	// The alert counts as delivered if either push or a message channel got through
+	var pushErr error = errNoChannel
+	if s.Push != nil {
+		pushErr = s.Push.SendToUser(ctx, n.UserID, PushPayload{
+			Event: EventJobAlertMatch,
+			Title: "New job: " + job.Title,
+			Body:  pushExcerpt(&job.Description),
+			URL:   s.link("/jobs/" + job.ID),
+			Tag:   "job-" + job.ID,
+		})
+	}
+	notifyErr := s.Notify(ctx, EventJobAlertMatch, to, job)
+	if pushErr == nil || notifyErr == nil {
+		return nil
+	}
+	if errors.Is(notifyErr, errNoChannel) {
+		return pushErr
+	}
+	return notifyErr
+}
+
+// dispatchNews sends one batch of queued news notifications
+func (s *Service) dispatchNews(ctx context.Context) {
+	if !s.hasChannel() {
+		return
+	}
+	batch, err := s.News.ClaimPending(ctx, s.BatchSize)
+	if err != nil || len(batch) == 0 {
+		return
+	}
+
+	for _, n := range batch {
+		err := s.deliverNews(ctx, n)
+		if err != nil {
+			log.Printf("Warning: Notify: news notification %s failed: %v", n.ID, err)
+			if markErr := s.News.MarkFailed(ctx, n.ID, err.Error()); markErr != nil {
+				log.Printf("Warning: Notify: could not mark news notification %s failed: %v", n.ID, markErr)
+			}
+			continue
+		}
+		if err := s.News.MarkSent(ctx, n.ID); err != nil {
+			log.Printf("Warning: Notify: could not mark news notification %s sent: %v", n.ID, err)
+		}
+	}
+}
+
+func (s *Service) deliverNews(ctx context.Context, n models.NewsNotification) error {
+	if n.SubscriptionID != nil {
+		if s.Push == nil {
+			return errNoChannel
+		}
+		sub, err := s.Push.Repo.GetSubscription(ctx, *n.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		return s.Push.SendToSubscription(ctx, sub, PushPayload{
+			Event: EventNewsPublished,
+			Title: n.News.Title,
+			Body:  pushExcerpt(n.News.Content),
+			URL:   s.link("/news/" + n.News.ID),
+			Tag:   "news-" + n.News.ID,
+		})
+	}
+
+	if s.Email == nil || n.UserID == nil {
+		return errNoChannel
+	}
+	to, err := s.recipientFor(ctx, *n.UserID)
+	if err != nil {
+		return err
+	}
+	to.Phone = "" // News goes out by email only; SMS is kept for alerts and emergencies
+	return s.Notify(ctx, EventNewsPublished, to, n.News)
+}
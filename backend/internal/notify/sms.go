@@ -0,0 +1,284 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"village_project/internal/config"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+)
+
+// SMS provider names (SMS_PROVIDER)
+const (
+	SMSProviderHTTP     = "http"
+	SMSProviderFake     = "fake"
+	SMSProviderDisabled = "disabled"
+)
+
+// SMSRequest is what a provider needs to submit one message
+type SMSRequest struct {
+	To            string // E.164
+	Body          string
+	Unicode       bool   // true when the body needs UCS-2
+	DLTTemplateID string // Registered DLT content template (required by Indian operators)
+	Reference     string // Our sms_messages.id, echoed back in callbacks where supported
+}
+
+// SMSProvider submits messages to an SMS gateway
+type SMSProvider interface {
+	Name() string
+	Send(ctx context.Context, req SMSRequest) (providerMessageID string, err error)
+}
+
+// HTTPSMSProvider talks to a JSON-over-HTTP gateway in the style of MSG91 or Twilio.
+// The request body carries the sender ID, DLT entity/template IDs, recipient and text;
+// the provider's message ID is read from the first of sid, message_id or request_id. Some
+// gateways (MSG91) report errors with HTTP 200 and "type" or "status" set to "error".
+type HTTPSMSProvider struct {
+	URL         string
+	AuthHeader  string // e.g. "authkey" for MSG91, "Authorization" for Basic/Bearer schemes
+	APIKey      string
+	SenderID    string
+	DLTEntityID string
+	Client      *http.Client
+}
+
+// Name identifies the provider in stored messages and callback URLs
+func (p *HTTPSMSProvider) Name() string { return SMSProviderHTTP }
+
+// Send submits one message
+func (p *HTTPSMSProvider) Send(ctx context.Context, req SMSRequest) (string, error) {
+	unicode := 0
+	if req.Unicode {
+		unicode = 1
+	}
+	payload, err := json.Marshal(map[string]any{
+		"sender":        p.SenderID,
+		"to":            req.To,
+		"mobiles":       strings.TrimPrefix(req.To, "+"),
+		"message":       req.Body,
+		"unicode":       unicode,
+		"DLT_TE_ID":     req.DLTTemplateID,
+		"dlt_entity_id": p.DLTEntityID,
+		"reference":     req.Reference,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(p.AuthHeader, p.APIKey)
+
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 15 * time.Second}
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return "", fmt.Errorf("SMS gateway returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%w: SMS gateway returned %d: %s", ErrPermanent, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal(body, &parsed); err == nil {
+		for _, key := range []string{"type", "status"} {
+			if v, _ := parsed[key].(string); strings.EqualFold(v, "error") || strings.EqualFold(v, "failed") {
+				return "", fmt.Errorf("%w: SMS gateway rejected the message: %s", ErrPermanent, strings.TrimSpace(string(body)))
+			}
+		}
+		for _, key := range []string{"sid", "message_id", "request_id"} {
+			if v, ok := parsed[key].(string); ok && v != "" {
+				return v, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// FakeSMSProvider records messages in memory instead of sending them.
+// Use SMS_PROVIDER=fake in development; tests set FailWith to simulate gateway errors.
+type FakeSMSProvider struct {
+	mu       sync.Mutex
+	Sent     []SMSRequest
+	FailWith error
+}
+
+// Name identifies the provider in stored messages and callback URLs
+func (p *FakeSMSProvider) Name() string { return SMSProviderFake }
+
+// Send records the request and returns a synthetic message ID
+func (p *FakeSMSProvider) Send(ctx context.Context, req SMSRequest) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.FailWith != nil {
+		return "", p.FailWith
+	}
+	p.Sent = append(p.Sent, req)
+	log.Printf("FakeSMS: to=%s template=%s unicode=%v body=%q", req.To, req.DLTTemplateID, req.Unicode, req.Body)
+	return fmt.Sprintf("fake-%d", len(p.Sent)), nil
+}
+
+// Messages returns a copy of everything sent so far
+func (p *FakeSMSProvider) Messages() []SMSRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]SMSRequest(nil), p.Sent...)
+}
+
+// SMSChannel enforces DLT templates and the per-recipient daily cap, logs every
+// message in sms_messages and submits it through the provider with retries.
+type SMSChannel struct {
+	Provider     SMSProvider
+	Repo         *repository.SMSRepository
+	DailyCap     int               // Messages per recipient per IST day; 0 disables the cap
+	DLTTemplates map[string]string // Event -> DLT template ID
+	Retry        RetryPolicy
+}
+
+// ErrDailyCapReached means the recipient has had their quota of SMS for today
+var ErrDailyCapReached = fmt.Errorf("%w: daily SMS cap reached for recipient", ErrPermanent)
+
+var istLocation = time.FixedZone("IST", 5*60*60+30*60)
+
+// NewSMSChannel builds the SMS channel selected by cfg.SMSProvider. It returns nil when SMS is disabled.
+func NewSMSChannel(cfg config.Config, repo *repository.SMSRepository) (*SMSChannel, error) {
+	var provider SMSProvider
+	switch cfg.SMSProvider {
+	case SMSProviderHTTP:
+		if cfg.SMSProviderURL == "" || cfg.SMSAPIKey == "" {
+			return nil, fmt.Errorf("SMS_PROVIDER=http requires SMS_PROVIDER_URL and SMS_API_KEY")
+		}
+		provider = &HTTPSMSProvider{
+			URL:         cfg.SMSProviderURL,
+			AuthHeader:  cfg.SMSAuthHeader,
+			APIKey:      cfg.SMSAPIKey,
+			SenderID:    cfg.SMSSenderID,
+			DLTEntityID: cfg.SMSDLTEntityID,
+		}
+	case SMSProviderFake:
+		provider = &FakeSMSProvider{}
+	case SMSProviderDisabled, "":
+		log.Println("SMS channel: disabled")
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown SMS_PROVIDER %q (want http, fake or disabled)", cfg.SMSProvider)
+	}
+
+	templates, err := parseDLTTemplates(cfg.SMSDLTTemplates)
+	if err != nil {
+		return nil, err
+	}
+	retry := DefaultRetryPolicy
+	if cfg.NotifyMaxAttempts > 0 {
+		retry.MaxAttempts = cfg.NotifyMaxAttempts
+	}
+	log.Printf("SMS channel: provider=%s daily cap=%d DLT templates=%d", provider.Name(), cfg.SMSDailyCap, len(templates))
+	return &SMSChannel{Provider: provider, Repo: repo, DailyCap: cfg.SMSDailyCap, DLTTemplates: templates, Retry: retry}, nil
+}
+
+// parseDLTTemplates reads "event=templateID,event=templateID"
+func parseDLTTemplates(raw string) (map[string]string, error) {
+	templates := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		event, id, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(id) == "" {
+			return nil, fmt.Errorf("invalid SMS_DLT_TEMPLATES entry %q (want event=templateID)", pair)
+		}
+		templates[strings.TrimSpace(event)] = strings.TrimSpace(id)
+	}
+	return templates, nil
+}
+
+// Send delivers msg.SMSBody to msg.To.Phone. Priority messages (emergency notices)
+// are not counted against the daily cap.
+func (s *SMSChannel) Send(ctx context.Context, msg Message, priority bool) error {
+	to := NormalizeIndianPhone(msg.To.Phone)
+	if to == "" {
+		return fmt.Errorf("%w: invalid mobile number %q", ErrPermanent, msg.To.Phone)
+	}
+	if msg.SMSBody == "" {
+		return fmt.Errorf("%w: no SMS template for event %s", ErrPermanent, msg.Event)
+	}
+
+	templateID := s.DLTTemplates[msg.Event]
+	if templateID == "" && s.Provider.Name() == SMSProviderHTTP {
+		// Indian operators drop messages that do not match a registered DLT template
+		return fmt.Errorf("%w: no DLT template ID configured for event %s", ErrPermanent, msg.Event)
+	}
+
+	seg := CountSegments(msg.SMSBody)
+	record := models.SMSMessage{
+		Recipient: to,
+		Event:     msg.Event,
+		Body:      msg.SMSBody,
+		Encoding:  seg.Encoding,
+		Segments:  seg.Segments,
+		Provider:  s.Provider.Name(),
+		Status:    models.SMSStatusQueued,
+	}
+	if templateID != "" {
+		record.DLTTemplateID = &templateID
+	}
+
+	var id string
+	var err error
+	if s.DailyCap > 0 && !priority {
+		now := time.Now().In(istLocation)
+		startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, istLocation)
+		var capped bool
+		id, capped, err = s.Repo.CreateMessageWithinCap(ctx, record, startOfDay, s.DailyCap)
+		if err != nil {
+			return err
+		}
+		if capped {
+			return ErrDailyCapReached
+		}
+	} else if id, err = s.Repo.CreateMessage(ctx, record); err != nil {
+		return err
+	}
+
+	var providerID string
+	err = Retry(ctx, s.Retry, "SMS to "+to, func(ctx context.Context) error {
+		var sendErr error
+		providerID, sendErr = s.Provider.Send(ctx, SMSRequest{
+			To:            to,
+			Body:          msg.SMSBody,
+			Unicode:       seg.Encoding == EncodingUCS2,
+			DLTTemplateID: templateID,
+			Reference:     id,
+		})
+		return sendErr
+	})
+	if err != nil {
+		s.Repo.MarkFailed(ctx, id, err.Error())
+		return err
+	}
+	if providerID == "" {
+		providerID = id // Gateways without their own IDs report status against our reference
+	}
+	return s.Repo.MarkSubmitted(ctx, id, providerID)
+}
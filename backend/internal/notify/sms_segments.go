@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// SMS encodings
+const (
+	EncodingGSM7 = "GSM-7"
+	EncodingUCS2 = "UCS-2"
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet; each character costs one septet
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extended characters need an escape septet, so each costs two
+const gsm7Extended = "^{}\\[~]|€\f"
+
+// SegmentInfo describes how a message body will be split by the operator
+type SegmentInfo struct {
+	Encoding string
+	Units    int // Septets for GSM-7, UTF-16 code units for UCS-2
+	Segments int
+}
+
+// CountSegments works out the encoding and number of SMS segments for body.
+// Any character outside GSM-7 (e.g. Telugu script) forces UCS-2 for the whole
+// message: 70 units in a single segment, 67 per segment once concatenated
+// (153 and 160 for GSM-7).
+func CountSegments(body string) SegmentInfo {
+	septets := 0
+	gsm := true
+	for _, r := range body {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			septets++
+		case strings.ContainsRune(gsm7Extended, r):
+			septets += 2
+		default:
+			gsm = false
+		}
+		if !gsm {
+			break
+		}
+	}
+
+	if gsm {
+		return SegmentInfo{Encoding: EncodingGSM7, Units: septets, Segments: segmentsFor(septets, 160, 153)}
+	}
+	units := len(utf16.Encode([]rune(body)))
+	return SegmentInfo{Encoding: EncodingUCS2, Units: units, Segments: segmentsFor(units, 70, 67)}
+}
+
+func segmentsFor(units, single, multi int) int {
+	if units == 0 {
+		return 1
+	}
+	if units <= single {
+		return 1
+	}
+	return (units + multi - 1) / multi
+}
+
+// NormalizeIndianPhone converts common Indian mobile formats ("98765 43210",
+// "098765-43210", "+91 98765 43210") to E.164. It returns "" if the number
+// is not a valid 10-digit Indian mobile number.
+func NormalizeIndianPhone(phone string) string {
+	var digits strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	switch {
+	case len(d) == 12 && strings.HasPrefix(d, "91"):
+		d = d[2:]
+	case len(d) == 11 && strings.HasPrefix(d, "0"):
+		d = d[1:]
+	}
+	if len(d) != 10 || d[0] < '6' {
+		return ""
+	}
+	return "+91" + d
+}
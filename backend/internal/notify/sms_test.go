@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPSMSProviderResponses(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    int
+		body      string
+		wantID    string
+		wantErr   bool
+		permanent bool
+	}{
+		{"request id", http.StatusOK, `{"type":"success","request_id":"abc123"}`, "abc123", false, false},
+		{"twilio sid", http.StatusCreated, `{"sid":"SM42","status":"queued"}`, "SM42", false, false},
+		{"message is not an id", http.StatusOK, `{"type":"success","message":"3763646c"}`, "", false, false},
+		{"msg91 error with 200", http.StatusOK, `{"type":"error","message":"Invalid authkey"}`, "", true, true},
+		{"failed status", http.StatusOK, `{"status":"failed","message":"DLT template mismatch"}`, "", true, true},
+		{"bad request", http.StatusBadRequest, `{"message":"bad number"}`, "", true, true},
+		{"gateway down", http.StatusBadGateway, `oops`, "", true, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("authkey") != "key" {
+					t.Errorf("auth header not sent")
+				}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer srv.Close()
+
+			p := &HTTPSMSProvider{URL: srv.URL, AuthHeader: "authkey", APIKey: "key"}
+			id, err := p.Send(context.Background(), SMSRequest{To: "+919876543210", Body: "hi"})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && errors.Is(err, ErrPermanent) != tc.permanent {
+				t.Errorf("permanent = %v, want %v (%v)", errors.Is(err, ErrPermanent), tc.permanent, err)
+			}
+			if id != tc.wantID {
+				t.Errorf("id = %q, want %q", id, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestRetryWithFakeSMSProvider(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	send := func(p *FakeSMSProvider, attempts *int) error {
+		return Retry(context.Background(), policy, "test SMS", func(ctx context.Context) error {
+			*attempts++
+			_, err := p.Send(ctx, SMSRequest{To: "+919876543210", Body: "hi"})
+			return err
+		})
+	}
+
+	var attempts int
+	transient := &FakeSMSProvider{FailWith: errors.New("timeout")}
+	if err := send(transient, &attempts); err == nil || attempts != 3 {
+		t.Errorf("transient failure: err = %v after %d attempts, want an error after 3", err, attempts)
+	}
+
+	attempts = 0
+	permanent := &FakeSMSProvider{FailWith: fmt.Errorf("%w: invalid number", ErrPermanent)}
+	if err := send(permanent, &attempts); !errors.Is(err, ErrPermanent) || attempts != 1 {
+		t.Errorf("permanent failure: err = %v after %d attempts, want ErrPermanent after 1", err, attempts)
+	}
+
+	attempts = 0
+	ok := &FakeSMSProvider{}
+	if err := send(ok, &attempts); err != nil || attempts != 1 || len(ok.Messages()) != 1 {
+		t.Errorf("success: err = %v, attempts %d, %d messages", err, attempts, len(ok.Messages()))
+	}
+}
@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// Renderer turns an event and its data into a per-recipient message.
+// Each event has <event>.txt.tmpl (defining "subject" and "body") and
+// <event>.html.tmpl (defining "body"); the HTML body is wrapped in layout.html.tmpl.
+// An optional <event>.sms.tmpl holds the short text used for SMS.
+type Renderer struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+	sms  map[string]*texttemplate.Template
+}
+
+// NewRenderer parses the embedded templates for every known event
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{
+		text: map[string]*texttemplate.Template{},
+		html: map[string]*htmltemplate.Template{},
+		sms:  map[string]*texttemplate.Template{},
+	}
+	for _, event := range allEvents {
+		t, err := texttemplate.ParseFS(templateFS, "templates/"+event+".txt.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("parsing text template for %s: %w", event, err)
+		}
+		r.text[event] = t
+
+		h, err := htmltemplate.ParseFS(templateFS, "templates/layout.html.tmpl", "templates/"+event+".html.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("parsing HTML template for %s: %w", event, err)
+		}
+		r.html[event] = h
+
+		smsFile := "templates/" + event + ".sms.tmpl"
+		if _, err := fs.Stat(templateFS, smsFile); err == nil {
+			st, err := texttemplate.ParseFS(templateFS, smsFile)
+			if err != nil {
+				return nil, fmt.Errorf("parsing SMS template for %s: %w", event, err)
+			}
+			r.sms[event] = st
+		}
+	}
+	return r, nil
+}
+
+// TemplateData is passed to every template. Data holds the event-specific payload.
+type TemplateData struct {
+	Recipient Recipient
+	AppURL    string
+	Data      any
+}
+
+// Render produces the subject, plain-text and HTML bodies for one recipient
+func (r *Renderer) Render(event string, to Recipient, appURL string, data any) (Message, error) {
+	textTmpl, ok := r.text[event]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: no templates for event %q", ErrPermanent, event)
+	}
+	td := TemplateData{Recipient: to, AppURL: strings.TrimRight(appURL, "/"), Data: data}
+
+	var subject, text, html, sms bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", td); err != nil {
+		return Message{}, fmt.Errorf("%w: rendering subject: %v", ErrPermanent, err)
+	}
+	if err := textTmpl.ExecuteTemplate(&text, "body", td); err != nil {
+		return Message{}, fmt.Errorf("%w: rendering text body: %v", ErrPermanent, err)
+	}
+	if err := r.html[event].ExecuteTemplate(&html, "layout", td); err != nil {
+		return Message{}, fmt.Errorf("%w: rendering HTML body: %v", ErrPermanent, err)
+	}
+
+	if smsTmpl, ok := r.sms[event]; ok {
+		if err := smsTmpl.Execute(&sms, td); err != nil {
+			return Message{}, fmt.Errorf("%w: rendering SMS body: %v", ErrPermanent, err)
+		}
+	}
+
+	return Message{
+		Event:    event,
+		To:       to,
+		Subject:  strings.TrimSpace(subject.String()),
+		TextBody: strings.TrimSpace(text.String()) + "\n",
+		HTMLBody: html.String(),
+		SMSBody:  strings.Join(strings.Fields(sms.String()), " "),
+	}, nil
+}
@@ -0,0 +1,389 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"village_project/internal/config"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+)
+
+// PushPayload is the JSON the service worker receives in its push event
+type PushPayload struct {
+	Event string `json:"event"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+	Tag   string `json:"tag,omitempty"` // Lets the browser replace an older notification for the same item
+}
+
+// VAPIDKeys identify this server to push services (RFC 8292)
+type VAPIDKeys struct {
+	PublicKey  string // base64url, uncompressed P-256 point; handed to PushManager.subscribe
+	privateKey *ecdsa.PrivateKey
+}
+
+// GenerateVAPIDKeys creates a new key pair encoded as base64url (public point, private scalar)
+func GenerateVAPIDKeys() (publicKey, privateKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return b64(key.PublicKey().Bytes()), b64(key.Bytes()), nil
+}
+
+// ParseVAPIDKeys decodes VAPID_PUBLIC_KEY and VAPID_PRIVATE_KEY and checks that they belong together
+func ParseVAPIDKeys(publicKey, privateKey string) (*VAPIDKeys, error) {
+	raw, err := unb64(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID_PRIVATE_KEY is not base64url: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("VAPID_PRIVATE_KEY is not a P-256 key: %w", err)
+	}
+	pub := key.PublicKey().Bytes()
+	if b64(pub) != strings.TrimRight(publicKey, "=") {
+		return nil, errors.New("VAPID_PUBLIC_KEY does not match VAPID_PRIVATE_KEY")
+	}
+
+	signer := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:65]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+	return &VAPIDKeys{PublicKey: b64(pub), privateKey: signer}, nil
+}
+
+// authorization builds the "vapid t=..., k=..." header for a push endpoint
+func (k *VAPIDKeys) authorization(endpoint, subject string, ttl time.Duration) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	header := b64([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(ttl).Unix(),
+		"sub": subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + b64(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, k.privateKey, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64) // JWS wants fixed-width r || s, not ASN.1
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return "vapid t=" + signingInput + "." + b64(sig) + ", k=" + k.PublicKey, nil
+}
+
+// pushRecordSize is the aes128gcm record size; the whole payload is sent as one record
+const pushRecordSize = 4096
+
+// maxPushPlaintext leaves room for the 16-byte tag and padding delimiter in one record
+// and keeps the body under the 4096 bytes push services are required to accept.
+const maxPushPlaintext = pushRecordSize - 16 - 1 - (16 + 4 + 1 + 65)
+
+// encryptPushPayload encrypts plaintext for a subscription as specified by RFC 8291,
+// using the aes128gcm content coding from RFC 8188.
+func encryptPushPayload(p256dh, authSecret string, plaintext []byte) ([]byte, error) {
+	if len(plaintext) > maxPushPlaintext {
+		return nil, fmt.Errorf("%w: push payload is %d bytes, limit is %d", ErrPermanent, len(plaintext), maxPushPlaintext)
+	}
+	uaPublicRaw, err := unb64(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad p256dh key: %v", ErrPermanent, err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicRaw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad p256dh key: %v", ErrPermanent, err)
+	}
+	auth, err := unb64(authSecret)
+	if err != nil || len(auth) != 16 {
+		return nil, fmt.Errorf("%w: bad auth secret", ErrPermanent)
+	}
+
+	// A fresh application-server key pair and salt for every message
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encryptPushRecord(uaPublic, auth, asPrivate, salt, plaintext)
+}
+
+// encryptPushRecord does the key derivation and encryption with caller-supplied
+// randomness, so the RFC 8291 appendix A vector can be reproduced.
+func encryptPushRecord(uaPublic *ecdh.PublicKey, auth []byte, asPrivate *ecdh.PrivateKey, salt, plaintext []byte) ([]byte, error) {
+	uaPublicRaw := uaPublic.Bytes()
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public, 32)
+	keyInfo := "WebPush: info\x00" + string(uaPublicRaw) + string(asPublic)
+	prkKey, err := hkdf.Extract(sha256.New, ecdhSecret, auth)
+	if err != nil {
+		return nil, err
+	}
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	// CEK and nonce as in RFC 8188 section 2.2 and 2.3
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Header: salt (16) || rs (uint32) || idlen (1) || keyid (as_public)
+	var body bytes.Buffer
+	body.Write(salt)
+	binary.Write(&body, binary.BigEndian, uint32(pushRecordSize))
+	body.WriteByte(byte(len(asPublic)))
+	body.Write(asPublic)
+
+	record := append(append([]byte(nil), plaintext...), 0x02) // 0x02 marks the last (only) record
+	body.Write(gcm.Seal(nil, nonce, record, nil))
+	return body.Bytes(), nil
+}
+
+// errSubscriptionGone means the push service no longer knows the subscription (404/410)
+var errSubscriptionGone = fmt.Errorf("%w: push subscription expired or unsubscribed", ErrPermanent)
+
+// PushChannel sends encrypted Web Push messages and prunes subscriptions that have gone away
+type PushChannel struct {
+	Keys     *VAPIDKeys
+	Subject  string // mailto: or https: contact for push service operators
+	TTL      time.Duration
+	Repo     *repository.PushRepository
+	Client   *http.Client
+	Retry    RetryPolicy
+	Parallel int // Concurrent sends during a broadcast
+}
+
+// NewPushChannel builds the Web Push channel. It returns nil when no VAPID keys are configured.
+func NewPushChannel(cfg config.Config, repo *repository.PushRepository) (*PushChannel, error) {
+	if cfg.VAPIDPublicKey == "" && cfg.VAPIDPrivateKey == "" {
+		log.Println("Web Push channel: disabled (no VAPID keys; run `go run ./cmd/vapidkeys` to create them)")
+		return nil, nil
+	}
+	keys, err := ParseVAPIDKeys(cfg.VAPIDPublicKey, cfg.VAPIDPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(cfg.VAPIDSubject, "mailto:") && !strings.HasPrefix(cfg.VAPIDSubject, "https://") {
+		return nil, fmt.Errorf("VAPID_SUBJECT must be a mailto: or https: URL, got %q", cfg.VAPIDSubject)
+	}
+
+	retry := DefaultRetryPolicy
+	if cfg.NotifyMaxAttempts > 0 {
+		retry.MaxAttempts = cfg.NotifyMaxAttempts
+	}
+	ttl := time.Duration(cfg.PushTTLSeconds) * time.Second
+	log.Printf("Web Push channel: enabled (TTL %s)", ttl)
+	return &PushChannel{
+		Keys:     keys,
+		Subject:  cfg.VAPIDSubject,
+		TTL:      ttl,
+		Repo:     repo,
+		Client:   &http.Client{Timeout: 15 * time.Second},
+		Retry:    retry,
+		Parallel: 8,
+	}, nil
+}
+
+// SendToUser pushes to every subscription of one user. It returns an error only if none succeeded.
+func (p *PushChannel) SendToUser(ctx context.Context, userID string, payload PushPayload) error {
+	subs, err := p.Repo.ListForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return errNoChannel
+	}
+	sent, _ := p.sendAll(ctx, subs, payload)
+	if sent == 0 {
+		return fmt.Errorf("push to user %s failed on all %d subscriptions", userID, len(subs))
+	}
+	return nil
+}
+
+// SendToSubscription pushes to one subscription, pruning it if the push service reports it gone
+func (p *PushChannel) SendToSubscription(ctx context.Context, sub models.PushSubscription, payload PushPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: encoding %s payload: %v", ErrPermanent, payload.Event, err)
+	}
+	return p.deliver(ctx, sub, body)
+}
+
+// sendAll delivers payload to subs with bounded concurrency and prunes gone subscriptions
+func (p *PushChannel) sendAll(ctx context.Context, subs []models.PushSubscription, payload PushPayload) (sent, pruned int) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("ERROR: Push: cannot encode %s payload: %v", payload.Event, err)
+		return 0, 0
+	}
+
+	var sentCount, prunedCount atomic.Int64
+	sem := make(chan struct{}, max(p.Parallel, 1))
+	var wg sync.WaitGroup
+	for _, sub := range subs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(sub models.PushSubscription) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			switch err := p.deliver(ctx, sub, body); {
+			case err == nil:
+				sentCount.Add(1)
+			case errors.Is(err, errSubscriptionGone):
+				prunedCount.Add(1)
+			default:
+				log.Printf("Warning: Push: subscription %s failed: %v", sub.ID, err)
+			}
+		}(sub)
+	}
+	wg.Wait()
+	return int(sentCount.Load()), int(prunedCount.Load())
+}
+
+// deliver sends body to one subscription with retries and records the outcome.
+// A subscription the push service reports gone is deleted and errSubscriptionGone returned.
+func (p *PushChannel) deliver(ctx context.Context, sub models.PushSubscription, body []byte) error {
+	err := Retry(ctx, p.Retry, "push to "+sub.ID, func(ctx context.Context) error {
+		return p.send(ctx, sub, body)
+	})
+	switch {
+	case err == nil:
+		p.Repo.RecordResult(ctx, sub.ID, true)
+	case errors.Is(err, errSubscriptionGone):
+		if err := p.Repo.DeleteSubscription(ctx, sub.ID); err != nil {
+			log.Printf("Warning: Push: could not prune subscription %s: %v", sub.ID, err)
+		}
+	default:
+		p.Repo.RecordResult(ctx, sub.ID, false)
+	}
+	return err
+}
+
+// send makes one delivery attempt to the subscription's push service
+func (p *PushChannel) send(ctx context.Context, sub models.PushSubscription, plaintext []byte) error {
+	body, err := encryptPushPayload(sub.P256dh, sub.Auth, plaintext)
+	if err != nil {
+		return err
+	}
+	authorization, err := p.Keys.authorization(sub.Endpoint, p.Subject, 12*time.Hour)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(p.TTL.Seconds())))
+	req.Header.Set("Urgency", "normal")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 4*1024))
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return errSubscriptionGone
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("push service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	default:
+		return fmt.Errorf("%w: push service returned %d: %s", ErrPermanent, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+}
+
+// ValidateSubscriptionKeys checks browser keys before a subscription is stored
+func ValidateSubscriptionKeys(p256dh, authSecret string) error {
+	raw, err := unb64(p256dh)
+	if err != nil {
+		return errors.New("keys.p256dh is not base64url")
+	}
+	if _, err := ecdh.P256().NewPublicKey(raw); err != nil {
+		return errors.New("keys.p256dh is not an uncompressed P-256 public key")
+	}
+	auth, err := unb64(authSecret)
+	if err != nil || len(auth) != 16 {
+		return errors.New("keys.auth must be 16 bytes of base64url")
+	}
+	return nil
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+// unb64 accepts base64url with or without padding, as browsers and key tools differ
+func unb64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(s), "="))
+}
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEncryptPushRecordRFC8291 reproduces the example in RFC 8291 appendix A
+func TestEncryptPushRecordRFC8291(t *testing.T) {
+	mustDecode := func(s string) []byte {
+		b, err := unb64(s)
+		if err != nil {
+			t.Fatalf("decoding %q: %v", s, err)
+		}
+		return b
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(mustDecode("BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	asPrivate, err := ecdh.P256().NewPrivateKey(mustDecode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := mustDecode("BTBZMqHH6r4Tts7J_aSIgg")
+	salt := mustDecode("DGv6ra1nlYgDCS1FRnbzlw")
+
+	got, err := encryptPushRecord(uaPublic, auth, asPrivate, salt, []byte("When I grow up, I want to be a watermelon"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if b64(got) != want {
+		t.Errorf("encrypted record\n got %s\nwant %s", b64(got), want)
+	}
+}
+
+func TestVAPIDAuthorization(t *testing.T) {
+	public, private, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseVAPIDKeys(public, private); err != nil {
+		t.Fatalf("generated keys do not parse: %v", err)
+	}
+	otherPublic, _, _ := GenerateVAPIDKeys()
+	if _, err := ParseVAPIDKeys(otherPublic, private); err == nil {
+		t.Error("mismatched public key accepted")
+	}
+
+	keys, _ := ParseVAPIDKeys(public, private)
+	header, err := keys.authorization("https://push.example.net/send/abc?x=1", "mailto:admin@example.org", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, k, ok := strings.Cut(strings.TrimPrefix(header, "vapid t="), ", k=")
+	if !ok || k != public {
+		t.Fatalf("unexpected header %q", header)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts", len(parts))
+	}
+	var claims struct {
+		Aud string `json:"aud"`
+		Sub string `json:"sub"`
+		Exp int64  `json:"exp"`
+	}
+	payload, _ := unb64(parts[1])
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims.Aud != "https://push.example.net" || claims.Sub != "mailto:admin@example.org" || claims.Exp <= time.Now().Unix() {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	sig, _ := unb64(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(&keys.privateKey.PublicKey, digest[:], r, s) {
+		t.Error("signature does not verify")
+	}
+}
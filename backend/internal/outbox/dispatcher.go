@@ -0,0 +1,168 @@
+// Package outbox delivers domain events from the transactional outbox to in-process handlers.
+//
+// Repositories write events with the same transaction as the change that caused them.
+// The Dispatcher leases due events with FOR UPDATE SKIP LOCKED, runs every handler
+// registered for the event type and settles the event. Delivery is at least once:
+// a crash after a handler ran but before the event was settled runs it again, so
+// handlers must be idempotent (the event ID is stable across retries). Handlers that
+// already succeeded are remembered and skipped when a sibling handler's failure
+// causes a retry. Events that keep failing are dead-lettered for an admin to requeue.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"slices"
+	"time"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+)
+
+// Handler reacts to one event. Returning an error schedules a retry.
+type Handler func(ctx context.Context, event models.OutboxEvent) error
+
+type namedHandler struct {
+	name string
+	fn   Handler
+}
+
+// Dispatcher polls the outbox and fans events out to registered handlers
+type Dispatcher struct {
+	Repo         *repository.OutboxRepository
+	MaxAttempts  int
+	BaseDelay    time.Duration // Delay after the first failure; doubled each time, with jitter
+	MaxDelay     time.Duration
+	Lease        time.Duration // How long a claimed event is hidden from other dispatchers
+	PollInterval time.Duration
+	BatchSize    int
+
+	handlers map[string][]namedHandler
+}
+
+// NewDispatcher creates a Dispatcher with default timings
+func NewDispatcher(repo *repository.OutboxRepository, maxAttempts int) *Dispatcher {
+	return &Dispatcher{
+		Repo:         repo,
+		MaxAttempts:  maxAttempts,
+		BaseDelay:    10 * time.Second,
+		MaxDelay:     30 * time.Minute,
+		Lease:        15 * time.Minute,
+		PollInterval: 2 * time.Second,
+		BatchSize:    50,
+		handlers:     map[string][]namedHandler{},
+	}
+}
+
+// Register adds a handler for an event type. The name identifies the handler in the
+// completed_handlers bookkeeping, so it must be unique per event type and stable across releases.
+func (d *Dispatcher) Register(eventType, name string, fn Handler) {
+	for _, h := range d.handlers[eventType] {
+		if h.name == name {
+			panic(fmt.Sprintf("outbox: handler %q registered twice for %s", name, eventType))
+		}
+	}
+	d.handlers[eventType] = append(d.handlers[eventType], namedHandler{name: name, fn: fn})
+}
+
+// Run processes the outbox until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	log.Printf("Outbox: dispatcher started (every %s, max %d attempts)", d.PollInterval, d.MaxAttempts)
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back
+		for d.processBatch(ctx) == d.BatchSize && ctx.Err() == nil {
+		}
+		select {
+		case <-ctx.Done():
+			log.Println("Outbox: dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch handles one batch of due events and returns how many were claimed.
+// The whole batch shares one lease, so handlers run under a deadline at its end; events
+// not started by then are left for another claim once the lease runs out.
+func (d *Dispatcher) processBatch(ctx context.Context) int {
+	// Taken before the claim, so it falls no later than the lease the database records
+	leaseCtx, cancel := context.WithDeadline(ctx, time.Now().Add(d.Lease))
+	defer cancel()
+
+	batch, err := d.Repo.ClaimDue(ctx, d.BatchSize, d.Lease)
+	if err != nil || len(batch) == 0 {
+		return 0
+	}
+	for i, event := range batch {
+		if leaseCtx.Err() != nil {
+			if ctx.Err() == nil {
+				log.Printf("Warning: Outbox: lease ran out with %d event(s) of the batch unprocessed", len(batch)-i)
+			}
+			break
+		}
+		d.process(ctx, leaseCtx, event)
+	}
+	return len(batch)
+}
+
+// process runs the outstanding handlers for one event under handlerCtx and settles it with ctx
+func (d *Dispatcher) process(ctx, handlerCtx context.Context, event models.OutboxEvent) {
+	completed := append([]string(nil), event.CompletedHandlers...)
+	var errs []error
+	for _, h := range d.handlers[event.EventType] {
+		if slices.Contains(completed, h.name) {
+			continue
+		}
+		if err := runHandler(handlerCtx, h, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			continue
+		}
+		completed = append(completed, h.name)
+	}
+
+	if len(errs) == 0 {
+		if err := d.Repo.MarkDone(ctx, event.ID, completed); err != nil {
+			log.Printf("Warning: Outbox: could not mark event %d done: %v", event.ID, err)
+		}
+		return
+	}
+
+	reason := errors.Join(errs...).Error()
+	attempts := event.Attempts + 1
+	var retryAt *time.Time
+	if attempts < d.MaxAttempts {
+		next := time.Now().Add(d.backoff(attempts))
+		retryAt = &next
+		log.Printf("Warning: Outbox: event %d (%s) failed on attempt %d/%d: %s", event.ID, event.EventType, attempts, d.MaxAttempts, reason)
+	} else {
+		log.Printf("ERROR: Outbox: event %d (%s) dead-lettered after %d attempts: %s", event.ID, event.EventType, attempts, reason)
+	}
+	if err := d.Repo.MarkFailed(ctx, event.ID, completed, reason, retryAt); err != nil {
+		log.Printf("Warning: Outbox: could not record failure of event %d: %v", event.ID, err)
+	}
+}
+
+// runHandler calls a handler, turning a panic into an error so one bad handler cannot stop the dispatcher
+func runHandler(ctx context.Context, h namedHandler, event models.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h.fn(ctx, event)
+}
+
+// backoff returns BaseDelay * 2^(attempts-1), capped at MaxDelay, with +/-20% jitter
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := float64(d.BaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(d.MaxDelay) {
+		delay = float64(d.MaxDelay)
+	}
+	return time.Duration(delay * (0.8 + rand.Float64()*0.4))
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"village_project/internal/audit"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// auditLockKey serializes appends so each entry links to the one before it
+const auditLockKey = 0x61756469740a // "audit"
+
+// auditColumns lists the columns scanned by scanAuditEntry, in order
+const auditColumns = `id, created_at, actor_id::text, actor_role, action, target_type, target_id,
+		       diff::text, ip, remote_ip, request_id, prev_hash, hash`
+
+func scanAuditEntry(row pgx.Row) (models.AuditEntry, error) {
+	var e models.AuditEntry
+	var diff *string
+	err := row.Scan(&e.ID, &e.CreatedAt, &e.ActorID, &e.ActorRole, &e.Action, &e.TargetType, &e.TargetID,
+		&diff, &e.IP, &e.RemoteIP, &e.RequestID, &e.PrevHash, &e.Hash)
+	if diff != nil {
+		e.Diff = []byte(*diff)
+	}
+	return e, err
+}
+
+// AuditFilter selects audit entries; empty fields match everything
+type AuditFilter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time // Exclusive
+	Before     int64      // Only entries with a smaller ID (the next page)
+	Limit      int
+}
+
+// AuditRepository appends to and reads the hash-chained audit log
+type AuditRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewAuditRepository creates a new AuditRepository
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+// Append links entry to the newest entry and inserts it, setting its ID, time and
+// hashes. Appends are serialized with an advisory lock, so the chain follows ID order.
+func (r *AuditRepository) Append(ctx context.Context, entry *models.AuditEntry) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1);`, auditLockKey); err != nil {
+		return err
+	}
+	err = tx.QueryRow(ctx, `SELECT hash FROM public.audit_log ORDER BY id DESC LIMIT 1;`).Scan(&entry.PrevHash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		entry.PrevHash = "" // First entry
+	} else if err != nil {
+		return err
+	}
+
+	// Stored with microsecond precision, so hash what will be read back
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	if entry.Diff, err = audit.Canonical(entry.Diff); err != nil {
+		return err
+	}
+	if entry.Hash, err = audit.Hash(*entry); err != nil {
+		return err
+	}
+	var diff *string
+	if entry.Diff != nil {
+		s := string(entry.Diff)
+		diff = &s
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO public.audit_log
+			(created_at, actor_id, actor_role, action, target_type, target_id, diff, ip, remote_ip, request_id, prev_hash, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9, $10, $11, $12)
+		RETURNING id;
+	`, entry.CreatedAt, entry.ActorID, entry.ActorRole, entry.Action, entry.TargetType, entry.TargetID,
+		diff, entry.IP, entry.RemoteIP, entry.RequestID, entry.PrevHash, entry.Hash).Scan(&entry.ID)
+	if err != nil {
+		log.Printf("Error appending audit entry: %v\n", err)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// List returns entries matching filter, newest first, and the Before value for the next
+// page (0 when this is the last)
+func (r *AuditRepository) List(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, int64, error) {
+	var conditions []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ActorID != "" {
+		add("actor_id = $%d::uuid", filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+	if filter.Before > 0 {
+		add("id < $%d", filter.Before)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit+1)
+
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+auditColumns+`
+		FROM public.audit_log
+		`+where+`
+		ORDER BY id DESC
+		LIMIT $`+fmt.Sprint(len(args))+`;
+	`, args...)
+	if err != nil {
+		log.Printf("Error querying audit log: %v\n", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			log.Printf("Error scanning audit row: %v\n", err)
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var next int64
+	if len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+		next = entries[len(entries)-1].ID
+	}
+	return entries, next, nil
+}
+
+// Walk streams every entry in chain (ID) order to fn, for verification
+func (r *AuditRepository) Walk(ctx context.Context, fn func(models.AuditEntry) error) (int, error) {
+	return exportRows(ctx, r.DB, `
+		SELECT `+auditColumns+`
+		FROM public.audit_log
+		ORDER BY id;
+	`, nil, scanAuditEntry, fn)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// collectionVersion reads the version counter that triggers bump on every write to a
+// collection's tables (see migration 013)
+func collectionVersion(ctx context.Context, db querier, collection string) (int64, time.Time, error) {
+	rows, err := db.Query(ctx, `SELECT version, changed_at FROM public.content_versions WHERE collection = $1;`, collection)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, time.Time{}, err
+		}
+		return 0, time.Time{}, ErrNotFound // Migration not applied
+	}
+	var version int64
+	var changedAt time.Time
+	err = rows.Scan(&version, &changedAt)
+	return version, changedAt, err
+}
+
+// CollectionVersion returns the news collection's version and when it last changed
+func (r *NewsRepository) CollectionVersion(ctx context.Context) (int64, time.Time, error) {
+	return collectionVersion(ctx, r.Pools.Reader(ctx, "news"), "news")
+}
+
+// CollectionVersion returns the jobs collection's version and when it last changed
+func (r *JobRepository) CollectionVersion(ctx context.Context) (int64, time.Time, error) {
+	return collectionVersion(ctx, r.Pools.Reader(ctx, "jobs"), "jobs")
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExportFilter selects the rows of an export. The range is half-open, [From, To), on
+// created_at for jobs and published_at for news; nil bounds are open.
+type ExportFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Status string // Empty for every status
+}
+
+// where builds the WHERE clause and arguments for the filter
+func (f ExportFilter) where(timeColumn string) (string, []any) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.From != nil {
+		add(timeColumn+" >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add(timeColumn+" < $%d", *f.To)
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// exportRows runs query and hands each row to fn as it arrives from the connection, so
+// an export never holds more than one row in memory. It stops at fn's first error.
+func exportRows[T any](ctx context.Context, db *pgxpool.Pool, query string, args []any, scan func(pgx.Row) (T, error), fn func(T) error) (int, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return count, err
+		}
+		if err := fn(item); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// ExportJobs streams the jobs matching filter, oldest first, to fn. Exports read from
+// the replica when there is one.
+func (r *JobRepository) ExportJobs(ctx context.Context, filter ExportFilter, fn func(models.Job) error) (int, error) {
+	where, args := filter.where("created_at")
+	count, err := exportRows(ctx, r.Pools.Reader(ctx, "jobs"), `
+		SELECT `+jobColumns+`
+		FROM public.jobs
+		WHERE `+where+`
+		ORDER BY created_at, id;
+	`, args, scanJob, fn)
+	if err != nil {
+		log.Printf("Error exporting jobs after %d rows: %v\n", count, err)
+	}
+	return count, err
+}
+
+// ExportNews streams the news items matching filter, oldest first, to fn
+func (r *NewsRepository) ExportNews(ctx context.Context, filter ExportFilter, fn func(models.News) error) (int, error) {
+	where, args := filter.where("published_at")
+	count, err := exportRows(ctx, r.Pools.Reader(ctx, "news"), `
+		SELECT `+newsColumns+`
+		FROM public.news
+		WHERE `+where+`
+		ORDER BY published_at, id;
+	`, args, scanNews, fn)
+	if err != nil {
+		log.Printf("Error exporting news after %d rows: %v\n", count, err)
+	}
+	return count, err
+}
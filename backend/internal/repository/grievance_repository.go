@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrInvalidTransition is returned when a grievance status change is not allowed
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ErrUnknownCategory is returned when a grievance references a category that does not exist
+var ErrUnknownCategory = errors.New("unknown grievance category")
+
+// GrievanceRepository handles database operations for grievance tickets
+type GrievanceRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewGrievanceRepository creates a new instance of GrievanceRepository
+func NewGrievanceRepository(db *pgxpool.Pool) *GrievanceRepository {
+	return &GrievanceRepository{DB: db}
+}
+
+const grievanceColumns = `
+	id, ticket_number, created_at, updated_at, category, ward, description, photo_url,
+	reporter_name, reporter_phone, reporter_user_id, assigned_to, status, sla_due_at, resolved_at`
+
+func scanGrievance(row pgx.Row) (models.Grievance, error) {
+	var g models.Grievance
+	err := row.Scan(
+		&g.ID, &g.TicketNumber, &g.CreatedAt, &g.UpdatedAt, &g.Category, &g.Ward, &g.Description, &g.PhotoURL,
+		&g.ReporterName, &g.ReporterPhone, &g.ReporterUserID, &g.AssignedTo, &g.Status, &g.SLADueAt, &g.ResolvedAt,
+	)
+	if err != nil {
+		return g, err
+	}
+	g.Overdue = g.ResolvedAt == nil && g.Status != models.GrievanceStatusRejected && time.Now().After(g.SLADueAt)
+	return g, nil
+}
+
+// nullIfEmpty converts an optional request string to a nullable column value
+func nullIfEmpty(s string) *string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return &s
+}
+
+// ListCategories returns all grievance categories with their SLA hours
+func (r *GrievanceRepository) ListCategories(ctx context.Context) ([]models.GrievanceCategory, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT code, name, sla_hours, default_assignee_id
+		FROM public.grievance_categories
+		ORDER BY name;
+	`)
+	if err != nil {
+		log.Printf("Error querying grievance categories: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []models.GrievanceCategory{}
+	for rows.Next() {
+		var cat models.GrievanceCategory
+		if err := rows.Scan(&cat.Code, &cat.Name, &cat.SLAHours, &cat.DefaultAssigneeID); err != nil {
+			log.Printf("Error scanning grievance category row: %v\n", err)
+			continue
+		}
+		categories = append(categories, cat)
+	}
+	return categories, rows.Err()
+}
+
+// CreateGrievance files a new ticket. The SLA due date and default assignee come from the category.
+func (r *GrievanceRepository) CreateGrievance(ctx context.Context, req models.CreateGrievanceRequest, reporterUserID *string) (models.Grievance, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.Grievance{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var slaHours int
+	var defaultAssignee *string
+	err = tx.QueryRow(ctx,
+		`SELECT sla_hours, default_assignee_id FROM public.grievance_categories WHERE code = $1;`,
+		req.Category,
+	).Scan(&slaHours, &defaultAssignee)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Grievance{}, ErrUnknownCategory
+		}
+		log.Printf("Error looking up grievance category %s: %v\n", req.Category, err)
+		return models.Grievance{}, err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO public.grievances
+			(category, ward, description, photo_url, reporter_name, reporter_phone,
+			 reporter_user_id, assigned_to, status, sla_due_at)
+		VALUES
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, now() + make_interval(hours => $10))
+		RETURNING %s;
+	`, grievanceColumns)
+
+	g, err := scanGrievance(tx.QueryRow(ctx, query,
+		req.Category,
+		req.Ward,
+		req.Description,
+		nullIfEmpty(req.PhotoURL),
+		req.ReporterName,
+		nullIfEmpty(req.ReporterPhone),
+		reporterUserID,
+		defaultAssignee,
+		models.GrievanceStatusNew,
+		slaHours,
+	))
+	if err != nil {
+		log.Printf("Error creating grievance: %v\n", err)
+		return models.Grievance{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Grievance{}, err
+	}
+
+	log.Printf("Successfully created grievance %s (%s)", g.TicketNumber, g.ID)
+	return g, nil
+}
+
+// GetGrievanceByID fetches a single ticket by its UUID
+func (r *GrievanceRepository) GetGrievanceByID(ctx context.Context, id string) (models.Grievance, error) {
+	query := fmt.Sprintf(`SELECT %s FROM public.grievances WHERE id = $1;`, grievanceColumns)
+	g, err := scanGrievance(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Grievance{}, ErrNotFound
+		}
+		log.Printf("Error querying grievance by ID %s: %v\n", id, err)
+		return models.Grievance{}, err
+	}
+	return g, nil
+}
+
+// GetPublicStatus looks up a ticket by its number and returns only the publicly visible fields
+func (r *GrievanceRepository) GetPublicStatus(ctx context.Context, ticketNumber string) (models.PublicGrievanceStatus, error) {
+	query := fmt.Sprintf(`SELECT %s FROM public.grievances WHERE ticket_number = $1;`, grievanceColumns)
+	g, err := scanGrievance(r.DB.QueryRow(ctx, query, strings.ToUpper(ticketNumber)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.PublicGrievanceStatus{}, ErrNotFound
+		}
+		log.Printf("Error querying grievance by ticket %s: %v\n", ticketNumber, err)
+		return models.PublicGrievanceStatus{}, err
+	}
+
+	timeline, err := r.ListComments(ctx, g.ID, false)
+	if err != nil {
+		return models.PublicGrievanceStatus{}, err
+	}
+
+	return models.PublicGrievanceStatus{
+		TicketNumber: g.TicketNumber,
+		Category:     g.Category,
+		Ward:         g.Ward,
+		Status:       g.Status,
+		CreatedAt:    g.CreatedAt,
+		UpdatedAt:    g.UpdatedAt,
+		SLADueAt:     g.SLADueAt,
+		ResolvedAt:   g.ResolvedAt,
+		Timeline:     timeline,
+	}, nil
+}
+
+// ListGrievances returns tickets matching the filter, oldest SLA first
+func (r *GrievanceRepository) ListGrievances(ctx context.Context, filter models.GrievanceFilter) ([]models.Grievance, error) {
+	conditions := []string{}
+	args := []any{}
+	add := func(cond string, val any) {
+		args = append(args, val)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Status != "" {
+		add("status = $%d", filter.Status)
+	}
+	if filter.Ward != "" {
+		add("ward = $%d", filter.Ward)
+	}
+	if filter.Category != "" {
+		add("category = $%d", filter.Category)
+	}
+	if filter.AssignedTo != "" {
+		add("assigned_to = $%d", filter.AssignedTo)
+	}
+	if filter.Overdue {
+		conditions = append(conditions, "resolved_at IS NULL AND status <> 'Rejected' AND sla_due_at < now()")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	query := fmt.Sprintf(`SELECT %s FROM public.grievances %s ORDER BY sla_due_at ASC;`, grievanceColumns, where)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying grievances: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	grievances := []models.Grievance{}
+	for rows.Next() {
+		g, err := scanGrievance(rows)
+		if err != nil {
+			log.Printf("Error scanning grievance row: %v\n", err)
+			continue
+		}
+		grievances = append(grievances, g)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating grievance rows: %v\n", err)
+		return nil, err
+	}
+	return grievances, nil
+}
+
+// UpdateStatus moves a ticket to a new status and records the change on its timeline.
+// The row is locked so concurrent updates cannot skip a lifecycle step.
+func (r *GrievanceRepository) UpdateStatus(ctx context.Context, id string, req models.UpdateGrievanceStatusRequest, actorID *string, actorName string) (models.Grievance, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.Grievance{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current string
+	err = tx.QueryRow(ctx, `SELECT status FROM public.grievances WHERE id = $1 FOR UPDATE;`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Grievance{}, ErrNotFound
+		}
+		return models.Grievance{}, err
+	}
+	if !models.CanTransitionGrievance(current, req.Status) {
+		return models.Grievance{}, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, current, req.Status)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE public.grievances
+		SET status = $2,
+		    updated_at = now(),
+		    resolved_at = CASE WHEN $2 IN ('Resolved', 'Rejected') THEN now() ELSE NULL END
+		WHERE id = $1
+		RETURNING %s;
+	`, grievanceColumns)
+	g, err := scanGrievance(tx.QueryRow(ctx, query, id, req.Status))
+	if err != nil {
+		log.Printf("Error updating grievance %s status: %v\n", id, err)
+		return models.Grievance{}, err
+	}
+
+	body := req.Comment
+	if strings.TrimSpace(body) == "" {
+		body = fmt.Sprintf("Status changed to %s", req.Status)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO public.grievance_comments
+			(grievance_id, author_user_id, author_name, body, status_from, status_to, is_internal)
+		VALUES ($1, $2, $3, $4, $5, $6, false);
+	`, id, actorID, actorName, body, current, req.Status)
+	if err != nil {
+		log.Printf("Error recording grievance %s status comment: %v\n", id, err)
+		return models.Grievance{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Grievance{}, err
+	}
+	log.Printf("Grievance %s moved %s -> %s", g.TicketNumber, current, req.Status)
+	return g, nil
+}
+
+// Assign sets the responsible official for a ticket
+func (r *GrievanceRepository) Assign(ctx context.Context, id string, assigneeID string, actorID *string, actorName string) (models.Grievance, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.Grievance{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		UPDATE public.grievances SET assigned_to = $2, updated_at = now()
+		WHERE id = $1
+		RETURNING %s;
+	`, grievanceColumns)
+	g, err := scanGrievance(tx.QueryRow(ctx, query, id, assigneeID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Grievance{}, ErrNotFound
+		}
+		log.Printf("Error assigning grievance %s: %v\n", id, err)
+		return models.Grievance{}, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO public.grievance_comments (grievance_id, author_user_id, author_name, body, is_internal)
+		VALUES ($1, $2, $3, $4, true);
+	`, id, actorID, actorName, "Assigned to official "+assigneeID)
+	if err != nil {
+		return models.Grievance{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Grievance{}, err
+	}
+	return g, nil
+}
+
+// AddComment appends a note to a ticket's timeline
+func (r *GrievanceRepository) AddComment(ctx context.Context, grievanceID string, req models.AddGrievanceCommentRequest, actorID *string, actorName string) (models.GrievanceComment, error) {
+	var c models.GrievanceComment
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO public.grievance_comments (grievance_id, author_user_id, author_name, body, is_internal)
+		SELECT id, $2, $3, $4, $5 FROM public.grievances WHERE id = $1
+		RETURNING id, grievance_id, created_at, author_user_id, author_name, body, status_from, status_to, is_internal;
+	`, grievanceID, actorID, actorName, req.Body, req.IsInternal).Scan(
+		&c.ID, &c.GrievanceID, &c.CreatedAt, &c.AuthorUserID, &c.AuthorName, &c.Body, &c.StatusFrom, &c.StatusTo, &c.IsInternal,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return c, ErrNotFound
+		}
+		log.Printf("Error adding comment to grievance %s: %v\n", grievanceID, err)
+		return c, err
+	}
+	return c, nil
+}
+
+// ListComments returns a ticket's timeline in chronological order.
+// Internal notes are only included when includeInternal is true.
+func (r *GrievanceRepository) ListComments(ctx context.Context, grievanceID string, includeInternal bool) ([]models.GrievanceComment, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, grievance_id, created_at, author_user_id, author_name, body, status_from, status_to, is_internal
+		FROM public.grievance_comments
+		WHERE grievance_id = $1 AND ($2 OR NOT is_internal)
+		ORDER BY created_at ASC;
+	`, grievanceID, includeInternal)
+	if err != nil {
+		log.Printf("Error querying comments for grievance %s: %v\n", grievanceID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.GrievanceComment{}
+	for rows.Next() {
+		var c models.GrievanceComment
+		if err := rows.Scan(&c.ID, &c.GrievanceID, &c.CreatedAt, &c.AuthorUserID, &c.AuthorName, &c.Body, &c.StatusFrom, &c.StatusTo, &c.IsInternal); err != nil {
+			log.Printf("Error scanning grievance comment row: %v\n", err)
+			continue
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// importRows saves rows in one transaction, each behind its own savepoint so a row the
+// database rejects can be rolled back on its own. Invalid rows are never sent. In
+// ImportModeAll the first bad row stops the import and nothing is committed. A dry run
+// tries every valid row with the same inserts (catching constraint errors too), then
+// rolls back, so its report lists every problem at once.
+func importRows[T any](ctx context.Context, db *pgxpool.Pool, report *models.ImportReport, rows []models.ImportRow[T], insert func(pgx.Tx, T) (string, error)) error {
+	report.Total = len(rows)
+	report.Rows = make([]models.ImportRowResult, len(rows))
+	allOrNothing := report.Mode == models.ImportModeAll
+	aborted := false
+	for i, row := range rows {
+		report.Rows[i] = models.ImportRowResult{Line: row.Line, Errors: row.Errors}
+		if len(row.Errors) > 0 {
+			report.Rows[i].Status = models.ImportRowInvalid
+			report.Invalid++
+			aborted = allOrNothing
+		}
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for i, row := range rows {
+		result := &report.Rows[i]
+		if result.Status != "" {
+			continue
+		}
+		if aborted && !report.DryRun {
+			result.Status = models.ImportRowSkipped
+			report.Skipped++
+			continue
+		}
+		savepoint, err := tx.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		id, err := insert(savepoint, row.Value)
+		if err == nil {
+			err = savepoint.Commit(ctx)
+		}
+		if err != nil {
+			if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+				return rbErr
+			}
+			result.Status, result.Errors = models.ImportRowFailed, []string{err.Error()}
+			report.Failed++
+			aborted = aborted || allOrNothing
+			continue
+		}
+		result.Status = models.ImportRowCreated
+		if !report.DryRun {
+			result.ID = id // A dry run's IDs are rolled back
+		}
+		report.Created++
+	}
+
+	// Rows saved before an all-or-nothing import was aborted are rolled back with it
+	if aborted && !report.DryRun {
+		for i := range report.Rows {
+			if report.Rows[i].Status == models.ImportRowCreated {
+				report.Rows[i].Status, report.Rows[i].ID = models.ImportRowSkipped, ""
+				report.Created--
+				report.Skipped++
+			}
+		}
+	}
+	if report.DryRun || aborted {
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	report.Committed = true
+	log.Printf("Imported %d %s (%d invalid, %d failed)", report.Created, report.Kind, report.Invalid, report.Failed)
+	return nil
+}
+
+// ImportJobs saves spreadsheet rows as jobs posted by postedByUserID (nil from the CLI).
+// Open jobs are matched against saved alerts and announced like any new post; other rows are not.
+func (r *JobRepository) ImportJobs(ctx context.Context, rows []models.ImportRow[models.ImportJobRow], mode string, dryRun bool, postedByUserID *string) (models.ImportReport, error) {
+	report := models.ImportReport{Kind: "jobs", Mode: mode, DryRun: dryRun}
+	err := importRows(ctx, r.DB, &report, rows, func(tx pgx.Tx, row models.ImportJobRow) (string, error) {
+		job, err := insertJob(ctx, tx, row.CreateJobRequest, postedByUserID, row.Status, row.ExpiresAt)
+		return job.ID, err
+	})
+	if report.Committed {
+		r.Cache.Clear()
+	}
+	return report, err
+}
+
+// ImportNews saves spreadsheet rows as news items
+func (r *NewsRepository) ImportNews(ctx context.Context, rows []models.ImportRow[models.ImportNewsRow], mode string, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{Kind: "news", Mode: mode, DryRun: dryRun}
+	err := importRows(ctx, r.DB, &report, rows, func(tx pgx.Tx, row models.ImportNewsRow) (string, error) {
+		newsItem, err := insertNews(ctx, tx, row.CreateNewsRequest, row.PublishedAt)
+		return newsItem.ID, err
+	})
+	if report.Committed {
+		r.Cache.Clear()
+	}
+	return report, err
+}
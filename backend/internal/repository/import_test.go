@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// recordingTx records the statements insertJob runs. Queries return no rows and
+// QueryRow scans nothing, which is all insertJob needs to get through.
+type recordingTx struct {
+	pgx.Tx
+	statements []string
+}
+
+func (tx *recordingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tx.statements = append(tx.statements, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (tx *recordingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	tx.statements = append(tx.statements, sql)
+	return emptyRows{}, nil
+}
+
+func (tx *recordingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	tx.statements = append(tx.statements, sql)
+	return emptyRow{}
+}
+
+func (tx *recordingTx) wrote(table string) bool {
+	for _, sql := range tx.statements {
+		if strings.Contains(sql, "INSERT INTO public."+table) {
+			return true
+		}
+	}
+	return false
+}
+
+type emptyRows struct{ pgx.Rows }
+
+func (emptyRows) Next() bool { return false }
+func (emptyRows) Err() error { return nil }
+func (emptyRows) Close()     {}
+
+type emptyRow struct{}
+
+func (emptyRow) Scan(dest ...any) error { return nil }
+
+func TestImportedJobsAnnouncedOnlyWhenOpen(t *testing.T) {
+	req := models.CreateJobRequest{Title: "Paddy harvest", Description: "Two days of cutting", ContactInfo: "9876543210"}
+	tests := []struct {
+		status   string
+		announce bool
+	}{
+		{"Open", true},
+		{"Filled", false},
+		{"Expired", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			tx := &recordingTx{}
+			if _, err := insertJob(context.Background(), tx, req, nil, tt.status, nil); err != nil {
+				t.Fatalf("insertJob: %v", err)
+			}
+			if got := tx.wrote("outbox_events"); got != tt.announce {
+				t.Errorf("outbox event written = %v, want %v", got, tt.announce)
+			}
+		})
+	}
+}
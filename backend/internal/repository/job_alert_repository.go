@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobAlertRepository handles database operations for saved job alerts
+type JobAlertRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewJobAlertRepository creates a new instance of JobAlertRepository
+func NewJobAlertRepository(db *pgxpool.Pool) *JobAlertRepository {
+	return &JobAlertRepository{DB: db}
+}
+
+const jobAlertColumns = `id, user_id, created_at, updated_at, keywords, location, min_pay, paused`
+
+func scanJobAlert(row pgx.Row) (models.JobAlert, error) {
+	var a models.JobAlert
+	err := row.Scan(&a.ID, &a.UserID, &a.CreatedAt, &a.UpdatedAt, &a.Keywords, &a.Location, &a.MinPay, &a.Paused)
+	return a, err
+}
+
+// CreateAlert saves a new alert for a user
+func (r *JobAlertRepository) CreateAlert(ctx context.Context, userID string, req models.CreateJobAlertRequest) (models.JobAlert, error) {
+	keywords := req.Keywords
+	if keywords == nil {
+		keywords = []string{}
+	}
+	alert, err := scanJobAlert(r.DB.QueryRow(ctx, `
+		INSERT INTO public.job_alerts (user_id, keywords, location, min_pay)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+jobAlertColumns+`;
+	`, userID, keywords, nullIfEmpty(req.Location), req.MinPay))
+	if err != nil {
+		log.Printf("Error creating job alert for user %s: %v\n", userID, err)
+		return models.JobAlert{}, err
+	}
+	return alert, nil
+}
+
+// ListAlerts returns all of a user's alerts, newest first
+func (r *JobAlertRepository) ListAlerts(ctx context.Context, userID string) ([]models.JobAlert, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+jobAlertColumns+` FROM public.job_alerts
+		WHERE user_id = $1
+		ORDER BY created_at DESC;
+	`, userID)
+	if err != nil {
+		log.Printf("Error querying job alerts for user %s: %v\n", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []models.JobAlert{}
+	for rows.Next() {
+		a, err := scanJobAlert(rows)
+		if err != nil {
+			log.Printf("Error scanning job alert row: %v\n", err)
+			continue
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+// SetPaused pauses or resumes one of the user's alerts
+func (r *JobAlertRepository) SetPaused(ctx context.Context, userID, alertID string, paused bool) (models.JobAlert, error) {
+	alert, err := scanJobAlert(r.DB.QueryRow(ctx, `
+		UPDATE public.job_alerts SET paused = $3, updated_at = now()
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+jobAlertColumns+`;
+	`, alertID, userID, paused))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.JobAlert{}, ErrNotFound
+		}
+		log.Printf("Error updating job alert %s: %v\n", alertID, err)
+		return models.JobAlert{}, err
+	}
+	return alert, nil
+}
+
+// DeleteAlert removes one of the user's alerts
+func (r *JobAlertRepository) DeleteAlert(ctx context.Context, userID, alertID string) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM public.job_alerts WHERE id = $1 AND user_id = $2;`, alertID, userID)
+	if err != nil {
+		log.Printf("Error deleting job alert %s: %v\n", alertID, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListNotifications returns the alert notifications queued for a user, newest first
+func (r *JobAlertRepository) ListNotifications(ctx context.Context, userID string) ([]models.JobAlertNotification, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT n.id, n.alert_id, n.job_id, n.user_id, n.created_at, n.status, n.sent_at, j.title
+		FROM public.job_alert_notifications n
+		JOIN public.jobs j ON j.id = n.job_id AND j.deleted_at IS NULL
+		WHERE n.user_id = $1
+		ORDER BY n.created_at DESC
+		LIMIT 100;
+	`, userID)
+	if err != nil {
+		log.Printf("Error querying alert notifications for user %s: %v\n", userID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.JobAlertNotification{}
+	for rows.Next() {
+		var n models.JobAlertNotification
+		if err := rows.Scan(&n.ID, &n.AlertID, &n.JobID, &n.UserID, &n.CreatedAt, &n.Status, &n.SentAt, &n.JobTitle); err != nil {
+			log.Printf("Error scanning alert notification row: %v\n", err)
+			continue
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+// queueJobAlertNotifications matches a newly visible job against every active alert
+// and queues one notification per matching subscriber. It runs inside the caller's
+// transaction so a job is never published without its alerts being queued.
+// ON CONFLICT suppresses duplicates when a job is re-approved or several of a
+// user's alerts match it.
+func queueJobAlertNotifications(ctx context.Context, tx pgx.Tx, job models.Job) (int, error) {
+	rows, err := tx.Query(ctx, `SELECT `+jobAlertColumns+` FROM public.job_alerts WHERE NOT paused;`)
+	if err != nil {
+		return 0, err
+	}
+	var matches []models.JobAlert
+	for rows.Next() {
+		a, err := scanJobAlert(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if a.Matches(job) {
+			matches = append(matches, a)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for _, a := range matches {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO public.job_alert_notifications (alert_id, job_id, user_id)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, job_id) DO NOTHING;
+		`, a.ID, job.ID, a.UserID)
+		if err != nil {
+			return queued, err
+		}
+		queued += int(tag.RowsAffected())
+	}
+	if queued > 0 {
+		log.Printf("Queued %d job alert notifications for job %s", queued, job.ID)
+	}
+	return queued, nil
+}
+
+// ClaimPendingNotifications marks up to limit queued notifications as "sending" and
+// returns them. Rows stuck in "sending" for more than ten minutes (e.g. after a crash)
+// are claimed again. SKIP LOCKED lets several instances drain the queue concurrently.
+// Notifications for jobs in the trash stay queued until the job is restored or purged.
+func (r *JobAlertRepository) ClaimPendingNotifications(ctx context.Context, limit int) ([]models.JobAlertNotification, error) {
+	rows, err := r.DB.Query(ctx, `
+		UPDATE public.job_alert_notifications n
+		SET status = 'sending', claimed_at = now()
+		FROM public.jobs j
+		WHERE j.id = n.job_id AND j.deleted_at IS NULL AND n.id IN (
+			SELECT q.id FROM public.job_alert_notifications q
+			JOIN public.jobs live ON live.id = q.job_id AND live.deleted_at IS NULL
+			WHERE q.status = 'pending'
+			   OR (q.status = 'sending' AND q.claimed_at < now() - interval '10 minutes')
+			ORDER BY q.created_at
+			LIMIT $1
+			FOR UPDATE OF q SKIP LOCKED
+		)
+		RETURNING n.id, n.alert_id, n.job_id, n.user_id, n.created_at, n.status, n.sent_at, j.title;
+	`, limit)
+	if err != nil {
+		log.Printf("Error claiming alert notifications: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := []models.JobAlertNotification{}
+	for rows.Next() {
+		var n models.JobAlertNotification
+		if err := rows.Scan(&n.ID, &n.AlertID, &n.JobID, &n.UserID, &n.CreatedAt, &n.Status, &n.SentAt, &n.JobTitle); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, n)
+	}
+	return claimed, rows.Err()
+}
+
+// MarkNotificationSent records a successful delivery
+func (r *JobAlertRepository) MarkNotificationSent(ctx context.Context, id string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.job_alert_notifications SET status = 'sent', sent_at = now(), last_error = NULL
+		WHERE id = $1;
+	`, id)
+	return err
+}
+
+// MarkNotificationFailed records a delivery that gave up after retries
+func (r *JobAlertRepository) MarkNotificationFailed(ctx context.Context, id string, reason string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.job_alert_notifications SET status = 'failed', last_error = $2
+		WHERE id = $1;
+	`, id, reason)
+	return err
+}
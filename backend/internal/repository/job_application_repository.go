@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Job application errors
+var (
+	ErrAlreadyApplied = errors.New("already applied to this job")
+	ErrJobNotOpen     = errors.New("job is not open for applications")
+)
+
+// JobApplicationRepository handles database operations for job applications
+type JobApplicationRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewJobApplicationRepository creates a new instance of JobApplicationRepository
+func NewJobApplicationRepository(db *pgxpool.Pool) *JobApplicationRepository {
+	return &JobApplicationRepository{DB: db}
+}
+
+const jobApplicationColumns = `
+	id, job_id, created_at, updated_at, applicant_name, applicant_phone,
+	note, availability, applicant_user_id, status`
+
+func scanJobApplication(row pgx.Row) (models.JobApplication, error) {
+	var a models.JobApplication
+	err := row.Scan(
+		&a.ID, &a.JobID, &a.CreatedAt, &a.UpdatedAt, &a.ApplicantName, &a.ApplicantPhone,
+		&a.Note, &a.Availability, &a.ApplicantUserID, &a.Status,
+	)
+	return a, err
+}
+
+// CreateApplication records a worker's interest in an open job
+func (r *JobApplicationRepository) CreateApplication(ctx context.Context, jobID string, req models.CreateJobApplicationRequest, applicantUserID *string) (models.JobApplication, error) {
+	var status string
+	err := r.DB.QueryRow(ctx, `SELECT status FROM public.jobs WHERE id = $1 AND deleted_at IS NULL;`, jobID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.JobApplication{}, ErrNotFound
+		}
+		log.Printf("Error checking job %s before application: %v\n", jobID, err)
+		return models.JobApplication{}, err
+	}
+	if status != "Open" {
+		return models.JobApplication{}, ErrJobNotOpen
+	}
+
+	app, err := scanJobApplication(r.DB.QueryRow(ctx, `
+		INSERT INTO public.job_applications
+			(job_id, applicant_name, applicant_phone, note, availability, applicant_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (job_id, applicant_phone) DO NOTHING
+		RETURNING `+jobApplicationColumns+`;
+	`, jobID, req.ApplicantName, req.ApplicantPhone, nullIfEmpty(req.Note), nullIfEmpty(req.Availability), applicantUserID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.JobApplication{}, ErrAlreadyApplied
+		}
+		log.Printf("Error creating application for job %s: %v\n", jobID, err)
+		return models.JobApplication{}, err
+	}
+
+	log.Printf("Successfully created application %s for job %s", app.ID, jobID)
+	return app, nil
+}
+
+// ListApplications returns all applications for a job, oldest first
+func (r *JobApplicationRepository) ListApplications(ctx context.Context, jobID string) ([]models.JobApplication, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+jobApplicationColumns+`
+		FROM public.job_applications
+		WHERE job_id = $1
+		ORDER BY created_at ASC;
+	`, jobID)
+	if err != nil {
+		log.Printf("Error querying applications for job %s: %v\n", jobID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	apps := []models.JobApplication{}
+	for rows.Next() {
+		app, err := scanJobApplication(rows)
+		if err != nil {
+			log.Printf("Error scanning job application row: %v\n", err)
+			continue
+		}
+		apps = append(apps, app)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating job application rows: %v\n", err)
+		return nil, err
+	}
+	return apps, nil
+}
+
+// UpdateApplicationStatus marks an applicant shortlisted, hired or declined.
+// When the job has auto_fill_on_hire set and the number of hires reaches
+// positions_needed, the job is moved to "Filled" in the same transaction.
+func (r *JobApplicationRepository) UpdateApplicationStatus(ctx context.Context, jobID, applicationID, status string) (models.JobApplicationUpdateResult, error) {
+	var result models.JobApplicationUpdateResult
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the job so concurrent hires are counted consistently. A job in the trash
+	// takes no status changes until it is restored.
+	var jobStatus string
+	var positionsNeeded int
+	var autoFill bool
+	err = tx.QueryRow(ctx, `
+		SELECT status, positions_needed, auto_fill_on_hire FROM public.jobs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;
+	`, jobID).Scan(&jobStatus, &positionsNeeded, &autoFill)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return result, ErrNotFound
+		}
+		return result, err
+	}
+
+	result.Application, err = scanJobApplication(tx.QueryRow(ctx, `
+		UPDATE public.job_applications SET status = $3, updated_at = now()
+		WHERE id = $1 AND job_id = $2
+		RETURNING `+jobApplicationColumns+`;
+	`, applicationID, jobID, status))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return result, ErrNotFound
+		}
+		log.Printf("Error updating application %s: %v\n", applicationID, err)
+		return result, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT count(*) FROM public.job_applications WHERE job_id = $1 AND status = $2;
+	`, jobID, models.ApplicationStatusHired).Scan(&result.HiredCount)
+	if err != nil {
+		return result, err
+	}
+
+	if autoFill && jobStatus == "Open" && result.HiredCount >= positionsNeeded {
+		job, err := scanJob(tx.QueryRow(ctx, `
+			UPDATE public.jobs SET status = 'Filled', updated_at = now() WHERE id = $1
+			RETURNING `+jobColumns+`;
+		`, jobID))
+		if err != nil {
+			log.Printf("Error marking job %s as filled: %v\n", jobID, err)
+			return result, err
+		}
+		if err := writeOutboxEvent(ctx, tx, "job", jobID, models.EventJobFilled, job); err != nil {
+			return result, err
+		}
+		result.JobFilled = true
+		log.Printf("Job %s filled after %d hires", jobID, result.HiredCount)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return result, err
+	}
+	return result, nil
+}
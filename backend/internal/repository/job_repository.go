@@ -3,33 +3,62 @@ package repository
 import (
 	"context"
 	"log"
+	"slices"
+	"time"
+	"village_project/internal/cache"
+	"village_project/internal/database"
 	"village_project/internal/models" // Adjust import path
 
-	// For generating UUID if needed
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	// "errors" // Import if using ErrNotFound from news_repository.go
 )
 
 // JobRepository handles database operations for jobs
 type JobRepository struct {
-	DB *pgxpool.Pool
+	DB    *pgxpool.Pool   // Primary, for writes
+	Pools *database.Pools // Reads go to Pools.Reader (the replica when healthy)
+	Cache *cache.Cache    // read-through cache for open-job reads; nil disables it
 }
 
 // NewJobRepository creates a new instance of JobRepository
-func NewJobRepository(db *pgxpool.Pool) *JobRepository {
-	return &JobRepository{DB: db}
+func NewJobRepository(pools *database.Pools, c *cache.Cache) *JobRepository {
+	return &JobRepository{DB: pools.Writer(), Pools: pools, Cache: c}
+}
+
+// jobColumns lists the columns scanned by scanJob, in order
+const jobColumns = `id, created_at, updated_at, title, description, location,
+		       payment_details, contact_info, status, posted_by_user_id, expires_at,
+		       positions_needed, auto_fill_on_hire, language`
+
+// scanJob scans a single row selected with jobColumns
+func scanJob(row pgx.Row) (models.Job, error) {
+	var job models.Job
+	err := row.Scan(
+		&job.ID, &job.CreatedAt, &job.UpdatedAt, &job.Title, &job.Description, &job.Location,
+		&job.PaymentDetails, &job.ContactInfo, &job.Status, &job.PostedByUserID, &job.ExpiresAt,
+		&job.PositionsNeeded, &job.AutoFillOnHire, &job.Language,
+	)
+	return job, err
 }
 
 // GetAllOpenJobs fetches all jobs with status 'Open', ordered by creation date descending
 func (r *JobRepository) GetAllOpenJobs(ctx context.Context) ([]models.Job, error) {
+	v, err := cache.Load(r.Cache, "open", func() ([]models.Job, error) {
+		return r.queryOpenJobs(ctx)
+	})
+	return slices.Clone(v), err
+}
+
+// queryOpenJobs queries the database; GetAllOpenJobs is the cached entry point
+func (r *JobRepository) queryOpenJobs(ctx context.Context) ([]models.Job, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, description, location,
-		       payment_details, contact_info, status, posted_by_user_id, expires_at
+		SELECT ` + jobColumns + `
 		FROM public.jobs
-		WHERE status = $1
+		WHERE status = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC;
 	`
-	rows, err := r.DB.Query(ctx, query, "Open") // Filter by status = 'Open'
+	rows, err := r.Pools.Reader(ctx, "jobs").Query(ctx, query, "Open") // Filter by status = 'Open'
 	if err != nil {
 		log.Printf("Error querying open jobs: %v\n", err)
 		return nil, err
@@ -38,11 +67,7 @@ func (r *JobRepository) GetAllOpenJobs(ctx context.Context) ([]models.Job, error
 
 	var jobList []models.Job
 	for rows.Next() {
-		var job models.Job
-		err := rows.Scan(
-			&job.ID, &job.CreatedAt, &job.UpdatedAt, &job.Title, &job.Description, &job.Location,
-			&job.PaymentDetails, &job.ContactInfo, &job.Status, &job.PostedByUserID, &job.ExpiresAt,
-		)
+		job, err := scanJob(rows)
 		if err != nil {
 			log.Printf("Error scanning job row: %v\n", err)
 			continue
@@ -61,19 +86,58 @@ func (r *JobRepository) GetAllOpenJobs(ctx context.Context) ([]models.Job, error
 	return jobList, nil
 }
 
+// SearchOpenJobs fetches open jobs matching a free-text query in Telugu, romanized
+// Telugu or English (see package search), newest first
+func (r *JobRepository) SearchOpenJobs(ctx context.Context, query string) ([]models.Job, error) {
+	v, err := cache.Load(r.Cache, "search:"+query, func() ([]models.Job, error) {
+		return r.querySearchOpenJobs(ctx, query)
+	})
+	return slices.Clone(v), err
+}
+
+// querySearchOpenJobs queries the database; SearchOpenJobs is the cached entry point
+func (r *JobRepository) querySearchOpenJobs(ctx context.Context, query string) ([]models.Job, error) {
+	condition, args := searchCondition(query, []any{"Open"})
+	rows, err := r.Pools.Reader(ctx, "jobs").Query(ctx, `
+		SELECT `+jobColumns+`
+		FROM public.jobs
+		WHERE status = $1 AND deleted_at IS NULL`+condition+`
+		ORDER BY created_at DESC;
+	`, args...)
+	if err != nil {
+		log.Printf("Error searching open jobs: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobList := []models.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			log.Printf("Error scanning job row: %v\n", err)
+			continue
+		}
+		jobList = append(jobList, job)
+	}
+	return jobList, rows.Err()
+}
+
 // GetJobByID fetches a single job by its UUID
 func (r *JobRepository) GetJobByID(ctx context.Context, id string) (models.Job, error) {
+	v, err := cache.Load(r.Cache, "item:"+id, func() (models.Job, error) {
+		return r.queryJobByID(ctx, id)
+	})
+	return v, err
+}
+
+// queryJobByID queries the database; GetJobByID is the cached entry point
+func (r *JobRepository) queryJobByID(ctx context.Context, id string) (models.Job, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, description, location,
-		       payment_details, contact_info, status, posted_by_user_id, expires_at
+		SELECT ` + jobColumns + `
 		FROM public.jobs
-		WHERE id = $1;
+		WHERE id = $1 AND deleted_at IS NULL;
 	`
-	var job models.Job
-	err := r.DB.QueryRow(ctx, query, id).Scan(
-		&job.ID, &job.CreatedAt, &job.UpdatedAt, &job.Title, &job.Description, &job.Location,
-		&job.PaymentDetails, &job.ContactInfo, &job.Status, &job.PostedByUserID, &job.ExpiresAt,
-	)
+	job, err := scanJob(r.Pools.Reader(ctx, "jobs").QueryRow(ctx, query, id))
 
 	if err != nil {
 		// Assuming ErrNotFound is defined elsewhere or handle pgx.ErrNoRows directly
@@ -85,41 +149,145 @@ func (r *JobRepository) GetJobByID(ctx context.Context, id string) (models.Job,
 }
 
 // CreateJob inserts a new job posting into the database
-func (r *JobRepository) CreateJob(ctx context.Context, jobData models.CreateJobRequest) (models.Job, error) {
+// postedByUserID is nil for anonymous posts.
+func (r *JobRepository) CreateJob(ctx context.Context, jobData models.CreateJobRequest, postedByUserID *string) (models.Job, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.Job{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	newJob, err := insertJob(ctx, tx, jobData, postedByUserID, "Open", nil) // Default status
+	if err != nil {
+		return models.Job{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Printf("Error committing new job: %v\n", err)
+		return models.Job{}, err
+	}
+	r.Cache.Clear()
+
+	log.Printf("Successfully created job with ID: %s", newJob.ID)
+	return newJob, nil
+}
+
+// insertJob inserts a job within tx. An open job is matched against saved alerts and announced
+// through the outbox.
+func insertJob(ctx context.Context, tx pgx.Tx, jobData models.CreateJobRequest, postedByUserID *string, status string, expiresAt *time.Time) (models.Job, error) {
 	query := `
 		INSERT INTO public.jobs
-			(title, description, location, payment_details, contact_info, status, posted_by_user_id)
+			(title, description, location, payment_details, contact_info, status, posted_by_user_id,
+			 positions_needed, auto_fill_on_hire, language, expires_at)
 		VALUES
-			($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at, updated_at, title, description, location,
-		          payment_details, contact_info, status, posted_by_user_id, expires_at;
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + jobColumns + `;
 	`
-	// For now, posted_by_user_id is NULL as we don't have auth implemented
-	var postedByUserID *string // Pointer to string, can be nil
-
-	// Generate a new UUID for the job ID (alternative: let DB generate it if default is set)
+	positionsNeeded := jobData.PositionsNeeded
+	if positionsNeeded == 0 {
+		positionsNeeded = 1
+	}
 
-	var newJob models.Job
-	err := r.DB.QueryRow(ctx, query,
+	newJob, err := scanJob(tx.QueryRow(ctx, query,
 		jobData.Title,
 		jobData.Description,
 		jobData.Location,       // Pass directly (string, nullable handled by DB)
 		jobData.PaymentDetails, // Pass directly
 		jobData.ContactInfo,
-		"Open",         // Default status
-		postedByUserID, // Pass nil pointer for now
-	).Scan(
-		&newJob.ID, &newJob.CreatedAt, &newJob.UpdatedAt, &newJob.Title, &newJob.Description, &newJob.Location,
-		&newJob.PaymentDetails, &newJob.ContactInfo, &newJob.Status, &newJob.PostedByUserID, &newJob.ExpiresAt,
-	)
+		status,
+		postedByUserID, // nil when posted anonymously
+		positionsNeeded,
+		jobData.AutoFillOnHire,
+		jobData.Language,
+		expiresAt,
+	))
 
 	if err != nil {
 		log.Printf("Error creating job: %v\n", err)
 		return models.Job{}, err
 	}
 
-	log.Printf("Successfully created job with ID: %s", newJob.ID)
+	// Match the new listing against saved alerts in the same transaction
+	if status == "Open" {
+		if _, err := queueJobAlertNotifications(ctx, tx, newJob); err != nil {
+			log.Printf("Error queueing job alerts for job %s: %v\n", newJob.ID, err)
+			return models.Job{}, err
+		}
+	}
+	if err := refreshJobSearchTerms(ctx, tx, newJob.ID); err != nil {
+		return models.Job{}, err
+	}
+	// Only open listings are announced; an imported Filled or Expired job is history
+	if status == "Open" {
+		if err := writeOutboxEvent(ctx, tx, "job", newJob.ID, models.EventJobCreated, newJob); err != nil {
+			return models.Job{}, err
+		}
+	}
 	return newJob, nil
 }
 
-// Add UpdateJob, DeleteJob later...
+// UpdateJobStatus changes a job's status (e.g. approving a pending post, or marking it Filled).
+// A job that becomes "Open" is matched against saved alerts, just like a new post.
+func (r *JobRepository) UpdateJobStatus(ctx context.Context, id string, status string) (models.Job, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.Job{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var previous string
+	err = tx.QueryRow(ctx, `SELECT status FROM public.jobs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE;`, id).Scan(&previous)
+	if err != nil {
+		return models.Job{}, err // pgx.ErrNoRows is checked by the handler
+	}
+
+	job, err := scanJob(tx.QueryRow(ctx, `
+		UPDATE public.jobs SET status = $2, updated_at = now()
+		WHERE id = $1
+		RETURNING `+jobColumns+`;
+	`, id, status))
+	if err != nil {
+		log.Printf("Error updating status of job %s: %v\n", id, err)
+		return models.Job{}, err
+	}
+
+	if status == "Open" && previous != "Open" {
+		if _, err := queueJobAlertNotifications(ctx, tx, job); err != nil {
+			log.Printf("Error queueing job alerts for job %s: %v\n", id, err)
+			return models.Job{}, err
+		}
+	}
+	if event := models.JobStatusEvent(status); event != "" && status != previous {
+		if err := writeOutboxEvent(ctx, tx, "job", job.ID, event, job); err != nil {
+			return models.Job{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Job{}, err
+	}
+	r.Cache.Clear()
+	log.Printf("Job %s status changed %s -> %s", id, previous, status)
+	return job, nil
+}
+
+// DeleteJob soft deletes a job: it disappears from reads and syncing clients receive a
+// tombstone. It stays in the trash, restorable, until purged. Like GetJobByID it returns
+// pgx.ErrNoRows if the job is missing or already deleted.
+func (r *JobRepository) DeleteJob(ctx context.Context, id string, deletedByUserID *string) error {
+	tag, err := r.DB.Exec(ctx, `
+		UPDATE public.jobs SET deleted_at = now(), deleted_by = $2, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL;
+	`, id, deletedByUserID)
+	if err != nil {
+		log.Printf("Error deleting job %s: %v\n", id, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	r.Cache.Clear()
+	return nil
+}
+
+// Add UpdateJob later...
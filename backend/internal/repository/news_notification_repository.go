@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewsNotificationRepository manages the per-recipient news announcement queue
+type NewsNotificationRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewNewsNotificationRepository creates a new instance of NewsNotificationRepository
+func NewNewsNotificationRepository(db *pgxpool.Pool) *NewsNotificationRepository {
+	return &NewsNotificationRepository{DB: db}
+}
+
+// QueueEmails queues an email about a news item for every resident with an address.
+// Residents already queued for it are skipped, so a repeated fan-out is harmless.
+func (r *NewsNotificationRepository) QueueEmails(ctx context.Context, newsID string) (int, error) {
+	tag, err := r.DB.Exec(ctx, `
+		INSERT INTO public.news_notifications (news_id, user_id)
+		SELECT $1, id FROM auth.users
+		WHERE deleted_at IS NULL AND coalesce(email, '') <> ''
+		ON CONFLICT (news_id, user_id) WHERE user_id IS NOT NULL DO NOTHING;
+	`, newsID)
+	if err != nil {
+		log.Printf("Error queueing news %s emails: %v\n", newsID, err)
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// QueuePushes queues a Web Push about a news item for every live subscription,
+// signed in or not. Subscriptions already queued for it are skipped.
+func (r *NewsNotificationRepository) QueuePushes(ctx context.Context, newsID string) (int, error) {
+	tag, err := r.DB.Exec(ctx, `
+		INSERT INTO public.news_notifications (news_id, subscription_id)
+		SELECT $1, id FROM public.push_subscriptions
+		WHERE expires_at IS NULL OR expires_at > now()
+		ON CONFLICT (news_id, subscription_id) WHERE subscription_id IS NOT NULL DO NOTHING;
+	`, newsID)
+	if err != nil {
+		log.Printf("Error queueing news %s pushes: %v\n", newsID, err)
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimPending marks up to limit queued notifications as "sending" and returns them with
+// their news item. Rows stuck in "sending" for more than ten minutes are claimed again.
+// Notifications for news that was deleted or unpublished since stay queued.
+func (r *NewsNotificationRepository) ClaimPending(ctx context.Context, limit int) ([]models.NewsNotification, error) {
+	rows, err := r.DB.Query(ctx, `
+		UPDATE public.news_notifications n
+		SET status = 'sending', claimed_at = now()
+		FROM public.news
+		WHERE news.id = n.news_id AND n.id IN (
+			SELECT q.id FROM public.news_notifications q
+			JOIN public.news live ON live.id = q.news_id
+			WHERE (q.status = 'pending'
+			       OR (q.status = 'sending' AND q.claimed_at < now() - interval '10 minutes'))
+			  AND live.status = 'published' AND live.deleted_at IS NULL
+			ORDER BY q.created_at
+			LIMIT $1
+			FOR UPDATE OF q SKIP LOCKED
+		)
+		RETURNING n.id, n.news_id, n.user_id, n.subscription_id, n.status,
+		          news.id, news.created_at, news.updated_at, news.title, news.content, news.published_at, news.status, news.language;
+	`, limit)
+	if err != nil {
+		log.Printf("Error claiming news notifications: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := []models.NewsNotification{}
+	for rows.Next() {
+		var n models.NewsNotification
+		if err := rows.Scan(&n.ID, &n.NewsID, &n.UserID, &n.SubscriptionID, &n.Status,
+			&n.News.ID, &n.News.CreatedAt, &n.News.UpdatedAt, &n.News.Title, &n.News.Content, &n.News.PublishedAt, &n.News.Status, &n.News.Language); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, n)
+	}
+	return claimed, rows.Err()
+}
+
+// MarkSent records a successful delivery
+func (r *NewsNotificationRepository) MarkSent(ctx context.Context, id string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.news_notifications SET status = 'sent', sent_at = now(), last_error = NULL
+		WHERE id = $1;
+	`, id)
+	return err
+}
+
+// MarkFailed records a delivery that gave up after retries
+func (r *NewsNotificationRepository) MarkFailed(ctx context.Context, id string, reason string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.news_notifications SET status = 'failed', last_error = $2
+		WHERE id = $1;
+	`, id, reason)
+	return err
+}
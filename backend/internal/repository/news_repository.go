@@ -3,7 +3,14 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"slices"
+	"strconv"
+	"time"
+	"village_project/internal/cache"
+	"village_project/internal/database"
+	"village_project/internal/i18n"
 	"village_project/internal/models" // Adjust import path
 
 	"github.com/jackc/pgx/v5"
@@ -12,12 +19,14 @@ import (
 
 // NewsRepository handles database operations for news items
 type NewsRepository struct {
-	DB *pgxpool.Pool
+	DB    *pgxpool.Pool   // Primary, for writes
+	Pools *database.Pools // Reads go to Pools.Reader (the replica when healthy)
+	Cache *cache.Cache    // read-through cache for published reads; nil disables it
 }
 
 // NewNewsRepository creates a new instance of NewsRepository
-func NewNewsRepository(db *pgxpool.Pool) *NewsRepository {
-	return &NewsRepository{DB: db}
+func NewNewsRepository(pools *database.Pools, c *cache.Cache) *NewsRepository {
+	return &NewsRepository{DB: pools.Writer(), Pools: pools, Cache: c}
 }
 
 // ** EXPORT THE ERROR by capitalizing it: E -> E **
@@ -26,14 +35,21 @@ var ErrNotFound = errors.New("resource not found") // <-- Make sure 'E' is capit
 
 // GetAllPublishedNews fetches all published news items
 func (r *NewsRepository) GetAllPublishedNews(ctx context.Context) ([]models.News, error) {
-	// ... function body ...
+	v, err := cache.Load(r.Cache, "published", func() ([]models.News, error) {
+		return r.queryPublishedNews(ctx)
+	})
+	return slices.Clone(v), err
+}
+
+// queryPublishedNews queries the database; GetAllPublishedNews is the cached entry point
+func (r *NewsRepository) queryPublishedNews(ctx context.Context) ([]models.News, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, content, published_at, status
+		SELECT ` + newsColumns + `
 		FROM public.news
-		WHERE status = $1
+		WHERE status = $1 AND deleted_at IS NULL
 		ORDER BY published_at DESC;
 	`
-	rows, err := r.DB.Query(ctx, query, "published")
+	rows, err := r.Pools.Reader(ctx, "news").Query(ctx, query, "published")
 	if err != nil {
 		log.Printf("Error querying published news: %v\n", err)
 		return nil, err
@@ -42,16 +58,7 @@ func (r *NewsRepository) GetAllPublishedNews(ctx context.Context) ([]models.News
 
 	var newsList []models.News
 	for rows.Next() {
-		var newsItem models.News
-		err := rows.Scan(
-			&newsItem.ID,
-			&newsItem.CreatedAt,
-			&newsItem.UpdatedAt,
-			&newsItem.Title,
-			&newsItem.Content,
-			&newsItem.PublishedAt,
-			&newsItem.Status,
-		)
+		newsItem, err := scanNews(rows)
 		if err != nil {
 			log.Printf("Error scanning news row: %v\n", err)
 			continue
@@ -68,25 +75,59 @@ func (r *NewsRepository) GetAllPublishedNews(ctx context.Context) ([]models.News
 	return newsList, nil
 }
 
+// SearchPublishedNews fetches published news matching a free-text query in Telugu,
+// romanized Telugu or English (see package search), newest first
+func (r *NewsRepository) SearchPublishedNews(ctx context.Context, query string) ([]models.News, error) {
+	v, err := cache.Load(r.Cache, "search:"+query, func() ([]models.News, error) {
+		return r.querySearchPublishedNews(ctx, query)
+	})
+	return slices.Clone(v), err
+}
+
+// querySearchPublishedNews queries the database; SearchPublishedNews is the cached entry point
+func (r *NewsRepository) querySearchPublishedNews(ctx context.Context, query string) ([]models.News, error) {
+	condition, args := searchCondition(query, []any{models.NewsStatusPublished})
+	rows, err := r.Pools.Reader(ctx, "news").Query(ctx, `
+		SELECT `+newsColumns+`
+		FROM public.news
+		WHERE status = $1 AND deleted_at IS NULL`+condition+`
+		ORDER BY published_at DESC;
+	`, args...)
+	if err != nil {
+		log.Printf("Error searching news: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	newsList := []models.News{}
+	for rows.Next() {
+		n, err := scanNews(rows)
+		if err != nil {
+			log.Printf("Error scanning news row: %v\n", err)
+			continue
+		}
+		newsList = append(newsList, n)
+	}
+	return newsList, rows.Err()
+}
+
 // GetNewsByID fetches a single news item by its UUID
 // ** ENSURE METHOD NAME is capitalized and RECEIVER is correct **
 func (r *NewsRepository) GetNewsByID(ctx context.Context, id string) (models.News, error) {
+	v, err := cache.Load(r.Cache, "item:"+id, func() (models.News, error) {
+		return r.queryNewsByID(ctx, id)
+	})
+	return v, err
+}
+
+// queryNewsByID queries the database; GetNewsByID is the cached entry point
+func (r *NewsRepository) queryNewsByID(ctx context.Context, id string) (models.News, error) {
 	query := `
-		SELECT id, created_at, updated_at, title, content, published_at, status
+		SELECT ` + newsColumns + `
 		FROM public.news
-		WHERE id = $1;
+		WHERE id = $1 AND deleted_at IS NULL;
 	`
-	var newsItem models.News
-
-	err := r.DB.QueryRow(ctx, query, id).Scan(
-		&newsItem.ID,
-		&newsItem.CreatedAt,
-		&newsItem.UpdatedAt,
-		&newsItem.Title,
-		&newsItem.Content,
-		&newsItem.PublishedAt,
-		&newsItem.Status,
-	)
+	newsItem, err := scanNews(r.Pools.Reader(ctx, "news").QueryRow(ctx, query, id))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -100,3 +141,228 @@ func (r *NewsRepository) GetNewsByID(ctx context.Context, id string) (models.New
 
 	return newsItem, nil
 }
+
+// ErrAlreadyPublished is returned when publishing a news item that is already live
+var ErrAlreadyPublished = errors.New("news item is already published")
+
+const newsColumns = `id, created_at, updated_at, title, content, published_at, status, language`
+
+func scanNews(row pgx.Row) (models.News, error) {
+	var n models.News
+	err := row.Scan(&n.ID, &n.CreatedAt, &n.UpdatedAt, &n.Title, &n.Content, &n.PublishedAt, &n.Status, &n.Language)
+	return n, err
+}
+
+// CreateNews inserts a news item as a draft or, when publish is set, as published now.
+// published_at is reset when a draft is published later.
+func (r *NewsRepository) CreateNews(ctx context.Context, req models.CreateNewsRequest) (models.News, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.News{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	newsItem, err := insertNews(ctx, tx, req, nil)
+	if err != nil {
+		return models.News{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.News{}, err
+	}
+	r.Cache.Clear()
+	return newsItem, nil
+}
+
+// insertNews inserts a news item within tx. A published item is announced on the outbox
+// unless publishedAt backdates it (an imported archive item, not news).
+func insertNews(ctx context.Context, tx pgx.Tx, req models.CreateNewsRequest, publishedAt *time.Time) (models.News, error) {
+	status := models.NewsStatusDraft
+	if req.Publish {
+		status = models.NewsStatusPublished
+	}
+
+	newsItem, err := scanNews(tx.QueryRow(ctx, `
+		INSERT INTO public.news (created_at, updated_at, title, content, published_at, status, language)
+		VALUES (now(), now(), $1, $2, COALESCE($5, now()), $3, $4)
+		RETURNING `+newsColumns+`;
+	`, req.Title, req.Content, status, req.Language, publishedAt))
+	if err != nil {
+		log.Printf("Error inserting news item: %v\n", err)
+		return models.News{}, err
+	}
+	if err := refreshNewsSearchTerms(ctx, tx, newsItem.ID); err != nil {
+		return models.News{}, err
+	}
+	if req.Publish && publishedAt == nil {
+		if err := writeOutboxEvent(ctx, tx, "news", newsItem.ID, models.EventNewsPublished, newsItem); err != nil {
+			return models.News{}, err
+		}
+	}
+	return newsItem, nil
+}
+
+// PublishNews makes a draft live. It returns ErrNotFound or ErrAlreadyPublished.
+func (r *NewsRepository) PublishNews(ctx context.Context, id string) (models.News, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.News{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	newsItem, err := scanNews(tx.QueryRow(ctx, `
+		UPDATE public.news
+		SET status = $2, published_at = now(), updated_at = now()
+		WHERE id = $1 AND status <> $2 AND deleted_at IS NULL
+		RETURNING `+newsColumns+`;
+	`, id, models.NewsStatusPublished))
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, getErr := r.GetNewsByID(ctx, id); getErr != nil {
+			return models.News{}, getErr
+		}
+		return models.News{}, ErrAlreadyPublished
+	}
+	if err != nil {
+		log.Printf("Error publishing news item %s: %v\n", id, err)
+		return models.News{}, err
+	}
+	if err := writeOutboxEvent(ctx, tx, "news", newsItem.ID, models.EventNewsPublished, newsItem); err != nil {
+		return models.News{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.News{}, err
+	}
+	r.Cache.Clear()
+	return newsItem, nil
+}
+
+// DeleteNews soft deletes a news item: it disappears from reads and syncing clients
+// receive a tombstone. It stays in the trash, restorable, until purged. It returns
+// ErrNotFound if the item is missing or already deleted.
+func (r *NewsRepository) DeleteNews(ctx context.Context, id string, deletedByUserID *string) error {
+	tag, err := r.DB.Exec(ctx, `
+		UPDATE public.news SET deleted_at = now(), deleted_by = $2, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NULL;
+	`, id, deletedByUserID)
+	if err != nil {
+		log.Printf("Error deleting news item %s: %v\n", id, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	r.Cache.Clear()
+	return nil
+}
+
+// GetRecentPublishedNews fetches the latest published items, newest first
+func (r *NewsRepository) GetRecentPublishedNews(ctx context.Context, limit int) ([]models.News, error) {
+	v, err := cache.Load(r.Cache, "recent:"+strconv.Itoa(limit), func() ([]models.News, error) {
+		return r.queryRecentPublishedNews(ctx, limit)
+	})
+	return slices.Clone(v), err
+}
+
+// queryRecentPublishedNews queries the database; GetRecentPublishedNews is the cached entry point
+func (r *NewsRepository) queryRecentPublishedNews(ctx context.Context, limit int) ([]models.News, error) {
+	rows, err := r.Pools.Reader(ctx, "news").Query(ctx, `
+		SELECT `+newsColumns+`
+		FROM public.news
+		WHERE status = $1 AND deleted_at IS NULL
+		ORDER BY published_at DESC
+		LIMIT $2;
+	`, models.NewsStatusPublished, limit)
+	if err != nil {
+		log.Printf("Error querying recent news: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	newsList := []models.News{}
+	for rows.Next() {
+		n, err := scanNews(rows)
+		if err != nil {
+			log.Printf("Error scanning news row: %v\n", err)
+			continue
+		}
+		newsList = append(newsList, n)
+	}
+	return newsList, rows.Err()
+}
+
+// archiveTimeZone is i18n.IST as the database names it; month buckets are counted in it
+const archiveTimeZone = "Asia/Kolkata"
+
+// GetNewsArchive counts published news per month (IST), newest month first
+func (r *NewsRepository) GetNewsArchive(ctx context.Context) ([]models.NewsArchiveMonth, error) {
+	v, err := cache.Load(r.Cache, "archive", func() ([]models.NewsArchiveMonth, error) {
+		return r.queryNewsArchive(ctx)
+	})
+	return slices.Clone(v), err
+}
+
+// queryNewsArchive queries the database; GetNewsArchive is the cached entry point. The
+// partial index on published_at makes this an index-only scan.
+func (r *NewsRepository) queryNewsArchive(ctx context.Context) ([]models.NewsArchiveMonth, error) {
+	rows, err := r.Pools.Reader(ctx, "news").Query(ctx, `
+		SELECT extract(year FROM published_at AT TIME ZONE '`+archiveTimeZone+`')::int AS year,
+		       extract(month FROM published_at AT TIME ZONE '`+archiveTimeZone+`')::int AS month,
+		       count(*)
+		FROM public.news
+		WHERE status = $1 AND deleted_at IS NULL
+		GROUP BY 1, 2
+		ORDER BY 1 DESC, 2 DESC;
+	`, models.NewsStatusPublished)
+	if err != nil {
+		log.Printf("Error querying news archive: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	months := []models.NewsArchiveMonth{}
+	for rows.Next() {
+		var m models.NewsArchiveMonth
+		if err := rows.Scan(&m.Year, &m.Month, &m.Count); err != nil {
+			log.Printf("Error scanning news archive row: %v\n", err)
+			return nil, err
+		}
+		months = append(months, m)
+	}
+	return months, rows.Err()
+}
+
+// GetPublishedNewsForMonth fetches the news published in one month (IST), newest first
+func (r *NewsRepository) GetPublishedNewsForMonth(ctx context.Context, year int, month time.Month) ([]models.News, error) {
+	v, err := cache.Load(r.Cache, fmt.Sprintf("archive:%04d-%02d", year, month), func() ([]models.News, error) {
+		return r.queryPublishedNewsForMonth(ctx, year, month)
+	})
+	return slices.Clone(v), err
+}
+
+// queryPublishedNewsForMonth queries the database; GetPublishedNewsForMonth is the cached
+// entry point. The month's bounds are computed here so the query is a range scan.
+func (r *NewsRepository) queryPublishedNewsForMonth(ctx context.Context, year int, month time.Month) ([]models.News, error) {
+	from := time.Date(year, month, 1, 0, 0, 0, 0, i18n.IST)
+	rows, err := r.Pools.Reader(ctx, "news").Query(ctx, `
+		SELECT `+newsColumns+`
+		FROM public.news
+		WHERE status = $1 AND deleted_at IS NULL
+		  AND published_at >= $2 AND published_at < $3
+		ORDER BY published_at DESC;
+	`, models.NewsStatusPublished, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		log.Printf("Error querying news for %04d-%02d: %v\n", year, month, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	newsList := []models.News{}
+	for rows.Next() {
+		n, err := scanNews(rows)
+		if err != nil {
+			log.Printf("Error scanning news row: %v\n", err)
+			continue
+		}
+		newsList = append(newsList, n)
+	}
+	return newsList, rows.Err()
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OutboxRepository reads and settles outbox events for the dispatcher
+type OutboxRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new instance of OutboxRepository
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+// writeOutboxEvent records a domain event inside the caller's transaction, so the
+// event exists if and only if the change that caused it was committed.
+func writeOutboxEvent(ctx context.Context, tx pgx.Tx, aggregateType, aggregateID, eventType string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO public.outbox_events (aggregate_type, aggregate_id, event_type, payload)
+		VALUES ($1, $2, $3, $4);
+	`, aggregateType, aggregateID, eventType, data)
+	if err != nil {
+		log.Printf("Error writing outbox event %s for %s %s: %v\n", eventType, aggregateType, aggregateID, err)
+	}
+	return err
+}
+
+const outboxColumns = `id, event_id, created_at, aggregate_type, aggregate_id, event_type, payload, status,
+	attempts, next_attempt_at, completed_handlers, last_error, processed_at`
+
+func scanOutboxEvent(row pgx.Row) (models.OutboxEvent, error) {
+	var e models.OutboxEvent
+	err := row.Scan(&e.ID, &e.EventID, &e.CreatedAt, &e.AggregateType, &e.AggregateID, &e.EventType, &e.Payload,
+		&e.Status, &e.Attempts, &e.NextAttemptAt, &e.CompletedHandlers, &e.LastError, &e.ProcessedAt)
+	return e, err
+}
+
+// ClaimDue leases up to limit due events, oldest first, for lease. Other dispatchers skip
+// leased rows; if this process dies the lease runs out and the events are claimed again.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxEvent, error) {
+	rows, err := r.DB.Query(ctx, `
+		UPDATE public.outbox_events
+		SET locked_until = now() + $2 * interval '1 second'
+		WHERE id IN (
+			SELECT id FROM public.outbox_events
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			  AND (locked_until IS NULL OR locked_until < now())
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+outboxColumns+`;
+	`, limit, lease.Seconds())
+	if err != nil {
+		log.Printf("Error claiming outbox events: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := []models.OutboxEvent{}
+	for rows.Next() {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, e)
+	}
+	return claimed, rows.Err()
+}
+
+// MarkDone settles an event whose handlers all succeeded
+func (r *OutboxRepository) MarkDone(ctx context.Context, id int64, completedHandlers []string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.outbox_events
+		SET status = 'done', attempts = attempts + 1, completed_handlers = $2,
+		    last_error = NULL, locked_until = NULL, processed_at = now()
+		WHERE id = $1;
+	`, id, completedHandlers)
+	return err
+}
+
+// MarkFailed records a failed attempt. The event is retried at retryAt, or dead-lettered when retryAt is nil.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, completedHandlers []string, reason string, retryAt *time.Time) error {
+	status := models.OutboxStatusDead
+	if retryAt != nil {
+		status = models.OutboxStatusPending
+	}
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.outbox_events
+		SET status = $2, attempts = attempts + 1, completed_handlers = $3, last_error = $4,
+		    next_attempt_at = coalesce($5, next_attempt_at), locked_until = NULL
+		WHERE id = $1;
+	`, id, status, completedHandlers, reason, retryAt)
+	return err
+}
+
+// ListEvents returns outbox events for inspection, newest first
+func (r *OutboxRepository) ListEvents(ctx context.Context, filter models.OutboxFilter) ([]models.OutboxEvent, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+outboxColumns+`
+		FROM public.outbox_events
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY id DESC
+		LIMIT $2;
+	`, filter.Status, limit)
+	if err != nil {
+		log.Printf("Error querying outbox events: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.OutboxEvent{}
+	for rows.Next() {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
+			log.Printf("Error scanning outbox row: %v\n", err)
+			continue
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+// Requeue gives a dead-lettered event a fresh set of attempts. Handlers that already
+// succeeded stay recorded and are not run again.
+func (r *OutboxRepository) Requeue(ctx context.Context, id string) (models.OutboxEvent, error) {
+	eventID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return models.OutboxEvent{}, ErrNotFound
+	}
+	e, err := scanOutboxEvent(r.DB.QueryRow(ctx, `
+		UPDATE public.outbox_events
+		SET status = 'pending', attempts = 0, next_attempt_at = now(), locked_until = NULL
+		WHERE id = $1 AND status = 'dead'
+		RETURNING `+outboxColumns+`;
+	`, eventID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return e, ErrNotFound
+	}
+	return e, err
+}
+
+// ListSince returns up to limit events with an ID above afterID, oldest first
+func (r *OutboxRepository) ListSince(ctx context.Context, afterID int64, limit int) ([]models.OutboxEvent, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+outboxColumns+`
+		FROM public.outbox_events
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2;
+	`, afterID, limit)
+	if err != nil {
+		log.Printf("Error querying outbox events since %d: %v\n", afterID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.OutboxEvent{}
+	for rows.Next() {
+		e, err := scanOutboxEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Poll voting errors
+var (
+	ErrAlreadyVoted  = errors.New("already voted in this poll")
+	ErrPollNotOpen   = errors.New("poll is not open for voting")
+	ErrInvalidChoice = errors.New("invalid poll choice")
+)
+
+// PollRepository handles database operations for polls and votes
+type PollRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewPollRepository creates a new instance of PollRepository
+func NewPollRepository(db *pgxpool.Pool) *PollRepository {
+	return &PollRepository{DB: db}
+}
+
+const pollColumns = `
+	id, created_at, updated_at, question, description, kind, max_choices, anonymous,
+	results_visibility, opens_at, closes_at, created_by_user_id`
+
+func scanPoll(row pgx.Row) (models.Poll, error) {
+	var p models.Poll
+	err := row.Scan(
+		&p.ID, &p.CreatedAt, &p.UpdatedAt, &p.Question, &p.Description, &p.Kind, &p.MaxChoices, &p.Anonymous,
+		&p.ResultsVisibility, &p.OpensAt, &p.ClosesAt, &p.CreatedByUserID,
+	)
+	return p, err
+}
+
+// CreatePoll inserts a poll and its options in one transaction
+func (r *PollRepository) CreatePoll(ctx context.Context, req models.CreatePollRequest, createdBy *string) (models.Poll, error) {
+	opensAt := req.OpensAt
+	if opensAt.IsZero() {
+		opensAt = time.Now()
+	}
+	visibility := req.ResultsVisibility
+	if visibility == "" {
+		visibility = models.PollResultsAfterVote
+	}
+	var maxChoices *int
+	if req.Kind == models.PollKindMultiple {
+		maxChoices = req.MaxChoices
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.Poll{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		INSERT INTO public.polls
+			(question, description, kind, max_choices, anonymous, results_visibility, opens_at, closes_at, created_by_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING %s;
+	`, pollColumns)
+	poll, err := scanPoll(tx.QueryRow(ctx, query,
+		req.Question, nullIfEmpty(req.Description), req.Kind, maxChoices, req.Anonymous,
+		visibility, opensAt, req.ClosesAt, createdBy,
+	))
+	if err != nil {
+		log.Printf("Error creating poll: %v\n", err)
+		return models.Poll{}, err
+	}
+
+	poll.Options = make([]models.PollOption, 0, len(req.Options))
+	for i, label := range req.Options {
+		opt := models.PollOption{Label: label, Position: i + 1}
+		err := tx.QueryRow(ctx,
+			`INSERT INTO public.poll_options (poll_id, label, position) VALUES ($1, $2, $3) RETURNING id;`,
+			poll.ID, label, opt.Position,
+		).Scan(&opt.ID)
+		if err != nil {
+			log.Printf("Error creating poll option %q: %v\n", label, err)
+			return models.Poll{}, err
+		}
+		poll.Options = append(poll.Options, opt)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.Poll{}, err
+	}
+	log.Printf("Successfully created poll with ID: %s", poll.ID)
+	return poll, nil
+}
+
+// ListPolls returns polls with their options, ordered by closing time. activeOnly limits the list to polls open right now.
+func (r *PollRepository) ListPolls(ctx context.Context, activeOnly bool) ([]models.Poll, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM public.polls
+		WHERE NOT $1 OR (opens_at <= now() AND closes_at > now())
+		ORDER BY closes_at DESC;
+	`, pollColumns)
+	rows, err := r.DB.Query(ctx, query, activeOnly)
+	if err != nil {
+		log.Printf("Error querying polls: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	polls := []models.Poll{}
+	for rows.Next() {
+		p, err := scanPoll(rows)
+		if err != nil {
+			log.Printf("Error scanning poll row: %v\n", err)
+			continue
+		}
+		p.Options = []models.PollOption{}
+		polls = append(polls, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	// Options for every listed poll in one query
+	index := make(map[string]int, len(polls))
+	ids := make([]string, len(polls))
+	for i, p := range polls {
+		index[p.ID], ids[i] = i, p.ID
+	}
+	optRows, err := r.DB.Query(ctx, `
+		SELECT poll_id, id, label, position FROM public.poll_options
+		WHERE poll_id = ANY($1::uuid[])
+		ORDER BY poll_id, position;
+	`, ids)
+	if err != nil {
+		log.Printf("Error querying poll options: %v\n", err)
+		return nil, err
+	}
+	defer optRows.Close()
+	for optRows.Next() {
+		var pollID string
+		var opt models.PollOption
+		if err := optRows.Scan(&pollID, &opt.ID, &opt.Label, &opt.Position); err != nil {
+			return nil, err
+		}
+		if i, ok := index[pollID]; ok {
+			polls[i].Options = append(polls[i].Options, opt)
+		}
+	}
+	return polls, optRows.Err()
+}
+
+// GetPoll fetches a poll with its options. voterKey is asked for the caller's key once the
+// poll's mode is known; if it returns one, HasVoted is filled in.
+func (r *PollRepository) GetPoll(ctx context.Context, id string, voterKey func(anonymous bool) string) (models.Poll, error) {
+	query := fmt.Sprintf(`SELECT %s FROM public.polls WHERE id = $1;`, pollColumns)
+	poll, err := scanPoll(r.DB.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Poll{}, ErrNotFound
+		}
+		log.Printf("Error querying poll %s: %v\n", id, err)
+		return models.Poll{}, err
+	}
+
+	rows, err := r.DB.Query(ctx,
+		`SELECT id, label, position FROM public.poll_options WHERE poll_id = $1 ORDER BY position;`, id)
+	if err != nil {
+		return models.Poll{}, err
+	}
+	defer rows.Close()
+	poll.Options = []models.PollOption{}
+	for rows.Next() {
+		var opt models.PollOption
+		if err := rows.Scan(&opt.ID, &opt.Label, &opt.Position); err != nil {
+			return models.Poll{}, err
+		}
+		poll.Options = append(poll.Options, opt)
+	}
+	if err := rows.Err(); err != nil {
+		return models.Poll{}, err
+	}
+
+	if key := voterKey(poll.Anonymous); key != "" {
+		err = r.DB.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM public.poll_ballots WHERE poll_id = $1 AND voter_key = $2);`,
+			id, key,
+		).Scan(&poll.HasVoted)
+		if err != nil {
+			return models.Poll{}, err
+		}
+	}
+	return poll, nil
+}
+
+// CastVote records one ballot for voterKey. The unique (poll_id, voter_key) constraint
+// guarantees one ballot per voter even under concurrent requests.
+func (r *PollRepository) CastVote(ctx context.Context, pollID string, voterKey string, optionIDs []string) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var kind string
+	var maxChoices *int
+	var isOpen bool
+	err = tx.QueryRow(ctx, `
+		SELECT kind, max_choices, (opens_at <= now() AND closes_at > now())
+		FROM public.polls WHERE id = $1 FOR SHARE;
+	`, pollID).Scan(&kind, &maxChoices, &isOpen)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if !isOpen {
+		return ErrPollNotOpen
+	}
+
+	unique := map[string]bool{}
+	for _, id := range optionIDs {
+		unique[id] = true
+	}
+	if len(unique) != len(optionIDs) {
+		return fmt.Errorf("%w: duplicate option", ErrInvalidChoice)
+	}
+	if kind == models.PollKindSingle && len(optionIDs) != 1 {
+		return fmt.Errorf("%w: exactly one option must be chosen", ErrInvalidChoice)
+	}
+	if maxChoices != nil && len(optionIDs) > *maxChoices {
+		return fmt.Errorf("%w: at most %d options may be chosen", ErrInvalidChoice, *maxChoices)
+	}
+
+	var valid int
+	err = tx.QueryRow(ctx,
+		`SELECT count(*) FROM public.poll_options WHERE poll_id = $1 AND id = ANY($2::uuid[]);`,
+		pollID, optionIDs,
+	).Scan(&valid)
+	if err != nil {
+		return err
+	}
+	if valid != len(optionIDs) {
+		return fmt.Errorf("%w: option does not belong to this poll", ErrInvalidChoice)
+	}
+
+	var ballotID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO public.poll_ballots (poll_id, voter_key) VALUES ($1, $2)
+		ON CONFLICT (poll_id, voter_key) DO NOTHING
+		RETURNING id;
+	`, pollID, voterKey).Scan(&ballotID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAlreadyVoted
+		}
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO public.poll_votes (ballot_id, option_id)
+		SELECT $1, unnest($2::uuid[]);
+	`, ballotID, optionIDs)
+	if err != nil {
+		log.Printf("Error recording votes for poll %s: %v\n", pollID, err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetResults computes the live tally for a poll
+func (r *PollRepository) GetResults(ctx context.Context, pollID string) (models.PollResults, error) {
+	results := models.PollResults{PollID: pollID, GeneratedAt: time.Now()}
+
+	err := r.DB.QueryRow(ctx, `
+		SELECT p.question, p.closes_at <= now(),
+		       (SELECT count(*) FROM public.poll_ballots b WHERE b.poll_id = p.id)
+		FROM public.polls p WHERE p.id = $1;
+	`, pollID).Scan(&results.Question, &results.Closed, &results.TotalBallots)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return results, ErrNotFound
+		}
+		log.Printf("Error querying poll %s for results: %v\n", pollID, err)
+		return results, err
+	}
+
+	rows, err := r.DB.Query(ctx, `
+		SELECT o.id, o.label, count(v.ballot_id)
+		FROM public.poll_options o
+		LEFT JOIN public.poll_votes v ON v.option_id = o.id
+		WHERE o.poll_id = $1
+		GROUP BY o.id, o.label, o.position
+		ORDER BY o.position;
+	`, pollID)
+	if err != nil {
+		log.Printf("Error tallying poll %s: %v\n", pollID, err)
+		return results, err
+	}
+	defer rows.Close()
+
+	results.Options = []models.PollOptionTally{}
+	for rows.Next() {
+		var t models.PollOptionTally
+		if err := rows.Scan(&t.OptionID, &t.Label, &t.Votes); err != nil {
+			return results, err
+		}
+		results.Options = append(results.Options, t)
+	}
+	return results, rows.Err()
+}
+
+// ClosePoll ends voting immediately
+func (r *PollRepository) ClosePoll(ctx context.Context, id string) error {
+	tag, err := r.DB.Exec(ctx, `
+		UPDATE public.polls
+		SET closes_at = now(), opens_at = LEAST(opens_at, now() - interval '1 second'), updated_at = now()
+		WHERE id = $1 AND closes_at > now();
+	`, id)
+	if err != nil {
+		log.Printf("Error closing poll %s: %v\n", id, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPollNotOpen
+	}
+	return nil
+}
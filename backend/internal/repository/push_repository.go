@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PushRepository stores Web Push subscriptions
+type PushRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewPushRepository creates a new instance of PushRepository
+func NewPushRepository(db *pgxpool.Pool) *PushRepository {
+	return &PushRepository{DB: db}
+}
+
+const pushSubscriptionColumns = `id, created_at, updated_at, user_id, device_key, endpoint, p256dh, auth_secret,
+	user_agent, expires_at, last_success_at, failure_count`
+
+func scanPushSubscription(row pgx.Row) (models.PushSubscription, error) {
+	var s models.PushSubscription
+	err := row.Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt, &s.UserID, &s.DeviceKey, &s.Endpoint, &s.P256dh, &s.Auth,
+		&s.UserAgent, &s.ExpiresAt, &s.LastSuccessAt, &s.FailureCount)
+	return s, err
+}
+
+// SaveSubscription registers a subscription, or takes over an existing one with the same endpoint.
+// Browsers rotate keys without changing the endpoint, so keys and owner are always refreshed.
+func (r *PushRepository) SaveSubscription(ctx context.Context, sub models.PushSubscription) (models.PushSubscription, error) {
+	saved, err := scanPushSubscription(r.DB.QueryRow(ctx, `
+		INSERT INTO public.push_subscriptions (user_id, device_key, endpoint, p256dh, auth_secret, user_agent, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+		    device_key = EXCLUDED.device_key,
+		    p256dh = EXCLUDED.p256dh,
+		    auth_secret = EXCLUDED.auth_secret,
+		    user_agent = EXCLUDED.user_agent,
+		    expires_at = EXCLUDED.expires_at,
+		    failure_count = 0,
+		    updated_at = now()
+		RETURNING `+pushSubscriptionColumns+`;
+	`, sub.UserID, sub.DeviceKey, sub.Endpoint, sub.P256dh, sub.Auth, sub.UserAgent, sub.ExpiresAt))
+	if err != nil {
+		log.Printf("Error saving push subscription: %v\n", err)
+	}
+	return saved, err
+}
+
+// DeleteOwnedSubscription removes a subscription if it belongs to the user or device.
+// It returns ErrNotFound otherwise.
+func (r *PushRepository) DeleteOwnedSubscription(ctx context.Context, endpoint string, userID, deviceKey *string) error {
+	tag, err := r.DB.Exec(ctx, `
+		DELETE FROM public.push_subscriptions
+		WHERE endpoint = $1 AND ((user_id IS NOT NULL AND user_id = $2) OR (device_key IS NOT NULL AND device_key = $3));
+	`, endpoint, userID, deviceKey)
+	if err != nil {
+		log.Printf("Error deleting push subscription: %v\n", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteSubscription prunes a subscription the push service reported as gone
+func (r *PushRepository) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := r.DB.Exec(ctx, `DELETE FROM public.push_subscriptions WHERE id = $1;`, id)
+	return err
+}
+
+// ListForUser returns the live subscriptions of one user
+func (r *PushRepository) ListForUser(ctx context.Context, userID string) ([]models.PushSubscription, error) {
+	return r.list(ctx, `WHERE user_id = $1 AND (expires_at IS NULL OR expires_at > now())`, userID)
+}
+
+// GetSubscription returns one live subscription, or ErrNotFound once it was pruned or expired
+func (r *PushRepository) GetSubscription(ctx context.Context, id string) (models.PushSubscription, error) {
+	subs, err := r.list(ctx, `WHERE id = $1 AND (expires_at IS NULL OR expires_at > now())`, id)
+	if err != nil {
+		return models.PushSubscription{}, err
+	}
+	if len(subs) == 0 {
+		return models.PushSubscription{}, ErrNotFound
+	}
+	return subs[0], nil
+}
+
+func (r *PushRepository) list(ctx context.Context, where string, args ...any) ([]models.PushSubscription, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+pushSubscriptionColumns+` FROM public.push_subscriptions `+where+` ORDER BY created_at;`, args...)
+	if err != nil {
+		log.Printf("Error querying push subscriptions: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []models.PushSubscription{}
+	for rows.Next() {
+		s, err := scanPushSubscription(rows)
+		if err != nil {
+			log.Printf("Error scanning push subscription row: %v\n", err)
+			continue
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// RecordResult updates delivery bookkeeping after a send attempt
+func (r *PushRepository) RecordResult(ctx context.Context, id string, success bool) error {
+	query := `UPDATE public.push_subscriptions SET failure_count = failure_count + 1 WHERE id = $1;`
+	if success {
+		query = `UPDATE public.push_subscriptions SET failure_count = 0, last_success_at = now() WHERE id = $1;`
+	}
+	_, err := r.DB.Exec(ctx, query, id)
+	return err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"village_project/internal/search"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// refreshSearchTerms stores the search keys of the texts returned by textQuery (called
+// with id) in table.search_terms. Table and query are trusted constants.
+func refreshSearchTerms(ctx context.Context, q querier, table, textQuery, id string) error {
+	rows, err := q.Query(ctx, textQuery, id)
+	if err != nil {
+		return err
+	}
+	texts, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, `UPDATE public.`+table+` SET search_terms = $2 WHERE id = $1;`, id, search.Terms(texts...))
+	if err != nil {
+		log.Printf("Error updating search terms of %s %s: %v\n", table, id, err)
+	}
+	return err
+}
+
+// refreshJobSearchTerms re-indexes a job from its own text and all its translations
+func refreshJobSearchTerms(ctx context.Context, q querier, jobID string) error {
+	return refreshSearchTerms(ctx, q, "jobs", `
+		SELECT concat_ws(' ', title, description, location, payment_details) FROM public.jobs WHERE id = $1
+		UNION ALL
+		SELECT concat_ws(' ', title, description, location, payment_details) FROM public.job_translations WHERE job_id = $1;
+	`, jobID)
+}
+
+// refreshNewsSearchTerms re-indexes a news item from its own text and all its translations
+func refreshNewsSearchTerms(ctx context.Context, q querier, newsID string) error {
+	return refreshSearchTerms(ctx, q, "news", `
+		SELECT concat_ws(' ', title, content) FROM public.news WHERE id = $1
+		UNION ALL
+		SELECT concat_ws(' ', title, content) FROM public.news_translations WHERE news_id = $1;
+	`, newsID)
+}
+
+// searchCondition turns a query into "AND search_terms && $n" clauses, one per query
+// word, appending each word's alternatives to args
+func searchCondition(query string, args []any) (string, []any) {
+	var clauses strings.Builder
+	for _, alternatives := range search.QueryTerms(query) {
+		args = append(args, alternatives)
+		fmt.Fprintf(&clauses, " AND search_terms && $%d::text[]", len(args))
+	}
+	return clauses.String(), args
+}
+
+// ReindexSearchTerms recomputes search_terms for every job and news item, e.g. after
+// the migration that adds the column or a change to the synonym dictionary
+func ReindexSearchTerms(ctx context.Context, db querier) (jobs, news int, err error) {
+	reindex := func(table string, refresh func(context.Context, querier, string) error) (int, error) {
+		rows, err := db.Query(ctx, `SELECT id::text FROM public.`+table+`;`)
+		if err != nil {
+			return 0, err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return 0, err
+		}
+		for i, id := range ids {
+			if err := refresh(ctx, db, id); err != nil {
+				return i, err
+			}
+		}
+		return len(ids), nil
+	}
+	if jobs, err = reindex("jobs", refreshJobSearchTerms); err != nil {
+		return jobs, 0, err
+	}
+	news, err = reindex("news", refreshNewsSearchTerms)
+	return jobs, news, err
+}
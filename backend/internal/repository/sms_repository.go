@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SMSRepository stores outgoing SMS messages and their delivery status
+type SMSRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewSMSRepository creates a new instance of SMSRepository
+func NewSMSRepository(db *pgxpool.Pool) *SMSRepository {
+	return &SMSRepository{DB: db}
+}
+
+// CreateMessageWithinCap records a message unless the recipient has already used their
+// allowance of dailyCap messages since a point in time; then it records the message as
+// capped and reports capped. The count and insert hold a per-recipient advisory lock, so
+// concurrent senders on any instance cannot both take the last slot.
+func (r *SMSRepository) CreateMessageWithinCap(ctx context.Context, msg models.SMSMessage, since time.Time, dailyCap int) (id string, capped bool, err error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('sms_cap:' || $1));`, msg.Recipient); err != nil {
+		return "", false, err
+	}
+	var sent int
+	err = tx.QueryRow(ctx, `
+		SELECT count(*) FROM public.sms_messages
+		WHERE recipient = $1 AND created_at >= $2 AND status NOT IN ('capped', 'failed');
+	`, msg.Recipient, since).Scan(&sent)
+	if err != nil {
+		return "", false, err
+	}
+	if sent >= dailyCap {
+		detail := fmt.Sprintf("daily cap of %d reached", dailyCap)
+		msg.Status = models.SMSStatusCapped
+		msg.StatusDetail = &detail
+		capped = true
+	}
+	if id, err = insertSMSMessage(ctx, tx, msg); err != nil {
+		return "", false, err
+	}
+	return id, capped, tx.Commit(ctx)
+}
+
+// CreateMessage records a message before it is handed to the provider
+func (r *SMSRepository) CreateMessage(ctx context.Context, msg models.SMSMessage) (string, error) {
+	return insertSMSMessage(ctx, r.DB, msg)
+}
+
+// insertSMSMessage inserts msg through the pool or a transaction
+func insertSMSMessage(ctx context.Context, db querier, msg models.SMSMessage) (string, error) {
+	var id string
+	err := db.QueryRow(ctx, `
+		INSERT INTO public.sms_messages
+			(recipient, event, body, encoding, segments, dlt_template_id, provider, status, status_detail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id;
+	`, msg.Recipient, msg.Event, msg.Body, msg.Encoding, msg.Segments, msg.DLTTemplateID,
+		msg.Provider, msg.Status, msg.StatusDetail).Scan(&id)
+	if err != nil {
+		log.Printf("Error recording SMS to %s: %v\n", msg.Recipient, err)
+	}
+	return id, err
+}
+
+// MarkSubmitted stores the provider's message ID once the provider accepts the message
+func (r *SMSRepository) MarkSubmitted(ctx context.Context, id, providerMessageID string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.sms_messages
+		SET status = 'submitted', provider_message_id = nullif($2, ''), updated_at = now()
+		WHERE id = $1;
+	`, id, providerMessageID)
+	return err
+}
+
+// MarkFailed records a message the provider did not accept
+func (r *SMSRepository) MarkFailed(ctx context.Context, id, detail string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.sms_messages SET status = 'failed', status_detail = $2, updated_at = now()
+		WHERE id = $1;
+	`, id, detail)
+	return err
+}
+
+// ApplyDeliveryCallback updates a message from a provider delivery report.
+// It returns ErrNotFound if no message has that provider ID.
+func (r *SMSRepository) ApplyDeliveryCallback(ctx context.Context, provider string, cb models.SMSDeliveryCallback) error {
+	tag, err := r.DB.Exec(ctx, `
+		UPDATE public.sms_messages
+		SET status = $3,
+		    status_detail = nullif($4, ''),
+		    delivered_at = CASE WHEN $3 = 'delivered' THEN now() ELSE delivered_at END,
+		    updated_at = now()
+		WHERE provider = $1 AND provider_message_id = $2 AND status <> 'delivered';
+	`, provider, cb.ProviderMessageID, cb.Status, cb.Detail)
+	if err != nil {
+		log.Printf("Error applying SMS callback for %s: %v\n", cb.ProviderMessageID, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrInvalidSyncToken is returned for a token this server did not issue
+var ErrInvalidSyncToken = errors.New("invalid sync token")
+
+// ErrSyncTokenExpired is returned for a token older than a trash purge: tombstones it
+// had not yet received are gone, so the client must start over from a full snapshot
+var ErrSyncTokenExpired = errors.New("sync token expired")
+
+// SyncCursor is the decoded form of a sync token (see migration 015 for why it holds a
+// transaction ID rather than just a sequence number)
+type SyncCursor struct {
+	From    uint64 // Return rows written by transactions at or above this xid
+	Horizon uint64 // While paging: the snapshot xmin of the first page, the next From
+	After   int64  // While paging: last change_seq already returned
+	Full    bool   // Full snapshot: tombstones are skipped
+}
+
+// ParseSyncToken decodes a token from SyncCursor.Token. The empty token asks for a full snapshot.
+func ParseSyncToken(token string) (SyncCursor, error) {
+	if token == "" {
+		return SyncCursor{Full: true}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return SyncCursor{}, ErrInvalidSyncToken
+	}
+	var c SyncCursor
+	var full int
+	if n, err := fmt.Sscanf(string(raw), "1:%d:%d:%d:%d", &c.From, &c.Horizon, &c.After, &full); err != nil || n != 4 {
+		return SyncCursor{}, ErrInvalidSyncToken
+	}
+	if c.After < 0 || (c.Horizon != 0 && c.Horizon < c.From) || (full != 0 && full != 1) {
+		return SyncCursor{}, ErrInvalidSyncToken
+	}
+	c.Full = full == 1
+	if c.Token() != token {
+		return SyncCursor{}, ErrInvalidSyncToken // Trailing data or a non-canonical number
+	}
+	return c, nil
+}
+
+// Token encodes the cursor as an opaque string
+func (c SyncCursor) Token() string {
+	full := 0
+	if c.Full {
+		full = 1
+	}
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "1:%d:%d:%d:%d", c.From, c.Horizon, c.After, full))
+}
+
+// SyncRepository reads the change feed behind GET /api/v1/sync
+type SyncRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewSyncRepository creates a new SyncRepository
+func NewSyncRepository(db *pgxpool.Pool) *SyncRepository {
+	return &SyncRepository{DB: db}
+}
+
+// Changes returns up to limit news and job changes after cursor, ordered by change_seq,
+// the cursor for the next call and whether more changes are already waiting. It runs on
+// the primary: the snapshot xmin must come from the server that assigns transaction IDs.
+func (r *SyncRepository) Changes(ctx context.Context, cursor SyncCursor, limit int) ([]models.SyncChange, SyncCursor, bool, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, SyncCursor{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// The first statement fixes the snapshot the change queries below read from
+	var xmin string
+	if err := tx.QueryRow(ctx, `SELECT pg_snapshot_xmin(pg_current_snapshot())::text;`).Scan(&xmin); err != nil {
+		log.Printf("Error reading sync snapshot: %v\n", err)
+		return nil, SyncCursor{}, false, err
+	}
+	current, err := strconv.ParseUint(xmin, 10, 64)
+	if err != nil {
+		return nil, SyncCursor{}, false, err
+	}
+	horizon := cursor.Horizon
+	if horizon == 0 {
+		horizon = current
+	}
+	if cursor.From > current || horizon > current {
+		return nil, SyncCursor{}, false, ErrInvalidSyncToken // Issued by another database
+	}
+	if !cursor.Full {
+		var purged string
+		if err := tx.QueryRow(ctx, `
+			SELECT coalesce(max(max_change_xid::text::numeric), 0)::text FROM public.trash_purges;
+		`).Scan(&purged); err != nil {
+			log.Printf("Error reading trash purges: %v\n", err)
+			return nil, SyncCursor{}, false, err
+		}
+		if newest, err := strconv.ParseUint(purged, 10, 64); err != nil {
+			return nil, SyncCursor{}, false, err
+		} else if newest >= cursor.From {
+			return nil, SyncCursor{}, false, ErrSyncTokenExpired
+		}
+	}
+
+	news, err := r.newsChanges(ctx, tx, cursor, limit+1)
+	if err != nil {
+		return nil, SyncCursor{}, false, err
+	}
+	jobs, err := r.jobChanges(ctx, tx, cursor, limit+1)
+	if err != nil {
+		return nil, SyncCursor{}, false, err
+	}
+
+	// Merge the two seq-ordered lists
+	changes := make([]models.SyncChange, 0, min(len(news)+len(jobs), limit+1))
+	for len(changes) <= limit && (len(news) > 0 || len(jobs) > 0) {
+		if len(jobs) == 0 || (len(news) > 0 && news[0].Seq < jobs[0].Seq) {
+			changes, news = append(changes, news[0]), news[1:]
+		} else {
+			changes, jobs = append(changes, jobs[0]), jobs[1:]
+		}
+	}
+	if len(changes) > limit {
+		changes = changes[:limit]
+		next := SyncCursor{From: cursor.From, Horizon: horizon, After: changes[limit-1].Seq, Full: cursor.Full}
+		return changes, next, true, nil
+	}
+	return changes, SyncCursor{From: horizon}, false, nil
+}
+
+// syncFilter selects the rows changed after cursor; live is the SQL for "publicly visible"
+func syncFilter(cursor SyncCursor, live string) string {
+	filter := `change_xid >= $1::text::xid8 AND change_seq > $2`
+	if cursor.Full {
+		filter += ` AND ` + live
+	}
+	return filter
+}
+
+func (r *SyncRepository) newsChanges(ctx context.Context, tx pgx.Tx, cursor SyncCursor, limit int) ([]models.SyncChange, error) {
+	const live = `(status = 'published' AND deleted_at IS NULL)`
+	rows, err := tx.Query(ctx, `
+		SELECT `+newsColumns+`, change_seq, `+live+`
+		FROM public.news
+		WHERE `+syncFilter(cursor, live)+`
+		ORDER BY change_seq
+		LIMIT $3;
+	`, strconv.FormatUint(cursor.From, 10), cursor.After, limit)
+	if err != nil {
+		log.Printf("Error querying news changes: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.SyncChange{}
+	for rows.Next() {
+		var n models.News
+		change := models.SyncChange{Collection: models.SyncCollectionNews}
+		var isLive bool
+		if err := rows.Scan(&n.ID, &n.CreatedAt, &n.UpdatedAt, &n.Title, &n.Content, &n.PublishedAt, &n.Status, &n.Language,
+			&change.Seq, &isLive); err != nil {
+			return nil, err
+		}
+		change.ID = n.ID
+		if isLive {
+			change.News = &n
+		} else {
+			change.Deleted = true
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *SyncRepository) jobChanges(ctx context.Context, tx pgx.Tx, cursor SyncCursor, limit int) ([]models.SyncChange, error) {
+	const live = `(status = 'Open' AND deleted_at IS NULL)`
+	rows, err := tx.Query(ctx, `
+		SELECT `+jobColumns+`, change_seq, `+live+`
+		FROM public.jobs
+		WHERE `+syncFilter(cursor, live)+`
+		ORDER BY change_seq
+		LIMIT $3;
+	`, strconv.FormatUint(cursor.From, 10), cursor.After, limit)
+	if err != nil {
+		log.Printf("Error querying job changes: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.SyncChange{}
+	for rows.Next() {
+		var job models.Job
+		change := models.SyncChange{Collection: models.SyncCollectionJobs}
+		var isLive bool
+		if err := rows.Scan(
+			&job.ID, &job.CreatedAt, &job.UpdatedAt, &job.Title, &job.Description, &job.Location,
+			&job.PaymentDetails, &job.ContactInfo, &job.Status, &job.PostedByUserID, &job.ExpiresAt,
+			&job.PositionsNeeded, &job.AutoFillOnHire, &job.Language,
+			&change.Seq, &isLive,
+		); err != nil {
+			return nil, err
+		}
+		change.ID = job.ID
+		if isLive {
+			change.Job = &job
+		} else {
+			change.Deleted = true
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestSyncTokenRoundTrip(t *testing.T) {
+	cursors := []SyncCursor{
+		{From: 1},
+		{From: 742, Full: true},
+		{From: 742, Horizon: 800, After: 12345},
+		{From: 1 << 40, Horizon: 1<<40 + 7, After: 1 << 50, Full: true},
+	}
+	for _, want := range cursors {
+		token := want.Token()
+		got, err := ParseSyncToken(token)
+		if err != nil {
+			t.Errorf("ParseSyncToken(%q) for %+v: %v", token, want, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("round trip of %+v gave %+v", want, got)
+		}
+	}
+}
+
+func TestParseSyncTokenEmptyIsFullSnapshot(t *testing.T) {
+	c, err := ParseSyncToken("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != (SyncCursor{Full: true}) {
+		t.Errorf("empty token = %+v, want a full snapshot from the start", c)
+	}
+}
+
+func TestParseSyncTokenRejects(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tokens := map[string]string{
+		"not base64":          "%%%",
+		"padded base64":       base64.URLEncoding.EncodeToString([]byte("1:50:0:0:0")),
+		"other version":       encode("2:5:0:0:0"),
+		"too few fields":      encode("1:5:0:0"),
+		"not numbers":         encode("1:a:b:c:d"),
+		"negative xid":        encode("1:-5:0:0:0"),
+		"negative position":   encode("1:5:0:-1:0"),
+		"horizon before from": encode("1:9:5:0:0"),
+		"full flag not 0/1":   encode("1:5:0:0:2"),
+		"trailing data":       encode("1:5:0:0:0:extra"),
+		"leading zeros":       encode("1:05:0:0:0"),
+	}
+	for name, token := range tokens {
+		if _, err := ParseSyncToken(token); !errors.Is(err, ErrInvalidSyncToken) {
+			t.Errorf("%s: err = %v, want ErrInvalidSyncToken", name, err)
+		}
+	}
+}
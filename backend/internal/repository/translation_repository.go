@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"village_project/internal/cache"
+	"village_project/internal/database"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrOriginalLanguage is returned when a translation targets the locale the item was written in
+var ErrOriginalLanguage = errors.New("locale is the item's original language")
+
+// TranslationRepository stores per-locale versions of news items and jobs
+type TranslationRepository struct {
+	DB        *pgxpool.Pool   // Primary, for writes
+	Pools     *database.Pools // Reads go to Pools.Reader (the replica when healthy)
+	NewsCache *cache.Cache    // shared with NewsRepository, so a translation edit clears both
+	JobsCache *cache.Cache    // shared with JobRepository
+}
+
+// NewTranslationRepository creates a new TranslationRepository
+func NewTranslationRepository(pools *database.Pools, newsCache, jobsCache *cache.Cache) *TranslationRepository {
+	return &TranslationRepository{DB: pools.Writer(), Pools: pools, NewsCache: newsCache, JobsCache: jobsCache}
+}
+
+const newsTranslationColumns = `news_id, locale, title, content, created_at, updated_at, updated_by`
+
+func scanNewsTranslation(row pgx.Row) (models.NewsTranslation, error) {
+	var t models.NewsTranslation
+	err := row.Scan(&t.NewsID, &t.Locale, &t.Title, &t.Content, &t.CreatedAt, &t.UpdatedAt, &t.UpdatedBy)
+	return t, err
+}
+
+const jobTranslationColumns = `job_id, locale, title, description, location, payment_details,
+		       created_at, updated_at, updated_by`
+
+func scanJobTranslation(row pgx.Row) (models.JobTranslation, error) {
+	var t models.JobTranslation
+	err := row.Scan(&t.JobID, &t.Locale, &t.Title, &t.Description, &t.Location, &t.PaymentDetails,
+		&t.CreatedAt, &t.UpdatedAt, &t.UpdatedBy)
+	return t, err
+}
+
+// checkOriginalLanguage locks the parent row so it cannot be deleted mid-upsert and
+// rejects a translation into its original language. table is a trusted constant.
+func checkOriginalLanguage(ctx context.Context, tx pgx.Tx, table, id, locale string) error {
+	var original string
+	err := tx.QueryRow(ctx, `SELECT language FROM public.`+table+` WHERE id = $1 AND deleted_at IS NULL FOR SHARE;`, id).Scan(&original)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if original == locale {
+		return ErrOriginalLanguage
+	}
+	return nil
+}
+
+// NewsTranslations loads the translations of the given news items, keyed by news ID
+func (r *TranslationRepository) NewsTranslations(ctx context.Context, newsIDs []string) (map[string][]models.NewsTranslation, error) {
+	v, err := cache.Load(r.NewsCache, "translations:"+strings.Join(newsIDs, ","), func() (map[string][]models.NewsTranslation, error) {
+		return r.queryNewsTranslations(ctx, newsIDs)
+	})
+	return v, err
+}
+
+// queryNewsTranslations queries the database; NewsTranslations is the cached entry point
+func (r *TranslationRepository) queryNewsTranslations(ctx context.Context, newsIDs []string) (map[string][]models.NewsTranslation, error) {
+	byNews := map[string][]models.NewsTranslation{}
+	if len(newsIDs) == 0 {
+		return byNews, nil
+	}
+	rows, err := r.Pools.Reader(ctx, "news").Query(ctx, `
+		SELECT `+newsTranslationColumns+`
+		FROM public.news_translations
+		WHERE news_id = ANY($1::uuid[])
+		ORDER BY news_id, locale;
+	`, newsIDs)
+	if err != nil {
+		log.Printf("Error querying news translations: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanNewsTranslation(rows)
+		if err != nil {
+			log.Printf("Error scanning news translation row: %v\n", err)
+			continue
+		}
+		byNews[t.NewsID] = append(byNews[t.NewsID], t)
+	}
+	return byNews, rows.Err()
+}
+
+// UpsertNewsTranslation adds or replaces one locale of a news item. The original row is
+// left untouched. It returns ErrNotFound or ErrOriginalLanguage.
+func (r *TranslationRepository) UpsertNewsTranslation(ctx context.Context, newsID, locale string, req models.UpsertNewsTranslationRequest, editorID *string) (models.NewsTranslation, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.NewsTranslation{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkOriginalLanguage(ctx, tx, "news", newsID, locale); err != nil {
+		return models.NewsTranslation{}, err
+	}
+	t, err := scanNewsTranslation(tx.QueryRow(ctx, `
+		INSERT INTO public.news_translations (news_id, locale, title, content, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (news_id, locale) DO UPDATE
+		SET title = EXCLUDED.title, content = EXCLUDED.content,
+		    updated_by = EXCLUDED.updated_by, updated_at = now()
+		RETURNING `+newsTranslationColumns+`;
+	`, newsID, locale, req.Title, req.Content, editorID))
+	if err != nil {
+		log.Printf("Error saving %s translation of news %s: %v\n", locale, newsID, err)
+		return models.NewsTranslation{}, err
+	}
+	if err := refreshNewsSearchTerms(ctx, tx, newsID); err != nil {
+		return models.NewsTranslation{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.NewsTranslation{}, err
+	}
+	r.NewsCache.Clear()
+	return t, nil
+}
+
+// DeleteNewsTranslation removes one locale of a news item
+func (r *TranslationRepository) DeleteNewsTranslation(ctx context.Context, newsID, locale string) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM public.news_translations WHERE news_id = $1 AND locale = $2;`, newsID, locale)
+	if err != nil {
+		log.Printf("Error deleting %s translation of news %s: %v\n", locale, newsID, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if err := refreshNewsSearchTerms(ctx, tx, newsID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.NewsCache.Clear()
+	return nil
+}
+
+// JobTranslations loads the translations of the given jobs, keyed by job ID
+func (r *TranslationRepository) JobTranslations(ctx context.Context, jobIDs []string) (map[string][]models.JobTranslation, error) {
+	v, err := cache.Load(r.JobsCache, "translations:"+strings.Join(jobIDs, ","), func() (map[string][]models.JobTranslation, error) {
+		return r.queryJobTranslations(ctx, jobIDs)
+	})
+	return v, err
+}
+
+// queryJobTranslations queries the database; JobTranslations is the cached entry point
+func (r *TranslationRepository) queryJobTranslations(ctx context.Context, jobIDs []string) (map[string][]models.JobTranslation, error) {
+	byJob := map[string][]models.JobTranslation{}
+	if len(jobIDs) == 0 {
+		return byJob, nil
+	}
+	rows, err := r.Pools.Reader(ctx, "jobs").Query(ctx, `
+		SELECT `+jobTranslationColumns+`
+		FROM public.job_translations
+		WHERE job_id = ANY($1::uuid[])
+		ORDER BY job_id, locale;
+	`, jobIDs)
+	if err != nil {
+		log.Printf("Error querying job translations: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanJobTranslation(rows)
+		if err != nil {
+			log.Printf("Error scanning job translation row: %v\n", err)
+			continue
+		}
+		byJob[t.JobID] = append(byJob[t.JobID], t)
+	}
+	return byJob, rows.Err()
+}
+
+// UpsertJobTranslation adds or replaces one locale of a job. The original row is left
+// untouched. It returns ErrNotFound or ErrOriginalLanguage.
+func (r *TranslationRepository) UpsertJobTranslation(ctx context.Context, jobID, locale string, req models.UpsertJobTranslationRequest, editorID *string) (models.JobTranslation, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return models.JobTranslation{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkOriginalLanguage(ctx, tx, "jobs", jobID, locale); err != nil {
+		return models.JobTranslation{}, err
+	}
+	t, err := scanJobTranslation(tx.QueryRow(ctx, `
+		INSERT INTO public.job_translations (job_id, locale, title, description, location, payment_details, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (job_id, locale) DO UPDATE
+		SET title = EXCLUDED.title, description = EXCLUDED.description, location = EXCLUDED.location,
+		    payment_details = EXCLUDED.payment_details, updated_by = EXCLUDED.updated_by, updated_at = now()
+		RETURNING `+jobTranslationColumns+`;
+	`, jobID, locale, req.Title, req.Description, req.Location, req.PaymentDetails, editorID))
+	if err != nil {
+		log.Printf("Error saving %s translation of job %s: %v\n", locale, jobID, err)
+		return models.JobTranslation{}, err
+	}
+	if err := refreshJobSearchTerms(ctx, tx, jobID); err != nil {
+		return models.JobTranslation{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.JobTranslation{}, err
+	}
+	r.JobsCache.Clear()
+	return t, nil
+}
+
+// DeleteJobTranslation removes one locale of a job
+func (r *TranslationRepository) DeleteJobTranslation(ctx context.Context, jobID, locale string) error {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM public.job_translations WHERE job_id = $1 AND locale = $2;`, jobID, locale)
+	if err != nil {
+		log.Printf("Error deleting %s translation of job %s: %v\n", locale, jobID, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if err := refreshJobSearchTerms(ctx, tx, jobID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	r.JobsCache.Clear()
+	return nil
+}
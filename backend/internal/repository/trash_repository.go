@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"village_project/internal/cache"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TrashCollections are the soft-deleted tables behind the trash, keyed by collection
+// name (which is also the table and read-cache name). A new content table joins the trash
+// by adding deleted_at and deleted_by columns, filtering deleted_at IS NULL in its reads
+// and being listed here.
+var TrashCollections = []string{"news", "jobs"}
+
+// TrashRepository lists, restores and purges deleted content across collections
+type TrashRepository struct {
+	DB        *pgxpool.Pool
+	Caches    *cache.Registry // Restored items go back into cached lists
+	Retention time.Duration   // How long deleted items are kept
+}
+
+// NewTrashRepository creates a new TrashRepository
+func NewTrashRepository(db *pgxpool.Pool, caches *cache.Registry, retention time.Duration) *TrashRepository {
+	return &TrashRepository{DB: db, Caches: caches, Retention: retention}
+}
+
+// List returns the deleted items of one collection, or of all when collection is
+// empty, most recently deleted first
+func (r *TrashRepository) List(ctx context.Context, collection string) ([]models.TrashItem, error) {
+	var selects []string
+	for _, name := range TrashCollections {
+		if collection == "" || collection == name {
+			selects = append(selects, fmt.Sprintf(
+				`SELECT '%s', id::text, title, deleted_at, deleted_by::text FROM public.%s WHERE deleted_at IS NOT NULL`, name, name))
+		}
+	}
+	if len(selects) == 0 {
+		return nil, fmt.Errorf("unknown collection %q", collection)
+	}
+	rows, err := r.DB.Query(ctx, strings.Join(selects, "\nUNION ALL\n")+"\nORDER BY 4 DESC;")
+	if err != nil {
+		log.Printf("Error listing trash: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.TrashItem{}
+	for rows.Next() {
+		var item models.TrashItem
+		if err := rows.Scan(&item.Collection, &item.ID, &item.Title, &item.DeletedAt, &item.DeletedBy); err != nil {
+			log.Printf("Error scanning trash row: %v\n", err)
+			return nil, err
+		}
+		item.PurgeAt = item.DeletedAt.Add(r.Retention)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Restore undeletes an item; syncing clients receive it again as a change. It returns
+// ErrNotFound if the item is not in the trash (never deleted, or already purged).
+func (r *TrashRepository) Restore(ctx context.Context, collection, id string) error {
+	if !slices.Contains(TrashCollections, collection) {
+		return ErrNotFound
+	}
+	tag, err := r.DB.Exec(ctx, `
+		UPDATE public.`+collection+` SET deleted_at = NULL, deleted_by = NULL, updated_at = now()
+		WHERE id = $1 AND deleted_at IS NOT NULL;
+	`, id)
+	if err != nil {
+		log.Printf("Error restoring %s %s: %v\n", collection, id, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	r.Caches.Invalidate(collection)
+	return nil
+}
+
+// Purge removes items deleted longer ago than the retention, one collection per
+// transaction, and records the newest change it removed so sync tokens older than that
+// are answered with a full snapshot. It returns the number purged per collection.
+func (r *TrashRepository) Purge(ctx context.Context) (map[string]int, error) {
+	cutoff := time.Now().Add(-r.Retention)
+	purged := make(map[string]int, len(TrashCollections))
+	for _, collection := range TrashCollections {
+		// max() over the xid8 as a number: not every supported server has max(xid8)
+		var n int
+		err := r.DB.QueryRow(ctx, `
+			WITH gone AS (
+				DELETE FROM public.`+collection+`
+				WHERE deleted_at IS NOT NULL AND deleted_at < $1
+				RETURNING change_xid
+			), logged AS (
+				INSERT INTO public.trash_purges (collection, purged, max_change_xid)
+				SELECT $2, count(*), max(change_xid::text::numeric)::text::xid8 FROM gone
+				HAVING count(*) > 0
+			)
+			SELECT count(*) FROM gone;
+		`, cutoff, collection).Scan(&n)
+		if err != nil {
+			log.Printf("Error purging %s trash: %v\n", collection, err)
+			return purged, err
+		}
+		purged[collection] = n
+	}
+	return purged, nil
+}
+
+// RunPurge purges the trash now and then every interval until ctx is cancelled
+func (r *TrashRepository) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := r.Purge(ctx); err == nil {
+			for collection, n := range purged {
+				if n > 0 {
+					log.Printf("Purged %d %s from the trash", n, collection)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
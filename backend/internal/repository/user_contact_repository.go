@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UserContactRepository reads contact details for Supabase Auth users.
+// The backend connects with a role that can read the auth schema.
+type UserContactRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewUserContactRepository creates a new instance of UserContactRepository
+func NewUserContactRepository(db *pgxpool.Pool) *UserContactRepository {
+	return &UserContactRepository{DB: db}
+}
+
+// GetContact returns the name, email and phone for a user ID
+func (r *UserContactRepository) GetContact(ctx context.Context, userID string) (models.UserContact, error) {
+	contact := models.UserContact{UserID: userID}
+	err := r.DB.QueryRow(ctx, `
+		SELECT coalesce(raw_user_meta_data->>'full_name', raw_user_meta_data->>'name', ''),
+		       nullif(email, ''), nullif(phone, '')
+		FROM auth.users
+		WHERE id = $1;
+	`, userID).Scan(&contact.Name, &contact.Email, &contact.Phone)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return contact, ErrNotFound
+		}
+		log.Printf("Error querying contact for user %s: %v\n", userID, err)
+		return contact, err
+	}
+	return contact, nil
+}
+
+// ListReachableContacts returns every user with an email address or phone number
+func (r *UserContactRepository) ListReachableContacts(ctx context.Context) ([]models.UserContact, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, coalesce(raw_user_meta_data->>'full_name', raw_user_meta_data->>'name', ''),
+		       nullif(email, ''), nullif(phone, '')
+		FROM auth.users
+		WHERE deleted_at IS NULL AND (coalesce(email, '') <> '' OR coalesce(phone, '') <> '');
+	`)
+	if err != nil {
+		log.Printf("Error querying reachable contacts: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	contacts := []models.UserContact{}
+	for rows.Next() {
+		var c models.UserContact
+		if err := rows.Scan(&c.UserID, &c.Name, &c.Email, &c.Phone); err != nil {
+			log.Printf("Error scanning contact row: %v\n", err)
+			continue
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, rows.Err()
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"village_project/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WebhookRepository stores webhook subscriptions and their delivery log
+type WebhookRepository struct {
+	DB *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+const webhookColumns = `id, created_at, updated_at, name, url, event_types, active, created_by`
+
+func scanWebhook(row pgx.Row) (models.WebhookSubscription, error) {
+	var w models.WebhookSubscription
+	err := row.Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt, &w.Name, &w.URL, &w.EventTypes, &w.Active, &w.CreatedBy)
+	return w, err
+}
+
+const webhookDeliveryColumns = `id, created_at, subscription_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at, last_attempt_at, response_code, response_body, last_error, delivered_at, redelivery_of`
+
+func scanWebhookDelivery(row pgx.Row) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := row.Scan(&d.ID, &d.CreatedAt, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseCode, &d.ResponseBody, &d.LastError, &d.DeliveredAt, &d.RedeliveryOf)
+	return d, err
+}
+
+// CreateSubscription registers a webhook. The returned value includes the secret.
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, req models.CreateWebhookRequest, secret string, createdBy *string) (models.WebhookSubscription, error) {
+	w, err := scanWebhook(r.DB.QueryRow(ctx, `
+		INSERT INTO public.webhook_subscriptions (name, url, event_types, secret, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+webhookColumns+`;
+	`, req.Name, req.URL, req.EventTypes, secret, createdBy))
+	if err != nil {
+		log.Printf("Error inserting webhook subscription: %v\n", err)
+		return w, err
+	}
+	w.Secret = secret
+	return w, nil
+}
+
+// ListSubscriptions returns every webhook, newest first
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+webhookColumns+` FROM public.webhook_subscriptions ORDER BY created_at DESC;`)
+	if err != nil {
+		log.Printf("Error querying webhook subscriptions: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.WebhookSubscription{}
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			log.Printf("Error scanning webhook row: %v\n", err)
+			continue
+		}
+		list = append(list, w)
+	}
+	return list, rows.Err()
+}
+
+// GetSubscription fetches one webhook without its secret
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id string) (models.WebhookSubscription, error) {
+	w, err := scanWebhook(r.DB.QueryRow(ctx, `SELECT `+webhookColumns+` FROM public.webhook_subscriptions WHERE id = $1;`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return w, ErrNotFound
+	}
+	return w, err
+}
+
+// UpdateSubscription applies the non-nil fields of req
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, id string, req models.UpdateWebhookRequest) (models.WebhookSubscription, error) {
+	w, err := scanWebhook(r.DB.QueryRow(ctx, `
+		UPDATE public.webhook_subscriptions
+		SET name = coalesce($2, name),
+		    url = coalesce($3, url),
+		    event_types = coalesce($4, event_types),
+		    active = coalesce($5, active),
+		    updated_at = now()
+		WHERE id = $1
+		RETURNING `+webhookColumns+`;
+	`, id, req.Name, req.URL, req.EventTypes, req.Active))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return w, ErrNotFound
+	}
+	if err != nil {
+		log.Printf("Error updating webhook %s: %v\n", id, err)
+	}
+	return w, err
+}
+
+// DeleteSubscription removes a webhook and its delivery log
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM public.webhook_subscriptions WHERE id = $1;`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// EnqueueEvent queues a delivery of payload for every active subscription to eventType,
+// or only for subscriptionID when it is set (used for ping events). Subscriptions that
+// already have this event are skipped. Returns the number queued.
+func (r *WebhookRepository) EnqueueEvent(ctx context.Context, eventID, eventType string, payload []byte, subscriptionID *string) (int, error) {
+	tag, err := r.DB.Exec(ctx, `
+		INSERT INTO public.webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT id, $1, $2, $3
+		FROM public.webhook_subscriptions
+		WHERE CASE WHEN $4::uuid IS NULL THEN active AND $2 = ANY (event_types) ELSE id = $4::uuid END
+		ON CONFLICT (subscription_id, event_id) WHERE redelivery_of IS NULL DO NOTHING;
+	`, eventID, eventType, payload, subscriptionID)
+	if err != nil {
+		log.Printf("Error queueing webhook event %s: %v\n", eventType, err)
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ClaimDueDeliveries marks up to limit due deliveries as "sending" and returns them with
+// the subscription URL and secret. Deliveries stuck in "sending" for ten minutes are retried.
+// Due deliveries for inactive subscriptions are cancelled rather than sent, except pings
+// and redeliveries, which an admin asked for explicitly.
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error) {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.webhook_deliveries d
+		SET status = 'cancelled', claimed_at = NULL
+		FROM public.webhook_subscriptions s
+		WHERE s.id = d.subscription_id AND NOT s.active
+		  AND (d.status = 'pending' OR (d.status = 'sending' AND d.claimed_at < now() - interval '10 minutes'))
+		  AND d.event_type <> 'ping' AND d.redelivery_of IS NULL;
+	`)
+	if err != nil {
+		log.Printf("Error cancelling webhook deliveries for inactive subscriptions: %v\n", err)
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(ctx, `
+		WITH due AS (
+			SELECT d.id FROM public.webhook_deliveries d
+			JOIN public.webhook_subscriptions s ON s.id = d.subscription_id
+			WHERE ((d.status = 'pending' AND d.next_attempt_at <= now())
+			    OR (d.status = 'sending' AND d.claimed_at < now() - interval '10 minutes'))
+			  AND (s.active OR d.event_type = 'ping' OR d.redelivery_of IS NOT NULL)
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE public.webhook_deliveries d
+		SET status = 'sending', claimed_at = now()
+		FROM due, public.webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.created_at, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+		          d.next_attempt_at, d.last_attempt_at, d.response_code, d.response_body, d.last_error,
+		          d.delivered_at, d.redelivery_of, s.url, s.secret;
+	`, limit)
+	if err != nil {
+		log.Printf("Error claiming webhook deliveries: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.CreatedAt, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.LastAttemptAt, &d.ResponseCode, &d.ResponseBody, &d.LastError,
+			&d.DeliveredAt, &d.RedeliveryOf, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, d)
+	}
+	return claimed, rows.Err()
+}
+
+// RecordAttempt stores the outcome of one attempt. On failure the delivery is
+// rescheduled for retryAt, or marked failed when retryAt is nil.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id string, responseCode *int, responseBody, attemptErr string, retryAt *time.Time) error {
+	status := models.WebhookDeliverySucceeded
+	if attemptErr != "" {
+		status = models.WebhookDeliveryFailed
+		if retryAt != nil {
+			status = models.WebhookDeliveryPending
+		}
+	}
+	_, err := r.DB.Exec(ctx, `
+		UPDATE public.webhook_deliveries
+		SET status = $2,
+		    attempts = attempts + 1,
+		    last_attempt_at = now(),
+		    response_code = $3,
+		    response_body = nullif($4, ''),
+		    last_error = nullif($5, ''),
+		    next_attempt_at = coalesce($6, next_attempt_at),
+		    delivered_at = CASE WHEN $2 = 'succeeded' THEN now() ELSE NULL END,
+		    claimed_at = NULL
+		WHERE id = $1;
+	`, id, status, responseCode, responseBody, attemptErr, retryAt)
+	if err != nil {
+		log.Printf("Error recording webhook attempt for %s: %v\n", id, err)
+	}
+	return err
+}
+
+// ListDeliveries returns a subscription's delivery log, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, filter models.WebhookDeliveryFilter) ([]models.WebhookDelivery, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM public.webhook_deliveries
+		WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3;
+	`, subscriptionID, filter.Status, limit)
+	if err != nil {
+		log.Printf("Error querying webhook deliveries: %v\n", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			log.Printf("Error scanning webhook delivery row: %v\n", err)
+			continue
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// Redeliver queues a fresh copy of an earlier delivery, keeping the original in the log
+func (r *WebhookRepository) Redeliver(ctx context.Context, deliveryID string) (models.WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(r.DB.QueryRow(ctx, `
+		INSERT INTO public.webhook_deliveries (subscription_id, event_id, event_type, payload, redelivery_of)
+		SELECT subscription_id, event_id, event_type, payload, id
+		FROM public.webhook_deliveries
+		WHERE id = $1
+		RETURNING `+webhookDeliveryColumns+`;
+	`, deliveryID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return d, ErrNotFound
+	}
+	if err != nil {
+		log.Printf("Error redelivering webhook delivery %s: %v\n", deliveryID, err)
+	}
+	return d, err
+}
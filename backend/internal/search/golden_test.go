@@ -0,0 +1,86 @@
+package search
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestGolden runs the cases in testdata/golden.tsv. Each non-comment line is
+// tab-separated: an operation, its input(s), and the expected output. Fields may use
+// Go escapes such as \u0C46 to spell decomposed text.
+//
+//	nfc      input              normalized
+//	tokens   input              token | token | ...
+//	latin    telugu             romanization
+//	telugu   latin              telugu script
+//	key      token              key
+//	match    document   query   yes|no
+func TestGolden(t *testing.T) {
+	f, err := os.Open("testdata/golden.tsv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cases := 0
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		for i, field := range fields {
+			unquoted, err := strconv.Unquote(`"` + strings.ReplaceAll(field, `"`, `\"`) + `"`)
+			if err != nil {
+				t.Fatalf("line %d: bad escape in %q: %v", lineNo, field, err)
+			}
+			fields[i] = unquoted
+		}
+
+		want, got := "", ""
+		switch {
+		case fields[0] == "match" && len(fields) == 4:
+			want = fields[3]
+			got = "no"
+			if Match(Terms(fields[1]), QueryTerms(fields[2])) {
+				got = "yes"
+			}
+		case len(fields) == 3:
+			want = fields[2]
+			switch fields[0] {
+			case "nfc":
+				got = Normalize(fields[1])
+			case "tokens":
+				got = strings.Join(Tokenize(fields[1]), " | ")
+			case "latin":
+				got = ToLatin(fields[1])
+			case "telugu":
+				got = ToTelugu(fields[1])
+			case "key":
+				got = Key(fields[1])
+			default:
+				t.Fatalf("line %d: unknown operation %q", lineNo, fields[0])
+			}
+		default:
+			t.Fatalf("line %d: wrong number of fields for %q", lineNo, fields[0])
+		}
+
+		cases++
+		if got != want {
+			t.Errorf("line %d: %s %q: got %q, want %q", lineNo, fields[0], fields[1], got, want)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if cases == 0 {
+		t.Fatal("golden corpus has no cases")
+	}
+	t.Logf("%d golden cases", cases)
+}
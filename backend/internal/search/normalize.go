@@ -0,0 +1,183 @@
+// Package search turns free text in Telugu script, romanized Telugu or English into
+// comparable search terms. Indexing and querying go through the same steps:
+//
+//  1. Normalize: Unicode NFC, lower case, zero-width joiners removed, Telugu digits to ASCII.
+//  2. Tokenize: runs of letters, combining marks and digits, so a Telugu consonant keeps
+//     its vowel signs and virama.
+//  3. Key: Telugu tokens are transliterated to Latin, then every token is folded to a
+//     phonetic key (long vowels, aspirates and doubled letters collapsed), so "పని",
+//     "pani" and "paani" all become "pani", and "కూలీ", "koolie" and "coolie" become "kuli".
+//  4. Synonyms: at query time each key is widened to its synonym group ("pani" also
+//     matches "work", "job" and "కామ్").
+//
+// Documents are indexed as the set of their keys (see Terms); a query matches when every
+// query token matches at least one alternative (see QueryTerms).
+package search
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	zwnj = '\u200c' // Zero-width non-joiner, used to control Telugu conjunct rendering
+	zwj  = '\u200d'
+)
+
+// stopWords are dropped from queries and documents; they only make AND-queries fail
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "in": true, "on": true,
+	"at": true, "for": true, "to": true, "is": true, "with": true, "or": true,
+}
+
+// Normalize applies NFC, lower-cases, drops zero-width joiners and maps Telugu digits to ASCII
+func Normalize(s string) string {
+	s = norm.NFC.String(s)
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == zwnj || r == zwj:
+			continue
+		case r >= '\u0C66' && r <= '\u0C6F':
+			b.WriteRune('0' + (r - '\u0C66'))
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// isWordRune reports whether r belongs inside a token. Marks are included so Telugu
+// vowel signs (e.g. ి) and the virama (్) stay with their consonant.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r)
+}
+
+// Tokenize normalizes s and splits it into words. A token never mixes Telugu and Latin
+// script ("ఉపాధిjobs" yields two tokens); stop words and single Latin letters are dropped.
+func Tokenize(s string) []string {
+	s = Normalize(s)
+	var tokens []string
+	var cur []rune
+	curTelugu := false
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		tok := string(cur)
+		cur = cur[:0]
+		if stopWords[tok] || (len(tok) == 1 && tok[0] < 0x80 && !unicode.IsDigit(rune(tok[0]))) {
+			return
+		}
+		tokens = append(tokens, tok)
+	}
+	for _, r := range s {
+		if !isWordRune(r) {
+			flush()
+			continue
+		}
+		telugu := IsTelugu(r)
+		// Digits and marks join whatever token they follow
+		if len(cur) > 0 && telugu != curTelugu && !unicode.IsDigit(r) && !unicode.IsMark(r) {
+			flush()
+		}
+		if len(cur) == 0 || (!unicode.IsDigit(r) && !unicode.IsMark(r)) {
+			curTelugu = telugu
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return tokens
+}
+
+// IsTelugu reports whether r is in the Telugu Unicode block
+func IsTelugu(r rune) bool {
+	return r >= 0x0C00 && r <= 0x0C7F
+}
+
+func hasTelugu(s string) bool {
+	return strings.ContainsFunc(s, IsTelugu)
+}
+
+// Key folds a single token to its phonetic search key
+func Key(token string) string {
+	token = Normalize(token)
+	if hasTelugu(token) {
+		token = toLatin(token, true)
+	}
+	return foldLatin(token)
+}
+
+// stems returns the key with common Telugu plural endings removed, so "కూలీలు" (kulilu)
+// also indexes as "kuli" and "panulu" as "pani"
+func stems(key string) []string {
+	if len(key) <= 4 || !strings.HasSuffix(key, "lu") {
+		return nil
+	}
+	out := []string{key[:len(key)-2]}
+	if strings.HasSuffix(key, "ulu") {
+		out = append(out, key[:len(key)-3]+"i")
+	}
+	return out
+}
+
+// Terms returns the sorted, de-duplicated keys of all the given texts, for storing
+// alongside a document
+func Terms(texts ...string) []string {
+	terms := []string{}
+	for _, text := range texts {
+		for _, tok := range Tokenize(text) {
+			k := Key(tok)
+			if k == "" {
+				continue
+			}
+			for _, term := range append([]string{k}, stems(k)...) {
+				if !slices.Contains(terms, term) {
+					terms = append(terms, term)
+				}
+			}
+		}
+	}
+	slices.Sort(terms)
+	return terms
+}
+
+// QueryTerms turns a query into one group of alternative keys per query word. A document
+// matches when each group shares at least one key with the document's Terms.
+func QueryTerms(query string) [][]string {
+	var groups [][]string
+	for _, tok := range Tokenize(query) {
+		k := Key(tok)
+		if k == "" {
+			continue
+		}
+		alternatives := Synonyms(k)
+		for _, stem := range stems(k) {
+			alternatives = append(alternatives, Synonyms(stem)...)
+		}
+		slices.Sort(alternatives)
+		groups = append(groups, slices.Compact(alternatives))
+	}
+	return groups
+}
+
+// Match reports whether a document with the given terms satisfies the query groups
+func Match(terms []string, groups [][]string) bool {
+	for _, alternatives := range groups {
+		found := false
+		for _, alt := range alternatives {
+			if _, ok := slices.BinarySearch(terms, alt); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"slices"
+)
+
+// jobSynonyms groups words residents use for the same thing in English, romanized
+// Telugu and Telugu script. Spelling variants need not be listed: they share a key.
+var jobSynonyms = [][]string{
+	{"pani", "పని", "work", "works", "job", "jobs", "udyogam", "ఉద్యోగం", "upadhi", "ఉపాధి", "employment"},
+	{"kooli", "కూలీ", "కూలి", "coolie", "labour", "labor", "labourer", "laborer", "worker", "workers"},
+	{"jeetham", "జీతం", "salary", "wage", "wages", "pay", "payment"},
+	{"roju", "రోజు", "daily", "day", "days"},
+	{"vyavasayam", "వ్యవసాయం", "agriculture", "farming", "farm", "polam", "పొలం", "field", "fields"},
+	{"vari", "వరి", "vadlu", "వడ్లు", "paddy", "rice"},
+	{"kotha", "కోత", "harvest", "harvesting", "cutting"},
+	{"natlu", "నాట్లు", "transplanting", "planting", "sowing"},
+	{"driver", "డ్రైవర్", "driving"},
+	{"tractor", "ట్రాక్టర్"},
+	{"mestri", "మేస్త్రి", "mason", "masonry", "tapi", "తాపీ"},
+	{"vadrangi", "వడ్రంగి", "carpenter", "carpentry"},
+	{"electrician", "ఎలక్ట్రీషియన్", "wiring"},
+	{"plumber", "ప్లంబర్", "plumbing"},
+	{"vanta", "వంట", "cook", "cooking"},
+	{"shubhram", "శుభ్రం", "cleaning", "cleaner", "sweeper", "sweeping"},
+	{"darji", "దర్జీ", "tailor", "tailoring", "kuttu", "కుట్టు", "stitching"},
+	{"pasuvulu", "పశువులు", "cattle", "livestock"},
+	{"hamali", "హమాలీ", "loading", "unloading", "porter"},
+	{"sanchulu", "సంచులు", "sanchi", "సంచి", "bag", "bags"},
+	{"teacher", "teaching", "upadhyayudu", "ఉపాధ్యాయుడు"},
+	{"kapala", "కాపలా", "watchman", "guard", "security"},
+	{"painter", "painting", "rangulu", "రంగులు"},
+	{"avasaram", "అవసరం", "urgent", "needed", "required"},
+}
+
+// synonymIndex maps each key to every key of its group(s), sorted
+var synonymIndex = buildSynonymIndex(jobSynonyms)
+
+func buildSynonymIndex(groups [][]string) map[string][]string {
+	index := map[string][]string{}
+	for _, group := range groups {
+		var keys []string
+		for _, word := range group {
+			for _, tok := range Tokenize(word) {
+				if k := Key(tok); k != "" && !slices.Contains(keys, k) {
+					keys = append(keys, k)
+				}
+			}
+		}
+		for _, k := range keys {
+			merged := append(index[k], keys...)
+			slices.Sort(merged)
+			index[k] = slices.Compact(merged)
+		}
+	}
+	return index
+}
+
+// Synonyms returns the keys that should match key, including key itself
+func Synonyms(key string) []string {
+	if group, ok := synonymIndex[key]; ok {
+		return group
+	}
+	return []string{key}
+}
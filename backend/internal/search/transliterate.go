@@ -0,0 +1,295 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Romanization used by ToLatin and accepted by ToTelugu. It is the plain-ASCII style
+// residents type: long vowels doubled (aa, ii, uu, ee, oo), aspirates with h, and
+// retroflex consonants written like the dental ones (ట and త are both "t").
+var teluguConsonants = map[rune]string{
+	'క': "k", 'ఖ': "kh", 'గ': "g", 'ఘ': "gh", 'ఙ': "ng",
+	'చ': "ch", 'ఛ': "chh", 'జ': "j", 'ఝ': "jh", 'ఞ': "ny",
+	'ట': "t", 'ఠ': "th", 'డ': "d", 'ఢ': "dh", 'ణ': "n",
+	'త': "t", 'థ': "th", 'ద': "d", 'ధ': "dh", 'న': "n",
+	'ప': "p", 'ఫ': "ph", 'బ': "b", 'భ': "bh", 'మ': "m",
+	'య': "y", 'ర': "r", 'ఱ': "r", 'ల': "l", 'ళ': "l", 'ఴ': "l", 'వ': "v",
+	'శ': "sh", 'ష': "sh", 'స': "s", 'హ': "h",
+}
+
+var teluguVowels = map[rune]string{
+	'అ': "a", 'ఆ': "aa", 'ఇ': "i", 'ఈ': "ii", 'ఉ': "u", 'ఊ': "uu",
+	'ఋ': "ru", 'ౠ': "ruu", 'ఌ': "lu", 'ౡ': "luu",
+	'ఎ': "e", 'ఏ': "ee", 'ఐ': "ai", 'ఒ': "o", 'ఓ': "oo", 'ఔ': "au",
+}
+
+var teluguVowelSigns = map[rune]string{
+	'ా': "aa", 'ి': "i", 'ీ': "ii", 'ు': "u", 'ూ': "uu", 'ృ': "ru", 'ౄ': "ruu",
+	'ె': "e", 'ే': "ee", 'ై': "ai", 'ొ': "o", 'ో': "oo", 'ౌ': "au",
+}
+
+const (
+	virama      = '్'
+	anusvara    = 'ం'
+	visarga     = 'ః'
+	candrabindu = 'ఁ'
+)
+
+// labials take an "m" for a preceding anusvara; other stops take "n"
+var labials = map[rune]bool{'ప': true, 'ఫ': true, 'బ': true, 'భ': true, 'మ': true}
+
+// ToLatin romanizes Telugu script. Characters outside the Telugu block pass through.
+func ToLatin(s string) string {
+	return toLatin(s, false)
+}
+
+// toLatin with forKey set writes ē and ō as "e" and "o", the way they are usually typed
+// ("mestri", "kotha"), so that foldLatin's reading of ee/oo as ī/ū does not apply to them.
+func toLatin(s string, forKey bool) string {
+	runes := []rune(norm.NFC.String(s))
+	shorten := func(roman string) string {
+		if forKey && (roman == "ee" || roman == "oo") {
+			return roman[:1]
+		}
+		return roman
+	}
+	var b strings.Builder
+	inherent := false // A consonant is waiting for its vowel
+	for i, r := range runes {
+		if cons, ok := teluguConsonants[r]; ok {
+			if inherent {
+				b.WriteString("a")
+			}
+			b.WriteString(cons)
+			inherent = true
+			continue
+		}
+		if sign, ok := teluguVowelSigns[r]; ok {
+			b.WriteString(shorten(sign))
+			inherent = false
+			continue
+		}
+		if r == virama {
+			inherent = false
+			continue
+		}
+		if inherent {
+			b.WriteString("a")
+			inherent = false
+		}
+		switch {
+		case teluguVowels[r] != "":
+			b.WriteString(shorten(teluguVowels[r]))
+		case r == anusvara:
+			next := rune(0)
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+			if next >= 'క' && next <= 'న' && !labials[next] {
+				b.WriteString("n")
+			} else {
+				b.WriteString("m")
+			}
+		case r == visarga:
+			b.WriteString("h")
+		case IsTelugu(r):
+			// Candrabindu, nukta, length marks, avagraha: no sound of their own here
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if inherent {
+		b.WriteString("a")
+	}
+	return b.String()
+}
+
+// Latin sequences ToTelugu recognises, longest first
+type latinConsonant struct {
+	latin  string
+	telugu string
+}
+
+var latinConsonants = []latinConsonant{
+	{"chh", "ఛ"}, {"ch", "చ"}, {"kh", "ఖ"}, {"gh", "ఘ"}, {"jh", "ఝ"}, {"th", "థ"}, {"dh", "ధ"},
+	{"ph", "ఫ"}, {"bh", "భ"}, {"sh", "శ"}, {"x", "క్స"},
+	{"k", "క"}, {"g", "గ"}, {"c", "చ"}, {"j", "జ"}, {"t", "త"}, {"d", "ద"}, {"n", "న"},
+	{"p", "ప"}, {"b", "బ"}, {"m", "మ"}, {"y", "య"}, {"r", "ర"}, {"l", "ల"}, {"v", "వ"},
+	{"w", "వ"}, {"s", "స"}, {"h", "హ"}, {"f", "ఫ"}, {"z", "జ"}, {"q", "క"},
+}
+
+type latinVowel struct {
+	latin       string
+	independent string
+	sign        string // Empty for the inherent "a"
+}
+
+var latinVowels = []latinVowel{
+	{"aa", "ఆ", "ా"}, {"ai", "ఐ", "ై"}, {"au", "ఔ", "ౌ"}, {"ii", "ఈ", "ీ"}, {"ee", "ఏ", "ే"},
+	{"uu", "ఊ", "ూ"}, {"oo", "ఓ", "ో"},
+	{"a", "అ", ""}, {"i", "ఇ", "ి"}, {"u", "ఉ", "ు"}, {"e", "ఎ", "ె"}, {"o", "ఒ", "ొ"},
+}
+
+func matchConsonant(s string) (latinConsonant, bool) {
+	for _, c := range latinConsonants {
+		if strings.HasPrefix(s, c.latin) {
+			return c, true
+		}
+	}
+	return latinConsonant{}, false
+}
+
+func matchVowel(s string) (latinVowel, bool) {
+	for _, v := range latinVowels {
+		if strings.HasPrefix(s, v.latin) {
+			return v, true
+		}
+	}
+	return latinVowel{}, false
+}
+
+// ToTelugu writes romanized Telugu (the ToLatin scheme) in Telugu script. It is a
+// best-effort inverse: retroflex consonants come back as dental ones, an "m" or "n"
+// between a vowel and a different consonant becomes an anusvara, and a word-final
+// consonant takes a virama (except "m", which becomes an anusvara).
+func ToTelugu(s string) string {
+	s = expandMacrons(strings.ToLower(s))
+	var b strings.Builder
+	afterConsonant := false // Last output was a consonant without a vowel
+	afterVowel := false
+	for len(s) > 0 {
+		if v, ok := matchVowel(s); ok {
+			if afterConsonant {
+				b.WriteString(v.sign)
+			} else {
+				b.WriteString(v.independent)
+			}
+			s = s[len(v.latin):]
+			afterConsonant, afterVowel = false, true
+			continue
+		}
+		if c, ok := matchConsonant(s); ok {
+			rest := s[len(c.latin):]
+			if (c.latin == "m" || c.latin == "n") && afterVowel {
+				next, nextIsConsonant := matchConsonant(rest)
+				_, nextIsVowel := matchVowel(rest)
+				wordEnd := !nextIsVowel && !nextIsConsonant
+				if (nextIsConsonant && next.latin != c.latin) || (wordEnd && c.latin == "m") {
+					b.WriteRune(anusvara)
+					s = rest
+					afterConsonant, afterVowel = false, false
+					continue
+				}
+			}
+			if afterConsonant {
+				b.WriteRune(virama)
+			}
+			b.WriteString(c.telugu)
+			s = rest
+			afterConsonant, afterVowel = true, false
+			continue
+		}
+		// Anything else ends the word
+		if afterConsonant {
+			b.WriteRune(virama)
+		}
+		r := []rune(s)[0]
+		b.WriteRune(r)
+		s = s[len(string(r)):]
+		afterConsonant, afterVowel = false, false
+	}
+	if afterConsonant {
+		b.WriteRune(virama)
+	}
+	return b.String()
+}
+
+// expandMacrons rewrites ISO 15919 long vowels (ā, ī, ū, ē, ō) as doubled letters and
+// drops other diacritics, so "kūlī" reads like "kuulii"
+func expandMacrons(s string) string {
+	var b strings.Builder
+	var prev rune
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r == '\u0304' && strings.ContainsRune("aiueo", prev):
+			b.WriteRune(prev)
+		case unicode.Is(unicode.Mn, r):
+		default:
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// foldLatin reduces a Latin token to its phonetic key: diacritics dropped, aspirates and
+// sibilants merged, "c" read as "k" (except in "ch"), ee/oo read as the long i/u of
+// popular spelling, a final "ie"/"ey" read as "i", and doubled letters collapsed.
+func foldLatin(token string) string {
+	if strings.ContainsFunc(token, func(r rune) bool { return r >= 0x80 }) {
+		var stripped strings.Builder
+		for _, r := range norm.NFD.String(token) {
+			if !unicode.Is(unicode.Mn, r) {
+				stripped.WriteRune(r)
+			}
+		}
+		token = stripped.String()
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(token); i++ {
+		ch := token[i]
+		next := byte(0)
+		if i+1 < len(token) {
+			next = token[i+1]
+		}
+		switch {
+		case ch == 'c' && next == 'h':
+			b.WriteByte('c')
+			i++
+			if i+1 < len(token) && token[i+1] == 'h' {
+				i++ // "chh"
+			}
+		case ch == 'c':
+			b.WriteByte('k')
+		case next == 'h' && strings.IndexByte("kgjtdpbs", ch) >= 0:
+			b.WriteByte(ch)
+			i++
+		case ch == 'f':
+			b.WriteByte('p')
+		case ch == 'w':
+			b.WriteByte('v')
+		case ch == 'z':
+			b.WriteByte('j')
+		case ch == 'q':
+			b.WriteByte('k')
+		case ch == 'x':
+			b.WriteString("ks")
+		case ch == 'e' && next == 'e':
+			b.WriteByte('i')
+			i++
+		case ch == 'o' && next == 'o':
+			b.WriteByte('u')
+			i++
+		case (ch == 'i' && next == 'e' || ch == 'e' && next == 'y') && i+2 == len(token):
+			b.WriteByte('i')
+			i++
+		case ch < 0x80 && (ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9'):
+			b.WriteByte(ch)
+		}
+	}
+
+	// Collapse doubled letters: "paani" and "panni" both key to "pani"
+	folded := b.String()
+	var out strings.Builder
+	for i := 0; i < len(folded); i++ {
+		if i > 0 && folded[i] == folded[i-1] && !(folded[i] >= '0' && folded[i] <= '9') {
+			continue
+		}
+		out.WriteByte(folded[i])
+	}
+	return out.String()
+}
@@ -0,0 +1,190 @@
+// Package stream fans domain events out to Server-Sent Events clients.
+//
+// Events reach every server instance through Postgres LISTEN/NOTIFY (see Listener),
+// are kept in a short in-memory replay buffer for Last-Event-ID resume, and are
+// pushed to each client through a bounded channel. A client that falls behind by
+// more than its buffer is disconnected; the browser reconnects with Last-Event-ID
+// and catches up from the replay buffer.
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"village_project/internal/models"
+)
+
+// Stream event types sent to clients
+const (
+	EventJobCreated       = "job.created"
+	EventJobStatusChanged = "job.status_changed"
+	EventNewsPublished    = "news.published"
+	EventResync           = "resync" // Last-Event-ID is older than the replay buffer; refetch everything
+)
+
+// EventTypes lists the event types clients can filter on
+var EventTypes = []string{EventJobCreated, EventJobStatusChanged, EventNewsPublished}
+
+// streamType maps an outbox event type to the stream event type, or "" if it is not streamed
+func streamType(outboxType string) string {
+	switch outboxType {
+	case models.EventJobCreated:
+		return EventJobCreated
+	case models.EventJobOpened, models.EventJobFilled, models.EventJobExpired:
+		return EventJobStatusChanged
+	case models.EventNewsPublished:
+		return EventNewsPublished
+	}
+	return ""
+}
+
+// Event is one message on the stream. ID is the outbox event ID.
+type Event struct {
+	ID   int64
+	Type string
+	Data json.RawMessage
+}
+
+// ErrClosed is returned by Subscribe after the broker has shut down
+var ErrClosed = errors.New("stream broker closed")
+
+// Client is one connected subscriber
+type Client struct {
+	events chan Event
+	types  map[string]bool // nil means every type
+}
+
+// Events delivers the client's events. It is closed when the client is
+// unsubscribed, dropped for falling behind, or the broker shuts down.
+func (c *Client) Events() <-chan Event { return c.events }
+
+func (c *Client) wants(eventType string) bool {
+	return c.types == nil || c.types[eventType]
+}
+
+// Broker keeps the replay buffer and the set of connected clients
+type Broker struct {
+	ReplaySize   int // Events kept for Last-Event-ID resume
+	ClientBuffer int // Events queued per client before it is considered too slow
+
+	mu      sync.Mutex
+	clients map[*Client]struct{}
+	replay  []Event
+	closed  bool
+}
+
+// NewBroker creates a Broker with the given replay buffer size
+func NewBroker(replaySize, clientBuffer int) *Broker {
+	return &Broker{
+		ReplaySize:   replaySize,
+		ClientBuffer: clientBuffer,
+		clients:      map[*Client]struct{}{},
+	}
+}
+
+// Subscribe registers a client interested in types (all when empty). With a
+// lastEventID it also returns the buffered events that followed it; resync is true
+// when that ID is no longer in the buffer and the client should refetch.
+func (b *Broker) Subscribe(lastEventID string, types []string) (client *Client, replay []Event, resync bool, err error) {
+	client = &Client{events: make(chan Event, b.ClientBuffer)}
+	if len(types) > 0 {
+		client.types = map[string]bool{}
+		for _, t := range types {
+			client.types[t] = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, false, ErrClosed
+	}
+
+	if lastEventID != "" {
+		resync = true
+		if id, parseErr := strconv.ParseInt(lastEventID, 10, 64); parseErr == nil {
+			// Replay by buffer position, not by ID: commit order can differ from ID order
+			for i := len(b.replay) - 1; i >= 0; i-- {
+				if b.replay[i].ID == id {
+					resync = false
+					for _, ev := range b.replay[i+1:] {
+						if client.wants(ev.Type) {
+							replay = append(replay, ev)
+						}
+					}
+					break
+				}
+			}
+		}
+	}
+
+	b.clients[client] = struct{}{}
+	return client, replay, resync, nil
+}
+
+// Unsubscribe removes a client and closes its channel. It is safe to call more than once.
+func (b *Broker) Unsubscribe(client *Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.clients[client]; ok {
+		delete(b.clients, client)
+		close(client.events)
+	}
+}
+
+// Publish records ev in the replay buffer and queues it for every interested client.
+// Clients whose buffer is full are dropped rather than allowed to block the others.
+func (b *Broker) Publish(ev Event) (dropped int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0
+	}
+
+	for _, seen := range b.replay {
+		if seen.ID == ev.ID {
+			return 0 // Already delivered, e.g. during catch-up after a reconnect
+		}
+	}
+	b.replay = append(b.replay, ev)
+	if len(b.replay) > b.ReplaySize {
+		b.replay = append([]Event(nil), b.replay[len(b.replay)-b.ReplaySize:]...)
+	}
+
+	for client := range b.clients {
+		if !client.wants(ev.Type) {
+			continue
+		}
+		select {
+		case client.events <- ev:
+		default:
+			delete(b.clients, client)
+			close(client.events)
+			dropped++
+		}
+	}
+	return dropped
+}
+
+// ClientCount reports how many clients are connected
+func (b *Broker) ClientCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// Close disconnects every client and refuses new ones. Call it before http.Server.Shutdown,
+// which otherwise waits for long-lived streams to end on their own.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for client := range b.clients {
+		delete(b.clients, client)
+		close(client.events)
+	}
+}
@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"village_project/internal/database"
+	"village_project/internal/models"
+	"village_project/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// NotifyChannel is the Postgres channel the outbox trigger notifies on
+const NotifyChannel = "village_events"
+
+// summaryKeys are the entity fields forwarded to clients; the same list is used by
+// the NOTIFY trigger in migrations/010_outbox_notify.sql.
+var summaryKeys = []string{"id", "title", "status", "location", "payment_details",
+	"published_at", "created_at", "updated_at", "expires_at"}
+
+// notification is the JSON payload sent by the outbox trigger
+type notification struct {
+	ID   int64           `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Listener holds a dedicated connection LISTENing for outbox notifications and
+// publishes them to the Broker. It reconnects with backoff and, after a reconnect,
+// catches up on events committed while it was away by reading the outbox.
+type Listener struct {
+	URL    string // Must be a session connection; transaction poolers drop LISTEN
+	Broker *Broker
+	Outbox *repository.OutboxRepository
+
+	lastID int64
+}
+
+// Run listens until ctx is cancelled
+func (l *Listener) Run(ctx context.Context) {
+	database.Listen(ctx, l.URL, NotifyChannel, func(ctx context.Context, reconnected bool) {
+		// Anything committed between losing the old connection and LISTEN succeeding
+		if reconnected && l.lastID > 0 {
+			l.catchUp(ctx)
+		}
+	}, func(n *pgconn.Notification) {
+		var msg notification
+		if err := json.Unmarshal([]byte(n.Payload), &msg); err != nil {
+			log.Printf("Warning: Stream: ignoring malformed notification: %v", err)
+			return
+		}
+		l.publish(msg.ID, msg.Type, msg.Data)
+	})
+}
+
+// catchUp publishes outbox events newer than the last one seen
+func (l *Listener) catchUp(ctx context.Context) {
+	events, err := l.Outbox.ListSince(ctx, l.lastID, 500)
+	if err != nil {
+		log.Printf("Warning: Stream: catch-up after reconnect failed: %v", err)
+		return
+	}
+	for _, ev := range events {
+		l.publish(ev.ID, ev.EventType, summarize(ev))
+	}
+	if len(events) > 0 {
+		log.Printf("Stream: caught up on %d events after reconnect", len(events))
+	}
+}
+
+func (l *Listener) publish(id int64, outboxType string, data json.RawMessage) {
+	if id > l.lastID {
+		l.lastID = id
+	}
+	eventType := streamType(outboxType)
+	if eventType == "" {
+		return
+	}
+	if dropped := l.Broker.Publish(Event{ID: id, Type: eventType, Data: data}); dropped > 0 {
+		log.Printf("Warning: Stream: dropped %d slow client(s)", dropped)
+	}
+}
+
+// summarize trims a full outbox payload to the fields the trigger would have sent
+func summarize(ev models.OutboxEvent) json.RawMessage {
+	var full map[string]json.RawMessage
+	if err := json.Unmarshal(ev.Payload, &full); err != nil {
+		return json.RawMessage("{}")
+	}
+	for key := range full {
+		if !slices.Contains(summaryKeys, key) {
+			delete(full, key)
+		}
+	}
+	data, _ := json.Marshal(full)
+	return data
+}
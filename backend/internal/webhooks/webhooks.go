@@ -0,0 +1,270 @@
+// Package webhooks delivers domain events to external systems over HTTP.
+//
+// Each delivery is a POST of a JSON envelope {"id", "type", "created_at", "data"} with headers:
+//
+//	X-Webhook-Event:     event type, e.g. job.created
+//	X-Webhook-Delivery:  delivery ID (changes on retry-by-redelivery; "id" in the body does not)
+//	X-Webhook-Timestamp: Unix seconds when the request was signed
+//	X-Webhook-Signature: sha256=<hex HMAC-SHA256(secret, timestamp + "." + body)>
+//
+// Receivers should recompute the signature, reject timestamps older than a few
+// minutes and deduplicate on the envelope id. Any 2xx response counts as delivered.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"village_project/internal/config"
+	"village_project/internal/models"
+)
+
+// Event types that subscriptions can select. They are the outbox's domain events.
+const (
+	EventJobCreated    = models.EventJobCreated
+	EventJobOpened     = models.EventJobOpened
+	EventJobFilled     = models.EventJobFilled
+	EventJobExpired    = models.EventJobExpired
+	EventNewsPublished = models.EventNewsPublished
+	EventPing          = "ping" // Sent on demand to test a subscription; cannot be subscribed to
+)
+
+// EventTypes lists the event types a subscription may select
+var EventTypes = []string{EventJobCreated, EventJobOpened, EventJobFilled, EventJobExpired, EventNewsPublished}
+
+// IsKnownEvent reports whether eventType can be subscribed to
+func IsKnownEvent(eventType string) bool {
+	return slices.Contains(EventTypes, eventType)
+}
+
+// Headers set on every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Envelope is the JSON body of every delivery
+type Envelope struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// Sign returns the X-Webhook-Signature value for a body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrInvalidSignature is returned by Verify for a missing, stale or wrong signature
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Verify checks the signature headers of a received delivery. Receivers written in Go can use it directly.
+func Verify(secret, timestampHeader, signatureHeader string, body []byte, tolerance time.Duration) error {
+	ts, err := strconv.ParseInt(timestampHeader, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrInvalidSignature)
+	}
+	if age := time.Since(time.Unix(ts, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signatureHeader)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// GenerateSecret returns a random 32-byte secret, hex encoded
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newEventID returns a random (version 4) UUID
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// Store keeps the delivery queue; *repository.WebhookRepository implements it
+type Store interface {
+	EnqueueEvent(ctx context.Context, eventID, eventType string, payload []byte, subscriptionID *string) (int, error)
+	ClaimDueDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, id string, responseCode *int, responseBody, attemptErr string, retryAt *time.Time) error
+}
+
+// Dispatcher queues events for matching subscriptions and delivers them in the background.
+// Failed attempts are rescheduled with exponential backoff until MaxAttempts is reached.
+type Dispatcher struct {
+	Repo         Store
+	Client       *http.Client
+	MaxAttempts  int
+	BaseDelay    time.Duration // Delay after the first failure; doubled each time, with jitter
+	MaxDelay     time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+// NewDispatcher builds a Dispatcher from cfg
+func NewDispatcher(cfg config.Config, repo Store) *Dispatcher {
+	return &Dispatcher{
+		Repo:         repo,
+		Client:       &http.Client{Timeout: time.Duration(cfg.WebhookTimeoutSeconds) * time.Second},
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		BaseDelay:    30 * time.Second,
+		MaxDelay:     time.Hour,
+		PollInterval: 5 * time.Second,
+		BatchSize:    20,
+	}
+}
+
+// HandleOutboxEvent queues an outbox event for every active subscription to its type.
+// It is registered with the outbox dispatcher; the outbox event ID becomes the envelope
+// ID, so a repeated call for the same event queues nothing new.
+func (d *Dispatcher) HandleOutboxEvent(ctx context.Context, event models.OutboxEvent) error {
+	_, err := d.enqueue(ctx, event.EventID, event.EventType, event.CreatedAt, event.Payload, nil)
+	return err
+}
+
+// Ping queues a test event for one subscription, whatever event types it selected
+func (d *Dispatcher) Ping(ctx context.Context, subscriptionID string) error {
+	eventID, err := newEventID()
+	if err != nil {
+		return err
+	}
+	data := map[string]string{"message": "Webhook test from the village backend"}
+	_, err = d.enqueue(ctx, eventID, EventPing, time.Now(), data, &subscriptionID)
+	return err
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, eventID, eventType string, createdAt time.Time, data any, subscriptionID *string) (int, error) {
+	payload, err := json.Marshal(Envelope{ID: eventID, Type: eventType, CreatedAt: createdAt.UTC(), Data: data})
+	if err != nil {
+		return 0, err
+	}
+	n, err := d.Repo.EnqueueEvent(ctx, eventID, eventType, payload, subscriptionID)
+	if err == nil && n > 0 {
+		log.Printf("Webhooks: queued %s for %d subscription(s)", eventType, n)
+	}
+	return n, err
+}
+
+// Run delivers queued webhooks until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	log.Printf("Webhooks: dispatcher started (every %s, max %d attempts)", d.PollInterval, d.MaxAttempts)
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			log.Println("Webhooks: dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchDue sends one batch of due deliveries
+func (d *Dispatcher) dispatchDue(ctx context.Context) {
+	batch, err := d.Repo.ClaimDueDeliveries(ctx, d.BatchSize)
+	if err != nil || len(batch) == 0 {
+		return
+	}
+	for _, delivery := range batch {
+		d.attempt(ctx, delivery)
+	}
+}
+
+// attempt makes one delivery attempt and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery models.WebhookDelivery) {
+	code, body, err := d.post(ctx, delivery)
+	if err == nil {
+		d.Repo.RecordAttempt(ctx, delivery.ID, code, body, "", nil)
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	var retryAt *time.Time
+	if attempts < d.MaxAttempts {
+		next := time.Now().Add(d.backoff(attempts))
+		retryAt = &next
+		log.Printf("Warning: Webhooks: delivery %s (%s) failed on attempt %d/%d: %v; retrying at %s",
+			delivery.ID, delivery.EventType, attempts, d.MaxAttempts, err, next.Format(time.RFC3339))
+	} else {
+		log.Printf("ERROR: Webhooks: delivery %s (%s) failed permanently after %d attempts: %v",
+			delivery.ID, delivery.EventType, attempts, err)
+	}
+	d.Repo.RecordAttempt(ctx, delivery.ID, code, body, err.Error(), retryAt)
+}
+
+// backoff returns the delay before the next attempt: BaseDelay * 2^(attempts-1), capped, +/-20% jitter
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := float64(d.BaseDelay) * math.Pow(2, float64(attempts-1))
+	if delay > float64(d.MaxDelay) {
+		delay = float64(d.MaxDelay)
+	}
+	return time.Duration(delay * (0.8 + mathrand.Float64()*0.4))
+}
+
+// post sends the signed request. A non-2xx response is an error; its code and body are still returned.
+func (d *Dispatcher) post(ctx context.Context, delivery models.WebhookDelivery) (*int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, strings.NewReader(string(delivery.Payload)))
+	if err != nil {
+		return nil, "", err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "village-webhooks/1")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	// Postgres text rejects invalid UTF-8 and NUL bytes
+	body := strings.ReplaceAll(strings.ToValidUTF8(string(raw), "\uFFFD"), "\x00", "")
+
+	code := resp.StatusCode
+	if code < 200 || code >= 300 {
+		return &code, body, fmt.Errorf("receiver returned %d", code)
+	}
+	return &code, body, nil
+}
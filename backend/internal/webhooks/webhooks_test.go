@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"village_project/internal/models"
+)
+
+func TestSignVerify(t *testing.T) {
+	const secret = "0123456789abcdef"
+	body := []byte(`{"id":"e1","type":"job.created","data":{}}`)
+	now := time.Now().Unix()
+	ts := strconv.FormatInt(now, 10)
+	sig := Sign(secret, now, body)
+
+	if err := Verify(secret, ts, sig, body, 5*time.Minute); err != nil {
+		t.Fatalf("Verify of a fresh signature: %v", err)
+	}
+
+	stale := now - int64((10 * time.Minute).Seconds())
+	tests := []struct {
+		name            string
+		secret, ts, sig string
+		body            []byte
+	}{
+		{"wrong secret", "another-secret-value", ts, sig, body},
+		{"tampered body", secret, ts, sig, []byte(`{"id":"e2"}`)},
+		{"bad timestamp", secret, "soon", sig, body},
+		{"stale timestamp", secret, strconv.FormatInt(stale, 10), Sign(secret, stale, body), body},
+		{"missing signature", secret, ts, "", body},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.ts, tt.sig, tt.body, 5*time.Minute)
+			if !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("Verify = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+// attempt is one RecordAttempt call seen by fakeStore
+type attempt struct {
+	id      string
+	code    *int
+	err     string
+	retryAt *time.Time
+}
+
+// fakeStore hands out a fixed batch once and records the outcomes
+type fakeStore struct {
+	due      []models.WebhookDelivery
+	attempts []attempt
+}
+
+func (f *fakeStore) EnqueueEvent(ctx context.Context, eventID, eventType string, payload []byte, subscriptionID *string) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeStore) ClaimDueDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error) {
+	batch := f.due
+	f.due = nil
+	return batch, nil
+}
+
+func (f *fakeStore) RecordAttempt(ctx context.Context, id string, responseCode *int, responseBody, attemptErr string, retryAt *time.Time) error {
+	f.attempts = append(f.attempts, attempt{id: id, code: responseCode, err: attemptErr, retryAt: retryAt})
+	return nil
+}
+
+func TestDispatcherDelivers(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"id":"e1","type":"job.created","created_at":"2026-01-01T00:00:00Z","data":{}}`)
+
+	status := http.StatusOK
+	var got *http.Request
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	delivery := models.WebhookDelivery{
+		ID: "d1", EventType: EventJobCreated, Payload: payload, URL: srv.URL, Secret: secret,
+	}
+	store := &fakeStore{}
+	d := &Dispatcher{
+		Repo: store, Client: srv.Client(), MaxAttempts: 3,
+		BaseDelay: time.Minute, MaxDelay: time.Hour, BatchSize: 10,
+	}
+
+	store.due = []models.WebhookDelivery{delivery}
+	d.dispatchDue(context.Background())
+	if got == nil {
+		t.Fatal("receiver was not called")
+	}
+	if got.Header.Get(HeaderEvent) != EventJobCreated || got.Header.Get(HeaderDelivery) != "d1" {
+		t.Errorf("event headers = %q, %q", got.Header.Get(HeaderEvent), got.Header.Get(HeaderDelivery))
+	}
+	if err := Verify(secret, got.Header.Get(HeaderTimestamp), got.Header.Get(HeaderSignature), gotBody, time.Minute); err != nil {
+		t.Errorf("receiver could not verify the delivery: %v", err)
+	}
+	if len(store.attempts) != 1 || store.attempts[0].err != "" || store.attempts[0].retryAt != nil {
+		t.Fatalf("attempts after success = %+v", store.attempts)
+	}
+
+	status = http.StatusServiceUnavailable
+	store.attempts = nil
+	store.due = []models.WebhookDelivery{delivery}
+	before := time.Now()
+	d.dispatchDue(context.Background())
+	if len(store.attempts) != 1 {
+		t.Fatalf("attempts after failure = %+v", store.attempts)
+	}
+	a := store.attempts[0]
+	if a.err == "" || a.code == nil || *a.code != http.StatusServiceUnavailable {
+		t.Errorf("failed attempt recorded as %+v", a)
+	}
+	if a.retryAt == nil || a.retryAt.Before(before.Add(40*time.Second)) {
+		t.Errorf("retryAt = %v, want about a minute after the attempt", a.retryAt)
+	}
+
+	// The last allowed attempt is not retried
+	store.attempts = nil
+	delivery.Attempts = 2
+	store.due = []models.WebhookDelivery{delivery}
+	d.dispatchDue(context.Background())
+	if len(store.attempts) != 1 || store.attempts[0].retryAt != nil {
+		t.Errorf("final attempt recorded as %+v, want no retry", store.attempts)
+	}
+}
@@ -0,0 +1,58 @@
+-- Grievance / complaint tracking
+-- Apply in the Supabase SQL editor (or psql) before deploying the grievance routes.
+
+CREATE TABLE IF NOT EXISTS public.grievance_categories (
+    code                  text PRIMARY KEY,                -- e.g. 'streetlight', 'drainage', 'water'
+    name                  text NOT NULL,
+    sla_hours             integer NOT NULL CHECK (sla_hours > 0),
+    default_assignee_id   uuid,                            -- Official who receives new tickets
+    created_at            timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO public.grievance_categories (code, name, sla_hours) VALUES
+    ('streetlight', 'Streetlight not working', 72),
+    ('drainage',    'Drainage blockage',       48),
+    ('water',       'Drinking water supply',   24),
+    ('roads',       'Road damage',             168),
+    ('sanitation',  'Garbage and sanitation',  48),
+    ('other',       'Other',                   120)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE SEQUENCE IF NOT EXISTS public.grievance_ticket_seq;
+
+CREATE TABLE IF NOT EXISTS public.grievances (
+    id                uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_number     text NOT NULL UNIQUE
+                      DEFAULT 'GRV-' || lpad(nextval('public.grievance_ticket_seq')::text, 6, '0'),
+    created_at        timestamptz NOT NULL DEFAULT now(),
+    updated_at        timestamptz NOT NULL DEFAULT now(),
+    category          text NOT NULL REFERENCES public.grievance_categories(code),
+    ward              text NOT NULL,
+    description       text NOT NULL,
+    photo_url         text,
+    reporter_name     text NOT NULL,
+    reporter_phone    text,
+    reporter_user_id  uuid,
+    assigned_to       uuid,
+    status            text NOT NULL DEFAULT 'New'
+                      CHECK (status IN ('New', 'Acknowledged', 'In Progress', 'Resolved', 'Rejected')),
+    sla_due_at        timestamptz NOT NULL,
+    resolved_at       timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS grievances_status_idx ON public.grievances (status, sla_due_at);
+CREATE INDEX IF NOT EXISTS grievances_assigned_idx ON public.grievances (assigned_to) WHERE assigned_to IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.grievance_comments (
+    id              uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    grievance_id    uuid NOT NULL REFERENCES public.grievances(id) ON DELETE CASCADE,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    author_user_id  uuid,
+    author_name     text NOT NULL,
+    body            text NOT NULL,
+    status_from     text,                                  -- Set when the comment records a status change
+    status_to       text,
+    is_internal     boolean NOT NULL DEFAULT false         -- Internal notes are hidden from public tracking
+);
+
+CREATE INDEX IF NOT EXISTS grievance_comments_grievance_idx ON public.grievance_comments (grievance_id, created_at);
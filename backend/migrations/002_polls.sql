@@ -0,0 +1,42 @@
+-- Community polls and surveys
+
+CREATE TABLE IF NOT EXISTS public.polls (
+    id                  uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at          timestamptz NOT NULL DEFAULT now(),
+    updated_at          timestamptz NOT NULL DEFAULT now(),
+    question            text NOT NULL,
+    description         text,
+    kind                text NOT NULL DEFAULT 'single' CHECK (kind IN ('single', 'multiple')),
+    max_choices         integer,                           -- Only for 'multiple'; NULL means no limit
+    anonymous           boolean NOT NULL DEFAULT false,    -- true: one vote per device token instead of per user
+    results_visibility  text NOT NULL DEFAULT 'after_vote' CHECK (results_visibility IN ('after_vote', 'after_close')),
+    opens_at            timestamptz NOT NULL DEFAULT now(),
+    closes_at           timestamptz NOT NULL,
+    created_by_user_id  uuid,
+    CHECK (closes_at > opens_at)
+);
+
+CREATE TABLE IF NOT EXISTS public.poll_options (
+    id        uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    poll_id   uuid NOT NULL REFERENCES public.polls(id) ON DELETE CASCADE,
+    label     text NOT NULL,
+    position  integer NOT NULL,
+    UNIQUE (poll_id, position)
+);
+
+-- One ballot per voter per poll. voter_key is the user ID, or a hash of the device token for anonymous polls.
+CREATE TABLE IF NOT EXISTS public.poll_ballots (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    poll_id     uuid NOT NULL REFERENCES public.polls(id) ON DELETE CASCADE,
+    voter_key   text NOT NULL,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (poll_id, voter_key)
+);
+
+CREATE TABLE IF NOT EXISTS public.poll_votes (
+    ballot_id  uuid NOT NULL REFERENCES public.poll_ballots(id) ON DELETE CASCADE,
+    option_id  uuid NOT NULL REFERENCES public.poll_options(id) ON DELETE CASCADE,
+    PRIMARY KEY (ballot_id, option_id)
+);
+
+CREATE INDEX IF NOT EXISTS poll_votes_option_idx ON public.poll_votes (option_id);
@@ -0,0 +1,22 @@
+-- Job applications: residents express interest, posters review them
+
+ALTER TABLE public.jobs
+    ADD COLUMN IF NOT EXISTS positions_needed integer NOT NULL DEFAULT 1 CHECK (positions_needed > 0),
+    ADD COLUMN IF NOT EXISTS auto_fill_on_hire boolean NOT NULL DEFAULT false;  -- Move to 'Filled' once enough are hired
+
+CREATE TABLE IF NOT EXISTS public.job_applications (
+    id                 uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id             uuid NOT NULL REFERENCES public.jobs(id) ON DELETE CASCADE,
+    created_at         timestamptz NOT NULL DEFAULT now(),
+    updated_at         timestamptz NOT NULL DEFAULT now(),
+    applicant_name     text NOT NULL,
+    applicant_phone    text NOT NULL,
+    note               text,
+    availability       text,
+    applicant_user_id  uuid,
+    status             text NOT NULL DEFAULT 'Applied'
+                       CHECK (status IN ('Applied', 'Shortlisted', 'Hired', 'Declined')),
+    UNIQUE (job_id, applicant_phone)                      -- One application per phone number per job
+);
+
+CREATE INDEX IF NOT EXISTS job_applications_job_idx ON public.job_applications (job_id, status);
@@ -0,0 +1,30 @@
+-- Saved job alerts and their notification queue
+
+CREATE TABLE IF NOT EXISTS public.job_alerts (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     uuid NOT NULL,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    keywords    text[] NOT NULL DEFAULT '{}',  -- Any keyword may match; empty matches every job
+    location    text,                          -- Substring matched against jobs.location
+    min_pay     integer,                       -- Rupees, compared with the amount parsed from payment_details
+    paused      boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS job_alerts_active_idx ON public.job_alerts (user_id) WHERE NOT paused;
+
+-- One row per subscriber per job: the unique constraint suppresses duplicate
+-- notifications when several of a user's alerts match the same job.
+CREATE TABLE IF NOT EXISTS public.job_alert_notifications (
+    id          uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id    uuid NOT NULL REFERENCES public.job_alerts(id) ON DELETE CASCADE,
+    job_id      uuid NOT NULL REFERENCES public.jobs(id) ON DELETE CASCADE,
+    user_id     uuid NOT NULL,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    status      text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    sent_at     timestamptz,
+    UNIQUE (user_id, job_id)
+);
+
+CREATE INDEX IF NOT EXISTS job_alert_notifications_pending_idx
+    ON public.job_alert_notifications (created_at) WHERE status = 'pending';
@@ -0,0 +1,9 @@
+-- Track in-flight delivery of job alert notifications so several server
+-- instances can drain the queue without sending the same message twice.
+
+ALTER TABLE public.job_alert_notifications
+    DROP CONSTRAINT IF EXISTS job_alert_notifications_status_check,
+    ADD CONSTRAINT job_alert_notifications_status_check
+        CHECK (status IN ('pending', 'sending', 'sent', 'failed')),
+    ADD COLUMN IF NOT EXISTS claimed_at timestamptz,
+    ADD COLUMN IF NOT EXISTS last_error text;
@@ -0,0 +1,23 @@
+-- Outgoing SMS log: one row per message, updated by provider delivery callbacks
+
+CREATE TABLE IF NOT EXISTS public.sms_messages (
+    id                   uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at           timestamptz NOT NULL DEFAULT now(),
+    updated_at           timestamptz NOT NULL DEFAULT now(),
+    recipient            text NOT NULL,                 -- E.164, e.g. +919876543210
+    event                text NOT NULL,
+    body                 text NOT NULL,
+    encoding             text NOT NULL CHECK (encoding IN ('GSM-7', 'UCS-2')),
+    segments             integer NOT NULL,
+    dlt_template_id      text,
+    provider             text NOT NULL,
+    provider_message_id  text,
+    status               text NOT NULL DEFAULT 'queued'
+                         CHECK (status IN ('queued', 'submitted', 'sent', 'delivered', 'failed', 'capped')),
+    status_detail        text,
+    delivered_at         timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS sms_messages_recipient_idx ON public.sms_messages (recipient, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS sms_messages_provider_id_idx
+    ON public.sms_messages (provider, provider_message_id) WHERE provider_message_id IS NOT NULL;
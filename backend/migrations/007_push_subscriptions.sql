@@ -0,0 +1,20 @@
+-- Web Push subscriptions, registered by signed-in users or anonymous devices (X-Device-Token)
+
+CREATE TABLE IF NOT EXISTS public.push_subscriptions (
+    id               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    updated_at       timestamptz NOT NULL DEFAULT now(),
+    user_id          uuid REFERENCES auth.users (id) ON DELETE CASCADE,
+    device_key       text,                          -- sha256 of the device token
+    endpoint         text NOT NULL UNIQUE,
+    p256dh           text NOT NULL,                 -- Browser public key (base64url, uncompressed P-256)
+    auth_secret      text NOT NULL,                 -- 16-byte auth secret (base64url)
+    user_agent       text,
+    expires_at       timestamptz,                   -- From PushSubscription.expirationTime, if the browser sets one
+    last_success_at  timestamptz,
+    failure_count    integer NOT NULL DEFAULT 0,
+    CHECK (user_id IS NOT NULL OR device_key IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS push_subscriptions_user_idx ON public.push_subscriptions (user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS push_subscriptions_device_idx ON public.push_subscriptions (device_key) WHERE device_key IS NOT NULL;
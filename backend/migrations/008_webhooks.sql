@@ -0,0 +1,37 @@
+-- Outbound webhooks: admin-managed subscriptions and a delivery log that doubles as the retry queue
+
+CREATE TABLE IF NOT EXISTS public.webhook_subscriptions (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now(),
+    name         text NOT NULL,
+    url          text NOT NULL,
+    event_types  text[] NOT NULL CHECK (cardinality(event_types) > 0),
+    secret       text NOT NULL,                 -- HMAC-SHA256 key shared with the receiver
+    active       boolean NOT NULL DEFAULT true,
+    created_by   uuid REFERENCES auth.users (id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+    id               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    subscription_id  uuid NOT NULL REFERENCES public.webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id         uuid NOT NULL,                 -- Same for every delivery of one event, so receivers can dedupe
+    event_type       text NOT NULL,
+    payload          jsonb NOT NULL,
+    status           text NOT NULL DEFAULT 'pending'
+                     CHECK (status IN ('pending', 'sending', 'succeeded', 'failed')),
+    attempts         integer NOT NULL DEFAULT 0,
+    next_attempt_at  timestamptz NOT NULL DEFAULT now(),
+    claimed_at       timestamptz,
+    last_attempt_at  timestamptz,
+    response_code    integer,
+    response_body    text,                          -- First 2 KB of the receiver's response
+    last_error       text,
+    delivered_at     timestamptz,
+    redelivery_of    uuid REFERENCES public.webhook_deliveries (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due_idx ON public.webhook_deliveries (next_attempt_at)
+    WHERE status IN ('pending', 'sending');
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_idx ON public.webhook_deliveries (subscription_id, created_at DESC);
@@ -0,0 +1,25 @@
+-- Transactional outbox: domain events written in the same transaction as the change that caused them
+
+CREATE TABLE IF NOT EXISTS public.outbox_events (
+    id                  bigint GENERATED ALWAYS AS IDENTITY PRIMARY KEY,  -- Delivery order
+    event_id            uuid NOT NULL DEFAULT gen_random_uuid() UNIQUE,   -- Stable ID handed to consumers for dedupe
+    created_at          timestamptz NOT NULL DEFAULT now(),
+    aggregate_type      text NOT NULL,                                    -- job, news, ...
+    aggregate_id        text NOT NULL,
+    event_type          text NOT NULL,                                    -- job.created, news.published, ...
+    payload             jsonb NOT NULL,
+    status              text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'dead')),
+    attempts            integer NOT NULL DEFAULT 0,
+    next_attempt_at     timestamptz NOT NULL DEFAULT now(),
+    locked_until        timestamptz,                                      -- Lease held by the dispatcher processing the event
+    completed_handlers  text[] NOT NULL DEFAULT '{}',                     -- Handlers that already succeeded are skipped on retry
+    last_error          text,
+    processed_at        timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_due_idx ON public.outbox_events (next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS outbox_events_aggregate_idx ON public.outbox_events (aggregate_type, aggregate_id);
+
+-- Outbox handlers run at least once; a repeated webhook enqueue for the same event is ignored
+CREATE UNIQUE INDEX IF NOT EXISTS webhook_deliveries_event_once_idx
+    ON public.webhook_deliveries (subscription_id, event_id) WHERE redelivery_of IS NULL;
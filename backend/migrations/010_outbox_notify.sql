@@ -0,0 +1,28 @@
+-- Announce every committed domain event on the village_events channel for live streams.
+-- NOTIFY is transactional, so listeners only hear about events whose transaction committed.
+-- The payload is trimmed to summary fields to stay well under the 8000-byte NOTIFY limit.
+
+CREATE OR REPLACE FUNCTION public.notify_outbox_event() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM pg_notify('village_events', json_build_object(
+        'id', NEW.id,
+        'type', NEW.event_type,
+        'aggregate_type', NEW.aggregate_type,
+        'aggregate_id', NEW.aggregate_id,
+        'created_at', NEW.created_at,
+        'data', (
+            SELECT coalesce(jsonb_object_agg(key, value), '{}'::jsonb)
+            FROM jsonb_each(NEW.payload)
+            WHERE key IN ('id', 'title', 'status', 'location', 'payment_details',
+                          'published_at', 'created_at', 'updated_at', 'expires_at')
+        )
+    )::text);
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS outbox_events_notify ON public.outbox_events;
+CREATE TRIGGER outbox_events_notify
+    AFTER INSERT ON public.outbox_events
+    FOR EACH ROW EXECUTE FUNCTION public.notify_outbox_event();
@@ -0,0 +1,31 @@
+-- Bilingual content: each news item and job keeps its original text plus optional
+-- translations, one row per locale. Editors add translations without touching the original.
+
+ALTER TABLE public.news ADD COLUMN IF NOT EXISTS language text NOT NULL DEFAULT 'en'
+    CHECK (language ~ '^[a-z]{2,3}$');                                  -- Locale the original was written in
+ALTER TABLE public.jobs ADD COLUMN IF NOT EXISTS language text NOT NULL DEFAULT 'en'
+    CHECK (language ~ '^[a-z]{2,3}$');
+
+CREATE TABLE IF NOT EXISTS public.news_translations (
+    news_id     uuid NOT NULL REFERENCES public.news (id) ON DELETE CASCADE,
+    locale      text NOT NULL CHECK (locale ~ '^[a-z]{2,3}$'),
+    title       text NOT NULL,
+    content     text,
+    created_at  timestamptz NOT NULL DEFAULT now(),
+    updated_at  timestamptz NOT NULL DEFAULT now(),
+    updated_by  uuid REFERENCES auth.users (id) ON DELETE SET NULL,      -- Editor who last changed it
+    PRIMARY KEY (news_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS public.job_translations (
+    job_id           uuid NOT NULL REFERENCES public.jobs (id) ON DELETE CASCADE,
+    locale           text NOT NULL CHECK (locale ~ '^[a-z]{2,3}$'),
+    title            text NOT NULL,
+    description      text NOT NULL,
+    location         text,                                               -- NULL keeps the original's value
+    payment_details  text,
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    updated_at       timestamptz NOT NULL DEFAULT now(),
+    updated_by       uuid REFERENCES auth.users (id) ON DELETE SET NULL,
+    PRIMARY KEY (job_id, locale)
+);
@@ -0,0 +1,8 @@
+-- Search keys for jobs and news, computed by internal/search from the original text and
+-- every translation. Backfill existing rows with: go run ./cmd/searchindex -reindex
+
+ALTER TABLE public.jobs ADD COLUMN IF NOT EXISTS search_terms text[] NOT NULL DEFAULT '{}';
+ALTER TABLE public.news ADD COLUMN IF NOT EXISTS search_terms text[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS jobs_search_terms_idx ON public.jobs USING gin (search_terms);
+CREATE INDEX IF NOT EXISTS news_search_terms_idx ON public.news USING gin (search_terms);
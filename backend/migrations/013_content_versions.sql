@@ -0,0 +1,38 @@
+-- Collection versions for conditional GETs: any write to a collection's tables bumps its
+-- version, so list ETags can be checked with one primary-key lookup.
+
+CREATE TABLE IF NOT EXISTS public.content_versions (
+    collection  text PRIMARY KEY,                   -- news, jobs
+    version     bigint NOT NULL DEFAULT 0,
+    changed_at  timestamptz NOT NULL DEFAULT now()
+);
+
+INSERT INTO public.content_versions (collection) VALUES ('news'), ('jobs')
+ON CONFLICT (collection) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION public.bump_content_version() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    -- clock_timestamp, not now(): a long transaction must not move changed_at backwards
+    UPDATE public.content_versions
+    SET version = version + 1, changed_at = greatest(changed_at, clock_timestamp())
+    WHERE collection = TG_ARGV[0];
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS news_content_version ON public.news;
+CREATE TRIGGER news_content_version AFTER INSERT OR UPDATE OR DELETE ON public.news
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_content_version('news');
+
+DROP TRIGGER IF EXISTS news_translations_content_version ON public.news_translations;
+CREATE TRIGGER news_translations_content_version AFTER INSERT OR UPDATE OR DELETE ON public.news_translations
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_content_version('news');
+
+DROP TRIGGER IF EXISTS jobs_content_version ON public.jobs;
+CREATE TRIGGER jobs_content_version AFTER INSERT OR UPDATE OR DELETE ON public.jobs
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_content_version('jobs');
+
+DROP TRIGGER IF EXISTS job_translations_content_version ON public.job_translations;
+CREATE TRIGGER job_translations_content_version AFTER INSERT OR UPDATE OR DELETE ON public.job_translations
+    FOR EACH STATEMENT EXECUTE FUNCTION public.bump_content_version('jobs');
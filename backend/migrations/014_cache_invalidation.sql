@@ -0,0 +1,15 @@
+-- Announce collection writes on the village_cache channel so every API instance can drop
+-- its in-process read cache. NOTIFY is delivered on commit and identical payloads within
+-- one transaction are folded, so a multi-statement write costs one notification.
+
+CREATE OR REPLACE FUNCTION public.bump_content_version() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    -- clock_timestamp, not now(): a long transaction must not move changed_at backwards
+    UPDATE public.content_versions
+    SET version = version + 1, changed_at = greatest(changed_at, clock_timestamp())
+    WHERE collection = TG_ARGV[0];
+    PERFORM pg_notify('village_cache', TG_ARGV[0]);
+    RETURN NULL;
+END;
+$$;
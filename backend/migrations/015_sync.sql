@@ -0,0 +1,60 @@
+-- Delta sync for offline clients (GET /api/v1/sync). News and jobs are soft deleted so a
+-- deletion can be sent as a tombstone, and every write stamps the row with a position in
+-- one global change sequence plus the writing transaction's ID.
+--
+-- Sequence values are handed out before commit, so they are not in commit order and
+-- cannot be the sync cursor on their own. Tokens therefore hold the snapshot xmin of the
+-- previous sync: every transaction below it had finished and was seen, so the next sync
+-- returns rows whose change_xid is at or above it (repeats are harmless upserts).
+-- change_seq orders the changes and pages through them.
+
+CREATE SEQUENCE IF NOT EXISTS public.change_seq;
+
+ALTER TABLE public.news ADD COLUMN IF NOT EXISTS deleted_at timestamptz;             -- Soft delete; NULL while live
+ALTER TABLE public.news ADD COLUMN IF NOT EXISTS change_seq bigint NOT NULL DEFAULT nextval('public.change_seq');
+ALTER TABLE public.news ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT '0'; -- Transaction that last wrote the row
+
+ALTER TABLE public.jobs ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+ALTER TABLE public.jobs ADD COLUMN IF NOT EXISTS change_seq bigint NOT NULL DEFAULT nextval('public.change_seq');
+ALTER TABLE public.jobs ADD COLUMN IF NOT EXISTS change_xid xid8 NOT NULL DEFAULT '0';
+
+CREATE INDEX IF NOT EXISTS news_change_seq_idx ON public.news (change_seq);
+CREATE INDEX IF NOT EXISTS jobs_change_seq_idx ON public.jobs (change_seq);
+
+CREATE OR REPLACE FUNCTION public.stamp_change() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    NEW.change_seq := nextval('public.change_seq');
+    NEW.change_xid := pg_current_xact_id();
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS news_stamp_change ON public.news;
+CREATE TRIGGER news_stamp_change BEFORE INSERT OR UPDATE ON public.news
+    FOR EACH ROW EXECUTE FUNCTION public.stamp_change();
+
+DROP TRIGGER IF EXISTS jobs_stamp_change ON public.jobs;
+CREATE TRIGGER jobs_stamp_change BEFORE INSERT OR UPDATE ON public.jobs
+    FOR EACH ROW EXECUTE FUNCTION public.stamp_change();
+
+-- A translation edit changes what clients display, so it re-stamps the parent row.
+-- TG_ARGV: parent table, foreign key column.
+CREATE OR REPLACE FUNCTION public.touch_translated_parent() RETURNS trigger
+LANGUAGE plpgsql AS $$
+DECLARE
+    changed jsonb := CASE WHEN TG_OP = 'DELETE' THEN to_jsonb(OLD) ELSE to_jsonb(NEW) END;
+BEGIN
+    EXECUTE format('UPDATE public.%I SET change_seq = change_seq WHERE id = $1::uuid', TG_ARGV[0])
+    USING changed ->> TG_ARGV[1];
+    RETURN NULL;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS news_translations_touch_parent ON public.news_translations;
+CREATE TRIGGER news_translations_touch_parent AFTER INSERT OR UPDATE OR DELETE ON public.news_translations
+    FOR EACH ROW EXECUTE FUNCTION public.touch_translated_parent('news', 'news_id');
+
+DROP TRIGGER IF EXISTS job_translations_touch_parent ON public.job_translations;
+CREATE TRIGGER job_translations_touch_parent AFTER INSERT OR UPDATE OR DELETE ON public.job_translations
+    FOR EACH ROW EXECUTE FUNCTION public.touch_translated_parent('jobs', 'job_id');
@@ -0,0 +1,6 @@
+-- News archive by month: both archive queries (counts per IST month, and one month's
+-- items by published_at range) read only published, live rows, so a partial index on
+-- published_at serves them (the counts usually as an index-only scan).
+
+CREATE INDEX IF NOT EXISTS news_published_at_idx ON public.news (published_at DESC)
+    WHERE status = 'published' AND deleted_at IS NULL;
@@ -0,0 +1,21 @@
+-- Trash: deleted news and jobs stay restorable (deleted_at from 015, plus who deleted
+-- them) until TRASH_RETENTION_DAYS have passed, then are purged for good.
+--
+-- A purged row can no longer be sent to syncing clients as a tombstone. Each purge
+-- therefore records the newest change_xid it removed; a sync token from before that
+-- point may have missed the deletion, so the sync endpoint answers it with a full
+-- snapshot instead (see SyncRepository.Changes).
+
+ALTER TABLE public.news ADD COLUMN IF NOT EXISTS deleted_by uuid; -- User who deleted the row; NULL while live or if unknown
+ALTER TABLE public.jobs ADD COLUMN IF NOT EXISTS deleted_by uuid;
+
+CREATE INDEX IF NOT EXISTS news_trash_idx ON public.news (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS jobs_trash_idx ON public.jobs (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.trash_purges (
+    id              bigserial PRIMARY KEY,
+    purged_at       timestamptz NOT NULL DEFAULT now(),
+    collection      text NOT NULL,   -- news, jobs
+    purged          integer NOT NULL, -- Rows removed
+    max_change_xid  xid8 NOT NULL     -- Newest change_xid among them
+);
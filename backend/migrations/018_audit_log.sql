@@ -0,0 +1,39 @@
+-- Audit log of changes made through the API (see package audit). Entries are chained:
+-- hash = sha256 over the entry's fields and prev_hash, the previous entry's hash, so an
+-- edited, inserted or deleted row breaks every later link (go run ./cmd/audit -verify).
+-- The trigger below stops accidental edits; the chain catches deliberate ones.
+
+CREATE TABLE IF NOT EXISTS public.audit_log (
+    id           bigserial PRIMARY KEY,
+    created_at   timestamptz NOT NULL,
+    actor_id     uuid,                      -- NULL for anonymous requests
+    actor_role   text NOT NULL,
+    action       text NOT NULL,             -- Handler name, e.g. PublishNews
+    target_type  text NOT NULL DEFAULT '',
+    target_id    text NOT NULL DEFAULT '',
+    diff         jsonb,                     -- {"field": {"before": ..., "after": ...}}
+    ip           text NOT NULL DEFAULT '',
+    request_id   text NOT NULL DEFAULT '',
+    prev_hash    text NOT NULL,             -- '' for the first entry
+    hash         text NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON public.audit_log (actor_id, id DESC);
+CREATE INDEX IF NOT EXISTS audit_log_target_idx ON public.audit_log (target_type, target_id, id DESC);
+CREATE INDEX IF NOT EXISTS audit_log_action_idx ON public.audit_log (action, id DESC);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON public.audit_log (created_at);
+
+CREATE OR REPLACE FUNCTION public.audit_log_append_only() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON public.audit_log;
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON public.audit_log
+    FOR EACH ROW EXECUTE FUNCTION public.audit_log_append_only();
+
+DROP TRIGGER IF EXISTS audit_log_no_truncate ON public.audit_log;
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON public.audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION public.audit_log_append_only();
@@ -0,0 +1,25 @@
+-- Per-recipient queue for news announcements. The news.published outbox handler only
+-- fans out into this table; Service.Run delivers each row and a failure affects one
+-- recipient instead of the whole broadcast.
+
+CREATE TABLE IF NOT EXISTS public.news_notifications (
+    id               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    news_id          uuid NOT NULL REFERENCES public.news(id) ON DELETE CASCADE,
+    user_id          uuid REFERENCES auth.users (id) ON DELETE CASCADE,                  -- Email to a resident
+    subscription_id  uuid REFERENCES public.push_subscriptions (id) ON DELETE CASCADE,   -- Web Push to one browser
+    created_at       timestamptz NOT NULL DEFAULT now(),
+    status           text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sending', 'sent', 'failed')),
+    claimed_at       timestamptz,
+    last_error       text,
+    sent_at          timestamptz,
+    CHECK ((user_id IS NULL) <> (subscription_id IS NULL))
+);
+
+-- The outbox delivers at least once; a repeated fan-out for the same news item is ignored
+CREATE UNIQUE INDEX IF NOT EXISTS news_notifications_email_once_idx
+    ON public.news_notifications (news_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS news_notifications_push_once_idx
+    ON public.news_notifications (news_id, subscription_id) WHERE subscription_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS news_notifications_pending_idx
+    ON public.news_notifications (created_at) WHERE status IN ('pending', 'sending');
@@ -0,0 +1,28 @@
+-- Cap the free-text summary fields in village_events payloads. Job titles, locations and
+-- pay notes can be long, and Telugu takes 3 bytes a character in UTF-8; a payload over
+-- 8000 bytes makes pg_notify fail and with it the transaction that wrote the event.
+-- Each string is cut to 300 characters, so the payload stays well under the limit;
+-- stream clients fetch the full record when they need it.
+
+CREATE OR REPLACE FUNCTION public.notify_outbox_event() RETURNS trigger
+LANGUAGE plpgsql AS $$
+BEGIN
+    PERFORM pg_notify('village_events', json_build_object(
+        'id', NEW.id,
+        'type', NEW.event_type,
+        'aggregate_type', NEW.aggregate_type,
+        'aggregate_id', NEW.aggregate_id,
+        'created_at', NEW.created_at,
+        'data', (
+            SELECT coalesce(jsonb_object_agg(key, CASE jsonb_typeof(value)
+                       WHEN 'string' THEN to_jsonb(left(value #>> '{}', 300))
+                       ELSE value
+                   END), '{}'::jsonb)
+            FROM jsonb_each(NEW.payload)
+            WHERE key IN ('id', 'title', 'status', 'location', 'payment_details',
+                          'published_at', 'created_at', 'updated_at', 'expires_at')
+        )
+    )::text);
+    RETURN NEW;
+END;
+$$;
@@ -0,0 +1,6 @@
+-- The audit log's ip is the client address as reported through X-Forwarded-For, which
+-- a client can set itself when the server trusts any proxy that forwards it.
+-- remote_ip is the address of the TCP peer, which the client cannot choose. Entries
+-- written before this column existed keep '' and hash exactly as before.
+
+ALTER TABLE public.audit_log ADD COLUMN IF NOT EXISTS remote_ip text NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Deliveries queued for a subscription that was deactivated before they were sent are
+-- marked cancelled instead of being claimed again on every poll. Pings and redeliveries
+-- an admin asks for still go out to an inactive subscription.
+
+ALTER TABLE public.webhook_deliveries DROP CONSTRAINT IF EXISTS webhook_deliveries_status_check;
+ALTER TABLE public.webhook_deliveries ADD CONSTRAINT webhook_deliveries_status_check
+    CHECK (status IN ('pending', 'sending', 'succeeded', 'failed', 'cancelled'));